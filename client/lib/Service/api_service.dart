@@ -789,26 +789,36 @@ class ApiService {
   static Future<SettleResult> getSettlementHistory({
     required String groupId,
   }) async {
-    final url = Uri.parse("${ApiConfig.baseUrl}/groups/$groupId/settlements");
+    final settlements = <Settlement>[];
+    String? cursor;
 
     try {
-      final response = await _authenticatedRequest(method: "GET", url: url);
-
-      if (response.statusCode == 200) {
-        final List data = jsonDecode(response.body);
-        final settlements = data.map((e) => Settlement.fromJson(e)).toList();
-        return SettleResult.success(settlements);
-      }
+      while (true) {
+        final url = Uri.parse(
+          "${ApiConfig.baseUrl}/groups/$groupId/settlements",
+        ).replace(queryParameters: cursor == null ? null : {"cursor": cursor});
+        final response = await _authenticatedRequest(method: "GET", url: url);
+
+        if (response.statusCode != 200) {
+          if (response.statusCode == 401)
+            return SettleResult.error("Session expired");
+          if (response.statusCode == 403)
+            return SettleResult.error("Not group member");
+          if (response.statusCode == 404)
+            return SettleResult.error("Group not found");
+          if (response.statusCode == 500)
+            return SettleResult.error("Server error");
+
+          return SettleResult.error("Unexpected error");
+        }
 
-      if (response.statusCode == 401)
-        return SettleResult.error("Session expired");
-      if (response.statusCode == 403)
-        return SettleResult.error("Not group member");
-      if (response.statusCode == 404)
-        return SettleResult.error("Group not found");
-      if (response.statusCode == 500) return SettleResult.error("Server error");
+        final data = jsonDecode(response.body);
+        final List items = data["items"];
+        settlements.addAll(items.map((e) => Settlement.fromJson(e)));
 
-      return SettleResult.error("Unexpected error");
+        cursor = data["next_cursor"];
+        if (cursor == null) return SettleResult.success(settlements);
+      }
     } catch (e) {
       return SettleResult.error(e.toString());
     }
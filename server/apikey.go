@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
+
+	"github.com/google/uuid"
+)
+
+const apiKeyUsage = `usage:
+  qashare apikey create -name NAME -scopes SCOPE[,SCOPE...] [-expires DURATION]
+  qashare apikey revoke KEY_ID`
+
+// runAPIKeyCommand provisions and revokes API keys from the command line.
+// It uses the same configuration as the server, so it runs against the configured database.
+// The plaintext key of a new API key is printed to stdout once and cannot be recovered later.
+func runAPIKeyCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New(apiKeyUsage)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	utils.InitLogger(cfg)
+
+	switch args[0] {
+	case "create":
+		return createAPIKey(cfg.Database, args[1:])
+	case "revoke":
+		return revokeAPIKey(cfg.Database, args[1:])
+	default:
+		return fmt.Errorf("unknown apikey command %q\n%s", args[0], apiKeyUsage)
+	}
+}
+
+func createAPIKey(dbConfig config.DatabaseConfig, args []string) error {
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	name := fs.String("name", "", "name identifying the integration using the key")
+	scopeList := fs.String("scopes", "", "comma-separated scopes to grant")
+	expires := fs.Duration("expires", 0, "lifetime of the key, e.g. 720h (default: never expires)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	scopes, err := parseAPIKeyScopes(*scopeList)
+	if err != nil {
+		return err
+	}
+	var expiresAt *time.Time
+	if *expires > 0 {
+		t := time.Now().Add(*expires)
+		expiresAt = &t
+	}
+
+	pool, err := initDatabase(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close(pool)
+
+	key, apiKey, err := db.CreateAPIKey(context.Background(), pool, strings.TrimSpace(*name), scopes, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Created API key %s (%s). Store it now, it is not shown again.\n", apiKey.KeyID, apiKey.Name)
+	fmt.Println(key)
+	return nil
+}
+
+func revokeAPIKey(dbConfig config.DatabaseConfig, args []string) error {
+	if len(args) != 1 {
+		return errors.New(apiKeyUsage)
+	}
+	keyID, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid key id %q: %w", args[0], err)
+	}
+
+	pool, err := initDatabase(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close(pool)
+
+	if err := db.RevokeAPIKey(context.Background(), pool, keyID); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Revoked API key %s\n", keyID)
+	return nil
+}
+
+// parseAPIKeyScopes splits a comma-separated scope list and rejects unknown scopes.
+func parseAPIKeyScopes(list string) ([]models.APIKeyScope, error) {
+	var scopes []models.APIKeyScope
+	for _, s := range strings.Split(list, ",") {
+		scope := models.APIKeyScope(strings.TrimSpace(s))
+		if scope == "" {
+			continue
+		}
+		if !slices.Contains(models.APIKeyScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q (valid scopes: %v)", scope, models.APIKeyScopes)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required (valid scopes: %v)", models.APIKeyScopes)
+	}
+	return scopes, nil
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// setTestEnv points Load at a missing .env file so only the variables set by the test apply.
+func setTestEnv(t *testing.T, debug string) {
+	t.Helper()
+	t.Setenv("ENV_PATH", filepath.Join(t.TempDir(), "missing.env"))
+	t.Setenv("DEBUG", debug)
+	t.Setenv("JWT_SECRET", "")
+}
+
+func TestLoadRequiresJWTSecretOutsideDebug(t *testing.T) {
+	setTestEnv(t, "false")
+
+	cfg, err := Load()
+	if err == nil {
+		t.Fatal("Load() succeeded without JWT_SECRET in production mode, want an error")
+	}
+	if cfg != nil {
+		t.Fatalf("Load() returned a config alongside the error: %+v", cfg.Redacted())
+	}
+}
+
+func TestLoadFallsBackToRandomJWTSecretInDebug(t *testing.T) {
+	setTestEnv(t, "true")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() in debug mode = %v, want nil", err)
+	}
+	if cfg.JWT.Secret == "" {
+		t.Fatal("Load() in debug mode left JWT.Secret empty, want a random secret")
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "net/url"
+
+// redactedValue replaces secrets in redacted output
+const redactedValue = "[REDACTED]"
+
+// Redacted returns a copy of the configuration that is safe to log.
+// The database passwords (primary and replica), JWT secrets, SMTP password and metrics token are masked.
+func (c Config) Redacted() Config {
+	c.Database.URL = redactURL(c.Database.URL)
+	c.Database.ReplicaURL = redactURL(c.Database.ReplicaURL)
+	c.JWT.Secret = redactSecret(c.JWT.Secret)
+	retired := make([]string, len(c.JWT.RetiredSecrets))
+	for i, secret := range c.JWT.RetiredSecrets {
+		retired[i] = redactSecret(secret)
+	}
+	c.JWT.RetiredSecrets = retired
+	c.Email.Password = redactSecret(c.Email.Password)
+	c.App.MetricsToken = redactSecret(c.App.MetricsToken)
+	return c
+}
+
+// redactURL masks the password in a connection URL, including a password query parameter.
+// Values that are not URLs (e.g. key=value DSNs) are masked entirely since they may still contain credentials.
+func redactURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		return redactedValue
+	}
+
+	query := u.Query()
+	if query.Has("password") {
+		query.Set("password", "xxxxx") // same mask url.Redacted uses for the userinfo password
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return secret
+	}
+	return redactedValue
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactedMasksDatabasePasswords(t *testing.T) {
+	const password = "s3cr3t-Pa55"
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"userinfo", "postgres://qashare:" + password + "@db:5432/qashare?sslmode=require"},
+		{"percent-encoded userinfo", "postgres://qashare:" + password + "%40%2F@db:5432/qashare"},
+		{"password query parameter", "postgres://db:5432/qashare?user=qashare&password=" + password},
+		{"key=value DSN", "host=db user=qashare password=" + password + " dbname=qashare"},
+	}
+	for _, tt := range tests {
+		for _, field := range []string{"primary", "replica"} {
+			t.Run(tt.name+"/"+field, func(t *testing.T) {
+				var cfg Config
+				if field == "primary" {
+					cfg.Database.URL = tt.url
+				} else {
+					cfg.Database.ReplicaURL = tt.url
+				}
+
+				redacted := cfg.Redacted()
+				got := redacted.Database.URL
+				if field == "replica" {
+					got = redacted.Database.ReplicaURL
+				}
+				if got == "" {
+					t.Fatal("redacted URL is empty, want the URL with its password masked")
+				}
+				// Check the whole value as it would be logged, not just the URL field
+				if dump := fmt.Sprintf("%+v", redacted); strings.Contains(dump, password) {
+					t.Errorf("password leaked into redacted config: %s", got)
+				}
+				if original := cfg.Database.URL + cfg.Database.ReplicaURL; original != tt.url {
+					t.Errorf("Redacted modified the original config: %s", original)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// Activity log actions
+const (
+	ActivityCreated  = "created"
+	ActivityUpdated  = "updated"
+	ActivityDeleted  = "deleted"
+	ActivityRestored = "restored"
+	ActivityAdded    = "added"
+	ActivityRemoved  = "removed"
+)
+
+// Activity log target types
+const (
+	TargetExpense    = "expense"
+	TargetSettlement = "settlement"
+	TargetMember     = "member"
+)
+
+// RecordActivity appends an entry to the group's activity log.
+// It takes the transaction of the mutating operation so the log is written atomically with the change.
+func RecordActivity(ctx context.Context, tx pgx.Tx, groupID, actorID uuid.UUID, action, targetType string, targetID uuid.UUID, summary string) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO activity_log (group_id, actor_id, action, target_type, target_id, summary)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		groupID, actorID, action, targetType, targetID, summary,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// recordMemberActivity records one activity entry per member, summarised by the member's name.
+func recordMemberActivity(ctx context.Context, tx pgx.Tx, groupID, actorID uuid.UUID, action string, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO activity_log (group_id, actor_id, action, target_type, target_id, summary)
+		SELECT $1, $2, $3, $4, u.user_id, u.user_name
+		FROM users u
+		WHERE u.user_id = ANY($5)`,
+		groupID, actorID, action, TargetMember, userIDs,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record activity: %w", err)
+	}
+	return nil
+}
+
+// GetActivity retrieves a page of the group's activity log, newest first.
+// Pass the returned cursor back to fetch the next page; an empty cursor means there are no more pages.
+func GetActivity(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, limit int, cursor string) ([]models.Activity, string, error) {
+	if groupID == uuid.Nil {
+		return nil, "", ErrInvalidInput.Msg("group id missing")
+	}
+
+	var afterTime *time.Time
+	var afterID *uuid.UUID
+	if cursor != "" {
+		values, err := utils.DecodeCursor(cursor, 2)
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		t, err := time.Parse(time.RFC3339Nano, values[0])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		id, err := uuid.Parse(values[1])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		afterTime, afterID = &t, &id
+	}
+
+	query := `SELECT a.activity_id, a.group_id, a.actor_id, COALESCE(u.user_name, $5), a.action, a.target_type, a.target_id,
+			a.summary, a.created_at
+		FROM activity_log a
+		LEFT JOIN users u ON u.user_id = a.actor_id
+		WHERE a.group_id = $1
+			AND ($2::timestamptz IS NULL OR (a.created_at, a.activity_id) < ($2::timestamptz, $3::uuid))
+		ORDER BY a.created_at DESC, a.activity_id DESC
+		LIMIT $4`
+
+	// Fetch one extra entry to know whether another page exists
+	rows, err := pool.Query(ctx, query, groupID, afterTime, afterID, limit+1, models.DeletedUserName)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	entries := make([]models.Activity, 0, limit)
+	createdAt := make([]time.Time, 0, limit)
+	for rows.Next() {
+		var entry models.Activity
+		var rawCreatedAt time.Time
+		err := rows.Scan(&entry.ActivityID, &entry.GroupID, &entry.ActorID, &entry.ActorName, &entry.Action,
+			&entry.TargetType, &entry.TargetID, &entry.Summary, &rawCreatedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		entry.CreatedAt = rawCreatedAt.Unix()
+		entries = append(entries, entry)
+		createdAt = append(createdAt, rawCreatedAt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(entries) > limit {
+		entries = entries[:limit]
+		nextCursor = utils.EncodeCursor(createdAt[limit-1].Format(time.RFC3339Nano), entries[limit-1].ActivityID.String())
+	}
+
+	return entries, nextCursor, nil
+}
+
+// expenseTarget returns the activity target type for an expense.
+func expenseTarget(isSettlement bool) string {
+	if isSettlement {
+		return TargetSettlement
+	}
+	return TargetExpense
+}
+
+// expenseSummary describes an expense for the activity log.
+// Private expenses are left undescribed, since every group member can read the log.
+func expenseSummary(title string, amount float64, isPrivate bool) string {
+	if isPrivate {
+		return ""
+	}
+	return fmt.Sprintf("%q (%.2f)", title, amount)
+}
+
+// expenseDiffSummary lists the changed title and amount of an expense, followed by a note when the splits were replaced.
+// Private expenses only report that they changed.
+func expenseDiffSummary(before, after models.ExpenseDetails) string {
+	if before.IsPrivate || after.IsPrivate {
+		return ""
+	}
+
+	var changes []string
+	if before.Title != after.Title {
+		changes = append(changes, fmt.Sprintf("title %q -> %q", before.Title, after.Title))
+	}
+	if before.Amount != after.Amount {
+		changes = append(changes, fmt.Sprintf("amount %.2f -> %.2f", before.Amount, after.Amount))
+	}
+	if !sameSplits(before.Splits, after.Splits) {
+		changes = append(changes, "splits changed")
+	}
+	return strings.Join(changes, "; ")
+}
+
+// sameSplits reports whether two split lists assign the same amounts to the same users, ignoring order.
+func sameSplits(a, b []models.ExpenseSplit) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	type key struct {
+		userID uuid.UUID
+		isPaid bool
+	}
+	amounts := make(map[key]float64, len(a))
+	for _, split := range a {
+		amounts[key{split.UserID, split.IsPaid}] += split.Amount
+	}
+	for _, split := range b {
+		amounts[key{split.UserID, split.IsPaid}] -= split.Amount
+	}
+	for _, diff := range amounts {
+		if diff != 0 {
+			return false
+		}
+	}
+	return true
+}
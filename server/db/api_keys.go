@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// CreateAPIKey creates a new API key with the given name and scopes.
+// Only the key's hash is stored. A nil expiresAt creates a key that never expires.
+// Returns the plaintext key, which cannot be recovered later, along with the stored record.
+func CreateAPIKey(ctx context.Context, pool *pgxpool.Pool, name string, scopes []models.APIKeyScope, expiresAt *time.Time) (string, models.APIKey, error) {
+	if name == "" {
+		return "", models.APIKey{}, ErrInvalidInput.Msg("api key name missing")
+	}
+	if len(scopes) == 0 {
+		return "", models.APIKey{}, ErrInvalidInput.Msg("api key needs at least one scope")
+	}
+
+	key, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		return "", models.APIKey{}, err
+	}
+
+	apiKey := models.APIKey{Name: name, Scopes: scopes}
+	query := `INSERT INTO api_keys (name, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING key_id, extract(epoch from created_at)::bigint, extract(epoch from expires_at)::bigint`
+
+	err = pool.QueryRow(ctx, query, name, hash, scopes, expiresAt).Scan(&apiKey.KeyID, &apiKey.CreatedAt, &apiKey.ExpiresAt)
+	if err != nil {
+		return "", models.APIKey{}, err
+	}
+
+	return key, apiKey, nil
+}
+
+// VerifyAPIKey looks up an API key by the hash of the plaintext key and records its use.
+// Returns ErrNotFound if the key does not exist, has been revoked, or has expired.
+func VerifyAPIKey(ctx context.Context, pool *pgxpool.Pool, key string) (models.APIKey, error) {
+	if key == "" {
+		return models.APIKey{}, ErrInvalidInput.Msg("api key missing")
+	}
+
+	var apiKey models.APIKey
+	query := `UPDATE api_keys SET last_used_at = NOW()
+		WHERE key_hash = $1
+			AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING key_id, name, scopes,
+			extract(epoch from created_at)::bigint,
+			extract(epoch from expires_at)::bigint,
+			extract(epoch from last_used_at)::bigint`
+
+	err := pool.QueryRow(ctx, query, utils.HashAPIKey(key)).Scan(
+		&apiKey.KeyID, &apiKey.Name, &apiKey.Scopes,
+		&apiKey.CreatedAt, &apiKey.ExpiresAt, &apiKey.LastUsedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return models.APIKey{}, ErrNotFound.Msg("api key not found")
+	}
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey marks an API key as revoked so it can no longer authenticate.
+// Returns ErrNotFound if no active key with the ID exists.
+func RevokeAPIKey(ctx context.Context, pool *pgxpool.Pool, keyID uuid.UUID) error {
+	result, err := pool.Exec(ctx, `UPDATE api_keys SET revoked_at = NOW() WHERE key_id = $1 AND revoked_at IS NULL`, keyID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound.Msgf("api key with id %s not found", keyID)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+)
+
+// AddExpenseAttachment records a reference to a receipt stored outside the database.
+// Takes an ExpenseAttachment with ExpenseID, URL or ObjectKey, ContentType, SizeBytes and UploadedBy
+// populated, and fills in AttachmentID and CreatedAt.
+// Returns ErrNotFound if the expense does not exist or is in the trash.
+func AddExpenseAttachment(ctx context.Context, pool *pgxpool.Pool, attachment *models.ExpenseAttachment) error {
+	query := `INSERT INTO expense_attachments (expense_id, url, object_key, content_type, size_bytes, uploaded_by)
+		SELECT expense_id, $2, $3, $4, $5, $6
+		FROM expenses
+		WHERE expense_id = $1 AND deleted_at IS NULL
+		RETURNING attachment_id, extract(epoch from created_at)::bigint`
+
+	err := pool.QueryRow(ctx, query,
+		attachment.ExpenseID,
+		attachment.URL,
+		attachment.ObjectKey,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		attachment.UploadedBy,
+	).Scan(&attachment.AttachmentID, &attachment.CreatedAt)
+	if err != nil {
+		if IsNoRows(err) {
+			return ErrNotFound.Msgf("expense with id %s not found", attachment.ExpenseID)
+		}
+		return err
+	}
+	return nil
+}
+
+// ListExpenseAttachments returns the attachments of an expense, oldest first.
+// Returns an empty slice if the expense has none.
+func ListExpenseAttachments(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) ([]models.ExpenseAttachment, error) {
+	query := `SELECT attachment_id, expense_id, url, object_key, content_type, size_bytes, uploaded_by,
+		extract(epoch from created_at)::bigint
+	FROM expense_attachments
+	WHERE expense_id = $1
+	ORDER BY created_at, attachment_id`
+
+	rows, err := pool.Query(ctx, query, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]models.ExpenseAttachment, 0)
+	for rows.Next() {
+		var attachment models.ExpenseAttachment
+		err := rows.Scan(
+			&attachment.AttachmentID,
+			&attachment.ExpenseID,
+			&attachment.URL,
+			&attachment.ObjectKey,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&attachment.UploadedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetExpenseAttachment retrieves a single attachment of an expense.
+// Returns ErrNotFound if the expense has no attachment with the ID.
+func GetExpenseAttachment(ctx context.Context, pool *pgxpool.Pool, expenseID, attachmentID uuid.UUID) (models.ExpenseAttachment, error) {
+	var attachment models.ExpenseAttachment
+	query := `SELECT attachment_id, expense_id, url, object_key, content_type, size_bytes, uploaded_by,
+		extract(epoch from created_at)::bigint
+	FROM expense_attachments
+	WHERE expense_id = $1 AND attachment_id = $2`
+
+	err := pool.QueryRow(ctx, query, expenseID, attachmentID).Scan(
+		&attachment.AttachmentID,
+		&attachment.ExpenseID,
+		&attachment.URL,
+		&attachment.ObjectKey,
+		&attachment.ContentType,
+		&attachment.SizeBytes,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if IsNoRows(err) {
+		return models.ExpenseAttachment{}, ErrNotFound.Msgf("attachment with id %s not found", attachmentID)
+	}
+	if err != nil {
+		return models.ExpenseAttachment{}, err
+	}
+	return attachment, nil
+}
+
+// DeleteExpenseAttachment removes an attachment reference from an expense.
+// The referenced file itself is not touched.
+// Returns ErrNotFound if the expense has no attachment with the ID.
+func DeleteExpenseAttachment(ctx context.Context, pool *pgxpool.Pool, expenseID, attachmentID uuid.UUID) error {
+	result, err := pool.Exec(ctx,
+		`DELETE FROM expense_attachments WHERE expense_id = $1 AND attachment_id = $2`,
+		expenseID, attachmentID,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound.Msgf("attachment with id %s not found", attachmentID)
+	}
+	return nil
+}
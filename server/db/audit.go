@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+)
+
+// AuditGroupSplits finds expenses whose paid or owed split totals differ from the
+// expense amount by more than tolerance. Expenses flagged as incomplete are skipped,
+// since their splits are allowed to be unfinished.
+// If groupID is nil, every group is scanned.
+func AuditGroupSplits(ctx context.Context, pool *pgxpool.Pool, groupID *uuid.UUID, tolerance float64) ([]models.SplitAnomaly, error) {
+	query := `
+	SELECT e.expense_id, e.group_id, e.amount::float8,
+	  COALESCE(SUM(es.amount) FILTER (WHERE es.is_paid), 0)::float8 AS paid_total,
+	  COALESCE(SUM(es.amount) FILTER (WHERE NOT es.is_paid), 0)::float8 AS owed_total
+	FROM expenses e
+	LEFT JOIN expense_splits es ON es.expense_id = e.expense_id
+	WHERE ($1::uuid IS NULL OR e.group_id = $1)
+	  AND e.deleted_at IS NULL
+	  AND NOT e.is_incomplete_amount
+	  AND NOT e.is_incomplete_split
+	GROUP BY e.expense_id, e.group_id, e.amount
+	HAVING ABS(COALESCE(SUM(es.amount) FILTER (WHERE es.is_paid), 0) - e.amount) > $2::numeric
+	  OR ABS(COALESCE(SUM(es.amount) FILTER (WHERE NOT es.is_paid), 0) - e.amount) > $2::numeric
+	ORDER BY e.group_id, e.expense_id
+	`
+
+	rows, err := pool.Query(ctx, query, groupID, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anomalies := make([]models.SplitAnomaly, 0)
+	for rows.Next() {
+		var anomaly models.SplitAnomaly
+		err := rows.Scan(&anomaly.ExpenseID, &anomaly.GroupID, &anomaly.Amount, &anomaly.PaidTotal, &anomaly.OwedTotal)
+		if err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return anomalies, nil
+}
+
+// StartSplitAudit starts a background goroutine that periodically runs AuditGroupSplits
+// over every group and logs each anomaly it finds.
+// The returned channel is closed once the goroutine exits after ctx is cancelled.
+func StartSplitAudit(ctx context.Context, pool *pgxpool.Pool, interval time.Duration, tolerance float64) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Split audit stopped")
+				return
+			case <-ticker.C:
+				anomalies, err := AuditGroupSplits(ctx, pool, nil, tolerance)
+				if err != nil {
+					slog.Error("Failed to audit expense splits", "error", err)
+					continue
+				}
+
+				for _, anomaly := range anomalies {
+					slog.Warn("Expense splits do not match amount",
+						"expense_id", anomaly.ExpenseID,
+						"group_id", anomaly.GroupID,
+						"amount", anomaly.Amount,
+						"paid_total", anomaly.PaidTotal,
+						"owed_total", anomaly.OwedTotal,
+					)
+				}
+				if len(anomalies) > 0 {
+					slog.Warn("Split audit found anomalies", "count", len(anomalies))
+				} else {
+					slog.Debug("Split audit found no anomalies")
+				}
+			}
+		}
+	}()
+	return done
+}
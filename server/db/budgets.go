@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+)
+
+// budgetPeriods lists the periods a group budget can use
+var budgetPeriods = []string{models.BudgetPeriodWeek, models.BudgetPeriodMonth, models.BudgetPeriodYear}
+
+// SetGroupBudget creates or replaces the budget of a group.
+// The budget's UpdatedBy and UpdatedAt are set on success.
+// Returns ErrInvalidInput if the amount is not positive or the period is unknown,
+// or ErrNotFound if the group does not exist.
+func SetGroupBudget(ctx context.Context, pool *pgxpool.Pool, budget *models.GroupBudget, updatedBy uuid.UUID) error {
+	if budget.GroupID == uuid.Nil {
+		return ErrInvalidInput.Msg("group id missing")
+	}
+	if budget.Amount <= 0 || math.IsNaN(budget.Amount) || math.IsInf(budget.Amount, 0) {
+		return ErrInvalidInput.Msg("budget amount must be positive")
+	}
+	if !slices.Contains(budgetPeriods, budget.Period) {
+		return ErrInvalidInput.Msgf("invalid budget period: %q, must be week, month or year", budget.Period)
+	}
+
+	err := pool.QueryRow(ctx,
+		`INSERT INTO group_budgets (group_id, amount, period, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (group_id) DO UPDATE
+		SET amount = EXCLUDED.amount, period = EXCLUDED.period, updated_by = EXCLUDED.updated_by, updated_at = now()
+		RETURNING extract(epoch from updated_at)::bigint`,
+		budget.GroupID, budget.Amount, budget.Period, updatedBy,
+	).Scan(&budget.UpdatedAt)
+	if err != nil {
+		if IsConstraintViolation(err) {
+			return ErrNotFound.Msgf("group with id %s not found", budget.GroupID)
+		}
+		return err
+	}
+	budget.UpdatedBy = &updatedBy
+	return nil
+}
+
+// GetBudgetStatus returns the group's budget with the spend of the current period, computed in a single query.
+// Returns ErrNotFound if the group has no budget.
+func GetBudgetStatus(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (models.BudgetStatus, error) {
+	var status models.BudgetStatus
+	err := pool.QueryRow(ctx,
+		`WITH budget AS (
+			SELECT b.group_id, b.amount, b.period, b.updated_by, b.updated_at, g.base_currency,
+				date_trunc(b.period, now(), 'UTC') AS period_start,
+				date_trunc(b.period, now(), 'UTC') + ('1 ' || b.period)::interval AS period_end
+			FROM group_budgets b
+			JOIN groups g ON g.group_id = b.group_id
+			WHERE b.group_id = $1
+		), spend AS (
+			SELECT COALESCE(SUM(e.amount), 0) AS spent
+			FROM budget b
+			JOIN expenses e ON e.group_id = b.group_id
+			WHERE e.deleted_at IS NULL
+				AND NOT e.is_settlement
+				AND e.transacted_at >= b.period_start
+				AND e.transacted_at < b.period_end
+		)
+		SELECT b.group_id, b.amount::float8, b.period, b.updated_by, extract(epoch from b.updated_at)::bigint,
+			b.base_currency, extract(epoch from b.period_start)::bigint, extract(epoch from b.period_end)::bigint,
+			s.spent::float8, (b.amount - s.spent)::float8, round(s.spent / b.amount * 100, 2)::float8,
+			s.spent > b.amount
+		FROM budget b, spend s`,
+		groupID,
+	).Scan(
+		&status.GroupID, &status.Amount, &status.Period, &status.UpdatedBy, &status.UpdatedAt,
+		&status.Currency, &status.PeriodStart, &status.PeriodEnd,
+		&status.Spent, &status.Remaining, &status.Percentage, &status.OverBudget,
+	)
+	if err == pgx.ErrNoRows {
+		return models.BudgetStatus{}, ErrNotFound.Msg("group has no budget")
+	}
+	if err != nil {
+		return models.BudgetStatus{}, err
+	}
+	return status, nil
+}
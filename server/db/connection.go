@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pranaovs/qashare/config"
 )
@@ -72,6 +74,21 @@ func Connect(dbConfig config.DatabaseConfig) (*pgxpool.Pool, error) {
 	return nil, fmt.Errorf("failed to connect after %d attempts, last error: %w", dbConfig.RetryAttempts, lastErr)
 }
 
+// ConnectReplica connects to the optional read replica at dbConfig.ReplicaURL, using the same
+// pool settings and retry policy as Connect. Returns a nil pool if no replica is configured.
+//
+// The replica is only used for read-only reporting queries (balances, listings, exports).
+// Replication is asynchronous, so those reads may lag slightly behind the latest writes;
+// anything that must see its own writes, including authorization checks, uses the primary.
+func ConnectReplica(dbConfig config.DatabaseConfig) (*pgxpool.Pool, error) {
+	if dbConfig.ReplicaURL == "" {
+		return nil, nil
+	}
+	slog.Info("Read replica configured, connecting")
+	dbConfig.URL = dbConfig.ReplicaURL
+	return Connect(dbConfig)
+}
+
 func VerifyDatabase(ctx context.Context, pool *pgxpool.Pool, dbName string) error {
 	if pool == nil {
 		return fmt.Errorf("connection pool is nil")
@@ -89,19 +106,40 @@ func VerifyDatabase(ctx context.Context, pool *pgxpool.Pool, dbName string) erro
 	return nil
 }
 
-// createPool creates a new connection pool with the provided configuration
+// createPool creates a new connection pool with the provided configuration.
+//
+// Handlers pass the request context straight to queries, which only ends when the client disconnects.
+// To keep a slow query from holding a connection indefinitely, every connection gets a server-side
+// statement_timeout, which covers all queries without threading deadlines through each call.
+// Postgres cancels a statement that runs past it with SQLSTATE 57014, reported to clients as 503.
+// Code that needs a tighter bound can still wrap its context with WithTimeout.
 func createPool(ctx context.Context, dbConfig config.DatabaseConfig) (*pgxpool.Pool, error) {
 	poolConfig, err := pgxpool.ParseConfig(dbConfig.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pool config: %w", err)
 	}
 
-	// Apply configuration
+	// Apply configuration; durations left at zero keep pgx's defaults,
+	// since a zero lifetime expires every connection and a zero health check period panics
 	poolConfig.MaxConns = dbConfig.MaxConnections
 	poolConfig.MinConns = dbConfig.MinConnections
-	poolConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
-	poolConfig.MaxConnIdleTime = dbConfig.MaxConnIdleTime
-	poolConfig.HealthCheckPeriod = dbConfig.HealthCheckPeriod
+	if dbConfig.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = dbConfig.MaxConnLifetime
+	}
+	if dbConfig.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = dbConfig.MaxConnIdleTime
+	}
+	if dbConfig.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = dbConfig.HealthCheckPeriod
+	}
+
+	if dbConfig.StatementTimeout > 0 {
+		timeout := strconv.FormatInt(dbConfig.StatementTimeout.Milliseconds(), 10)
+		poolConfig.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+			_, err := conn.Exec(ctx, "SELECT set_config('statement_timeout', $1, false)", timeout)
+			return err
+		}
+	}
 
 	return pgxpool.NewWithConfig(ctx, poolConfig)
 }
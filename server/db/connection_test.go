@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// newStatementTimeoutPool connects to the database in TEST_DATABASE_URL with a 100ms statement timeout.
+// Tests using it are skipped when no database is configured.
+func newStatementTimeoutPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	pool, err := createPool(context.Background(), config.DatabaseConfig{
+		URL:              url,
+		MaxConnections:   2,
+		StatementTimeout: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("createPool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestStatementTimeoutCancelsSlowQuery(t *testing.T) {
+	pool := newStatementTimeoutPool(t)
+
+	_, err := pool.Exec(context.Background(), "SELECT pg_sleep(1)")
+	if !utils.IsStatementTimeout(err) {
+		t.Fatalf("slow query error = %v, want a statement timeout (SQLSTATE 57014)", err)
+	}
+}
+
+func TestStatementTimeoutLiftedInsideMigrationTransaction(t *testing.T) {
+	pool := newStatementTimeoutPool(t)
+
+	// Mirrors applyMigration: SET LOCAL lifts the timeout for the transaction only
+	err := WithTransaction(context.Background(), pool, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, "SELECT pg_sleep(0.3)")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("slow statement inside migration transaction = %v, want nil", err)
+	}
+
+	_, err = pool.Exec(context.Background(), "SELECT pg_sleep(1)")
+	if !utils.IsStatementTimeout(err) {
+		t.Fatalf("slow query after the transaction = %v, want the pool's statement timeout again", err)
+	}
+}
+
+// terminateBackend ends the server process behind pid from a separate connection, as a server restart or
+// a proxy dropping the connection would, and waits for it to exit.
+func terminateBackend(t *testing.T, pid uint32) {
+	t.Helper()
+	conn, err := pgx.Connect(context.Background(), os.Getenv("TEST_DATABASE_URL"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	var terminated bool
+	if err := conn.QueryRow(context.Background(), "SELECT pg_terminate_backend($1, 5000)", pid).Scan(&terminated); err != nil || !terminated {
+		t.Fatalf("pg_terminate_backend(%d) = %v, %v", pid, terminated, err)
+	}
+}
+
+func TestWithTransactionRetriesBeginOnDroppedConnection(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	// A single connection, so the transaction has to begin on the one that was dropped
+	pool, err := createPool(context.Background(), config.DatabaseConfig{URL: url, MaxConnections: 1})
+	if err != nil {
+		t.Fatalf("createPool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	var droppedPID uint32
+	if err := pool.QueryRow(context.Background(), "SELECT pg_backend_pid()").Scan(&droppedPID); err != nil {
+		t.Fatalf("backend pid: %v", err)
+	}
+	// Terminated while idle in the pool for less than a second, so pgxpool hands it out without a ping
+	terminateBackend(t, droppedPID)
+
+	var pid uint32
+	err = WithTransaction(context.Background(), pool, func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "SELECT pg_backend_pid()").Scan(&pid)
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction after a dropped connection = %v, want nil", err)
+	}
+	if pid == droppedPID {
+		t.Errorf("transaction ran on the terminated backend %d", pid)
+	}
+	if n := pool.Stat().NewConnsCount(); n != 2 {
+		t.Errorf("pool opened %d connections, want the dropped one and its replacement", n)
+	}
+}
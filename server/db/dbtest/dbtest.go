@@ -0,0 +1,131 @@
+// Package dbtest provides a migrated test database and fixtures for tests that need Postgres.
+// Tests using it are skipped unless TEST_DATABASE_URL points at a database they may write to.
+package dbtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+)
+
+// Pool connects to the database in TEST_DATABASE_URL and applies all migrations.
+// The test is skipped when no database is configured.
+func Pool(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	pool, err := db.Connect(config.DatabaseConfig{
+		URL:            url,
+		MaxConnections: 4,
+		ConnectTimeout: 10 * time.Second,
+		RetryAttempts:  1,
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := db.Migrate(pool, migrationsDir()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return pool
+}
+
+// migrationsDir returns the server's migrations directory, wherever the test runs from.
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
+
+// User creates a verified user with a unique email.
+func User(t testing.TB, pool *pgxpool.Pool) models.User {
+	t.Helper()
+	id := uuid.New()
+	user := models.User{
+		Name:          "Test User",
+		Email:         id.String() + "@example.com",
+		EmailVerified: true,
+	}
+	if _, err := db.CreateUser(context.Background(), pool, &user, 0, nil); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// Group creates a group owned by owner, with members added alongside the owner.
+func Group(t testing.TB, pool *pgxpool.Pool, owner uuid.UUID, members ...uuid.UUID) models.Group {
+	t.Helper()
+	group := models.Group{
+		Name:      "Test Group",
+		CreatedBy: owner,
+		Currency:  "USD",
+	}
+	if err := db.CreateGroup(context.Background(), pool, &group); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if len(members) > 0 {
+		if _, _, err := db.AddGroupMembers(context.Background(), pool, group.GroupID, owner, members); err != nil {
+			t.Fatalf("add group members: %v", err)
+		}
+	}
+	return group
+}
+
+// Expense creates an expense in groupID added by addedBy with the given amount and splits.
+func Expense(t testing.TB, pool *pgxpool.Pool, groupID, addedBy uuid.UUID, amount float64, splits ...models.ExpenseSplit) models.ExpenseDetails {
+	t.Helper()
+	expense := models.ExpenseDetails{
+		Expense: models.Expense{
+			GroupID: groupID,
+			AddedBy: addedBy,
+			Title:   "Test Expense",
+			Amount:  amount,
+		},
+		Splits: splits,
+	}
+	if err := db.CreateExpense(context.Background(), pool, &expense); err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	return expense
+}
+
+// Settlement creates a settlement in groupID recording that from paid to amount.
+func Settlement(t testing.TB, pool *pgxpool.Pool, groupID, from, to uuid.UUID, amount float64) models.ExpenseDetails {
+	t.Helper()
+	settlement := models.ExpenseDetails{
+		Expense: models.Expense{
+			GroupID:      groupID,
+			AddedBy:      from,
+			Title:        "Settlement",
+			Amount:       amount,
+			IsSettlement: true,
+		},
+		Splits: []models.ExpenseSplit{Paid(from, amount), Owes(to, amount)},
+	}
+	if err := db.CreateExpense(context.Background(), pool, &settlement); err != nil {
+		t.Fatalf("create settlement: %v", err)
+	}
+	return settlement
+}
+
+// Paid returns a split recording that userID paid amount.
+func Paid(userID uuid.UUID, amount float64) models.ExpenseSplit {
+	return models.ExpenseSplit{UserID: userID, Amount: amount, IsPaid: true}
+}
+
+// Owes returns a split recording that userID owes amount.
+func Owes(userID uuid.UUID, amount float64) models.ExpenseSplit {
+	return models.ExpenseSplit{UserID: userID, Amount: amount}
+}
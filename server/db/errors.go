@@ -101,6 +101,12 @@ var (
 		Code:    "EXPIRED_TOKEN",
 		Message: "token has expired",
 	}
+
+	// ErrOutstandingBalance indicates a member still owes or is owed money in a group
+	ErrOutstandingBalance = &DBError{
+		Code:    "OUTSTANDING_BALANCE",
+		Message: "outstanding balance",
+	}
 )
 
 // IsNotFound checks if an error is a "not found" error
@@ -139,6 +145,18 @@ func IsConstraintError(err error) bool {
 	return false
 }
 
+// IsOutstandingBalance checks if an error is an outstanding balance error
+func IsOutstandingBalance(err error) bool {
+	if err == nil {
+		return false
+	}
+	var dbErr *DBError
+	if errors.As(err, &dbErr) {
+		return dbErr.Code == ErrOutstandingBalance.Code
+	}
+	return false
+}
+
 // IsNoRows checks if an error is a "no rows" error from pgx
 func IsNoRows(err error) bool {
 	if err == nil {
@@ -7,14 +7,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// checkSplitAmounts rejects negative split amounts, which would corrupt settlement math.
+// Handlers validate splits in full (see utils.ValidateSplits) before they get here; this guards
+// every write path, settlements included, which are stored as a positive paid and owed split.
+func checkSplitAmounts(splits []models.ExpenseSplit) error {
+	for _, s := range splits {
+		if s.Amount < 0 {
+			return ErrInvalidInput.Msgf("split amount for user %s must not be negative", s.UserID)
+		}
+	}
+	return nil
+}
+
 // CreateExpense creates a new expense with associated splits in the database.
 // This operation is atomic - either both the expense and all splits are created,
 // or neither is (using a transaction).
@@ -39,26 +54,42 @@ func CreateExpense(
 	if !expense.IsIncompleteAmount && expense.Amount <= 0 {
 		return ErrInvalidInput.Msg("amount must be greater than zero")
 	}
+	if err := checkSplitAmounts(expense.Splits); err != nil {
+		return err
+	}
 
 	// Use WithTransaction helper for consistent transaction management
 	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		// Claim the next expense number for the group.
+		// The row lock taken by the UPDATE serializes concurrent creates within a group.
+		err := tx.QueryRow(ctx,
+			`UPDATE groups SET expense_seq = expense_seq + 1 WHERE group_id = $1 RETURNING expense_seq`,
+			expense.GroupID,
+		).Scan(&expense.Seq)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrNotFound.Msgf("group with id %s not found", expense.GroupID)
+			}
+			return fmt.Errorf("failed to assign expense number: %w", err)
+		}
+
 		// Insert expense record
 		// is_private is forced true when the group itself is private,
 		// otherwise the user-provided value is used.
 		insertQuery := `INSERT INTO expenses (
 			group_id, added_by, title, description, amount,
 			is_incomplete_amount, is_incomplete_split, is_settlement, is_private, latitude, longitude,
-			transacted_at
+			transacted_at, seq, payment_method, category, client_ref
 		)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
 			$9 OR COALESCE((SELECT is_private FROM groups WHERE group_id = $1), false),
 			$10, $11,
-			COALESCE(to_timestamp($12::bigint), now()))
+			COALESCE(to_timestamp($12::bigint), now()), $13, $14, $15, $16)
 		RETURNING expense_id, is_private,
 			extract(epoch from created_at)::bigint,
 			extract(epoch from transacted_at)::bigint`
 
-		err := tx.QueryRow(
+		err = tx.QueryRow(
 			ctx,
 			insertQuery,
 			expense.GroupID,
@@ -73,8 +104,15 @@ func CreateExpense(
 			expense.Latitude,
 			expense.Longitude,
 			expense.TransactedAt,
+			expense.Seq,
+			expense.PaymentMethod,
+			expense.Category,
+			expense.ClientRef,
 		).Scan(&expense.ExpenseID, &expense.IsPrivate, &expense.CreatedAt, &expense.TransactedAt)
 		if err != nil {
+			if IsDuplicateKey(err) {
+				return ErrDuplicateKey.Msg("client_ref is already used in the group")
+			}
 			return fmt.Errorf("failed to insert expense: %w", err)
 		}
 
@@ -101,9 +139,33 @@ func CreateExpense(
 					return fmt.Errorf("failed to insert split %d of %d: %w", i+1, len(expense.Splits), err)
 				}
 			}
+			if err := br.Close(); err != nil {
+				return err
+			}
 		}
 
-		return nil
+		if expense.IsSettlement {
+			if err := coverSettledExpenses(ctx, tx, expense); err != nil {
+				return err
+			}
+		}
+
+		err = RecordActivity(ctx, tx, expense.GroupID, expense.AddedBy, ActivityCreated,
+			expenseTarget(expense.IsSettlement), expense.ExpenseID,
+			expenseSummary(expense.Title, expense.Amount, expense.IsPrivate))
+		if err != nil {
+			return err
+		}
+
+		// Private expenses are only visible to their participants, so they are not sent to integrations
+		if expense.IsPrivate {
+			return nil
+		}
+		event := models.WebhookExpenseCreated
+		if expense.IsSettlement {
+			event = models.WebhookSettlementCreated
+		}
+		return enqueueWebhookEvent(ctx, tx, expense.GroupID, expense.AddedBy, event, expense)
 	})
 	if err != nil {
 		return err
@@ -117,8 +179,9 @@ func CreateExpense(
 // or neither is (using a transaction).
 //
 // The old splits are deleted and replaced with the new splits provided.
+// The change is recorded in the group's activity log as made by actorID.
 // Returns an error if validation fails or the operation fails.
-func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.ExpenseDetails) error {
+func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.ExpenseDetails, actorID uuid.UUID) error {
 	// Validate input
 	if expense.ExpenseID == uuid.Nil {
 		return ErrNotFound.Msg("expense not found")
@@ -129,9 +192,18 @@ func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.Expe
 	if !expense.IsIncompleteAmount && expense.Amount <= 0 {
 		return ErrInvalidInput.Msg("amount must be greater than zero")
 	}
+	if err := checkSplitAmounts(expense.Splits); err != nil {
+		return err
+	}
 
 	// Use WithTransaction helper for consistent transaction management
 	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		// Lock and load the current state for the activity log diff
+		before, err := lockExpenseForUpdate(ctx, tx, expense.ExpenseID)
+		if err != nil {
+			return err
+		}
+
 		// Update main expense fields
 		updateQuery := `UPDATE expenses
 			SET title = $2,
@@ -143,8 +215,10 @@ func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.Expe
 				is_private = $8,
 				latitude = $9,
 				longitude = $10,
-				transacted_at = COALESCE(to_timestamp($11::bigint), transacted_at)
-			WHERE expense_id = $1`
+				transacted_at = COALESCE(to_timestamp($11::bigint), transacted_at),
+				payment_method = $12,
+				category = $13
+			WHERE expense_id = $1 AND deleted_at IS NULL`
 
 		result, err := tx.Exec(
 			ctx,
@@ -160,6 +234,8 @@ func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.Expe
 			expense.Latitude,
 			expense.Longitude,
 			expense.TransactedAt,
+			expense.PaymentMethod,
+			expense.Category,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to update expense: %w", err)
@@ -200,10 +276,13 @@ func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.Expe
 					return fmt.Errorf("failed to insert split %d of %d: %w", i+1, len(expense.Splits), err)
 				}
 			}
-
+			if err := br.Close(); err != nil {
+				return err
+			}
 		}
 
-		return nil
+		return RecordActivity(ctx, tx, before.GroupID, actorID, ActivityUpdated,
+			expenseTarget(before.IsSettlement), expense.ExpenseID, expenseDiffSummary(before, *expense))
 	})
 	if err != nil {
 		return err
@@ -212,24 +291,160 @@ func UpdateExpense(ctx context.Context, pool *pgxpool.Pool, expense *models.Expe
 	return nil
 }
 
+// UpdateSplitPaidStatus moves the user's split in an expense to the paid (isPaid) or owed side, keeping its amount.
+// The caller is responsible for checking that the resulting splits are still valid.
+// The change is recorded in the group's activity log as made by actorID.
+// Returns ErrNotFound if the expense does not exist or the user has no split on the other side,
+// and ErrDuplicateKey if the user already has a split on the requested side.
+func UpdateSplitPaidStatus(ctx context.Context, pool *pgxpool.Pool, expenseID, userID uuid.UUID, isPaid bool, actorID uuid.UUID) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		before, err := lockExpenseForUpdate(ctx, tx, expenseID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx,
+			`UPDATE expense_splits SET is_paid = $3
+			WHERE expense_id = $1 AND user_id = $2 AND is_paid <> $3`,
+			expenseID, userID, isPaid,
+		)
+		if err != nil {
+			if IsDuplicateKey(err) {
+				return ErrDuplicateKey.Msgf("user %s already has a split on that side", userID)
+			}
+			return fmt.Errorf("failed to update split: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrNotFound.Msgf("user %s has no split to change in expense %s", userID, expenseID)
+		}
+
+		after := before
+		after.Splits = make([]models.ExpenseSplit, len(before.Splits))
+		for i, split := range before.Splits {
+			if split.UserID == userID && split.IsPaid != isPaid {
+				split.IsPaid = isPaid
+			}
+			after.Splits[i] = split
+		}
+
+		return RecordActivity(ctx, tx, before.GroupID, actorID, ActivityUpdated,
+			expenseTarget(before.IsSettlement), expenseID, expenseDiffSummary(before, after))
+	})
+}
+
+// FinalizeExpense clears the incomplete amount and split flags of an expense, provided its current
+// splits balance against its amount within tolerance. The check and the update happen under the row lock,
+// so the splits cannot change in between.
+// An expense that is already complete is left unchanged and nothing is logged.
+// Returns ErrNotFound if no live expense with the ID exists, ErrInvalidInput if the amount is not positive,
+// or utils.ErrInvalidSplit if the splits do not balance.
+func FinalizeExpense(ctx context.Context, pool *pgxpool.Pool, expenseID, actorID uuid.UUID, tolerance float64) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		expense, err := lockExpenseForUpdate(ctx, tx, expenseID)
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec(ctx,
+			`UPDATE expenses SET is_incomplete_amount = false, is_incomplete_split = false
+			WHERE expense_id = $1 AND (is_incomplete_amount OR is_incomplete_split)`,
+			expenseID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to finalize expense: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil
+		}
+
+		if expense.Amount <= 0 {
+			return ErrInvalidInput.Msg("amount must be greater than zero")
+		}
+		if err := utils.ValidateSplits(expense.Splits, expense.Amount, tolerance, false, false); err != nil {
+			return err
+		}
+
+		summary := expenseSummary(expense.Title, expense.Amount, expense.IsPrivate)
+		if summary != "" {
+			summary += ": marked complete"
+		}
+		return RecordActivity(ctx, tx, expense.GroupID, actorID, ActivityUpdated,
+			expenseTarget(expense.IsSettlement), expenseID, summary)
+	})
+}
+
+// lockExpenseForUpdate locks a live expense row and loads the fields and splits needed to describe a change to it.
+// Returns ErrNotFound if no live expense with the ID exists.
+func lockExpenseForUpdate(ctx context.Context, tx pgx.Tx, expenseID uuid.UUID) (models.ExpenseDetails, error) {
+	var expense models.ExpenseDetails
+	err := tx.QueryRow(ctx,
+		`SELECT expense_id, group_id, title, amount, is_settlement, is_private
+		FROM expenses
+		WHERE expense_id = $1 AND deleted_at IS NULL
+		FOR UPDATE`,
+		expenseID,
+	).Scan(&expense.ExpenseID, &expense.GroupID, &expense.Title, &expense.Amount, &expense.IsSettlement, &expense.IsPrivate)
+	if err == pgx.ErrNoRows {
+		return models.ExpenseDetails{}, ErrNotFound.Msgf("expense with id %s not found", expenseID)
+	}
+	if err != nil {
+		return models.ExpenseDetails{}, err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT expense_id, user_id, amount, is_paid FROM expense_splits WHERE expense_id = $1`, expenseID)
+	if err != nil {
+		return models.ExpenseDetails{}, err
+	}
+	defer rows.Close()
+
+	expense.Splits = make([]models.ExpenseSplit, 0)
+	for rows.Next() {
+		var split models.ExpenseSplit
+		if err := rows.Scan(&split.ExpenseID, &split.UserID, &split.Amount, &split.IsPaid); err != nil {
+			return models.ExpenseDetails{}, err
+		}
+		expense.Splits = append(expense.Splits, split)
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.ExpenseDetails{}, err
+	}
+
+	return expense, nil
+}
+
 // GetExpense retrieves a complete expense record including all its splits in a single query.
+// Soft-deleted expenses are treated as missing.
 // Returns ErrExpenseNotFound if no expense with the ID exists.
 func GetExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (models.ExpenseDetails, error) {
+	return getExpense(ctx, pool, expenseID, false)
+}
+
+// GetDeletedExpense retrieves a soft-deleted expense including all its splits.
+// Returns ErrNotFound if no expense with the ID is in the trash.
+func GetDeletedExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (models.ExpenseDetails, error) {
+	return getExpense(ctx, pool, expenseID, true)
+}
+
+// getExpense loads an expense with its splits, either a live one or, when deleted is set, one from the trash.
+func getExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID, deleted bool) (models.ExpenseDetails, error) {
 	var expense models.ExpenseDetails
 
-	query := `SELECT e.expense_id, e.group_id, e.added_by, e.title, e.description,
+	query := `SELECT e.expense_id, e.group_id, e.seq, e.added_by, e.title, e.description,
 		extract(epoch from e.created_at)::bigint,
 		extract(epoch from e.transacted_at)::bigint,
 		e.amount,
 		e.is_incomplete_amount, e.is_incomplete_split, e.is_settlement, e.is_private,
-		e.latitude, e.longitude,
+		e.latitude, e.longitude, e.payment_method, e.category,
+		extract(epoch from e.deleted_at)::bigint, e.client_ref,
 		es.user_id, es.amount, es.is_paid
 	FROM expenses e
 	LEFT JOIN expense_splits es ON e.expense_id = es.expense_id
 	WHERE e.expense_id = $1
+		AND (e.deleted_at IS NOT NULL) = $2
 	ORDER BY es.is_paid DESC, es.user_id`
 
-	rows, err := pool.Query(ctx, query, expenseID)
+	rows, err := pool.Query(ctx, query, expenseID, deleted)
 	if err != nil {
 		if IsInvalidUUID(err) {
 			return models.ExpenseDetails{}, ErrNotFound.Msgf("expense with id %s not found", expenseID)
@@ -248,6 +463,7 @@ func GetExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (m
 		err = rows.Scan(
 			&expense.ExpenseID,
 			&expense.GroupID,
+			&expense.Seq,
 			&expense.AddedBy,
 			&expense.Title,
 			&expense.Description,
@@ -260,6 +476,10 @@ func GetExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (m
 			&expense.IsPrivate,
 			&expense.Latitude,
 			&expense.Longitude,
+			&expense.PaymentMethod,
+			&expense.Category,
+			&expense.DeletedAt,
+			&expense.ClientRef,
 			&splitUserID,
 			&splitAmount,
 			&splitIsPaid,
@@ -291,54 +511,297 @@ func GetExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (m
 	return expense, nil
 }
 
-// DeleteExpense deletes an expense from the database.
-// This operation is atomic and uses a transaction.
-// Note: The database will handle cascading deletes for expense_splits if configured.
-// Returns ErrExpenseNotFound if no expense with the ID exists.
-func DeleteExpense(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) error {
-	// Use WithTransaction helper for consistent transaction management
-	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
-		// Delete the expense (splits will be cascade deleted)
-		deleteQuery := `DELETE FROM expenses WHERE expense_id = $1`
+// GetExpensesByIDs retrieves several expenses with their splits in a single query, in the order of expenseIDs.
+// Only expenses userID may read are returned: live, non-settlement expenses in groups they are a member of,
+// where private expenses are limited to the creator and split participants, as in GetExpenses.
+// IDs that do not exist or are not accessible are silently left out.
+func GetExpensesByIDs(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, expenseIDs []uuid.UUID) ([]models.ExpenseDetails, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+	if len(expenseIDs) == 0 {
+		return []models.ExpenseDetails{}, nil
+	}
 
-		result, err := tx.Exec(ctx, deleteQuery, expenseID)
+	query := `SELECT e.expense_id, e.group_id, e.seq, e.added_by, e.title, e.description,
+		extract(epoch from e.created_at)::bigint,
+		extract(epoch from e.transacted_at)::bigint,
+		e.amount,
+		e.is_incomplete_amount, e.is_incomplete_split, e.is_settlement, e.is_private,
+		e.latitude, e.longitude, e.payment_method, e.category,
+		extract(epoch from e.deleted_at)::bigint,
+		es.user_id, es.amount, es.is_paid
+	FROM expenses e
+	JOIN group_members gm ON gm.group_id = e.group_id AND gm.user_id = $2
+	LEFT JOIN expense_splits es ON e.expense_id = es.expense_id
+	WHERE e.expense_id = ANY($1)
+		AND e.is_settlement = false
+		AND e.deleted_at IS NULL
+		AND (
+			e.is_private = false
+			OR e.added_by = $2
+			OR e.expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = $2)
+		)
+	ORDER BY e.expense_id, es.is_paid DESC, es.user_id`
+
+	rows, err := pool.Query(ctx, query, expenseIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[uuid.UUID]*models.ExpenseDetails, len(expenseIDs))
+	for rows.Next() {
+		var expense models.ExpenseDetails
+		var splitUserID *uuid.UUID
+		var splitAmount *float64
+		var splitIsPaid *bool
+
+		err = rows.Scan(
+			&expense.ExpenseID,
+			&expense.GroupID,
+			&expense.Seq,
+			&expense.AddedBy,
+			&expense.Title,
+			&expense.Description,
+			&expense.CreatedAt,
+			&expense.TransactedAt,
+			&expense.Amount,
+			&expense.IsIncompleteAmount,
+			&expense.IsIncompleteSplit,
+			&expense.IsSettlement,
+			&expense.IsPrivate,
+			&expense.Latitude,
+			&expense.Longitude,
+			&expense.PaymentMethod,
+			&expense.Category,
+			&expense.DeletedAt,
+			&splitUserID,
+			&splitAmount,
+			&splitIsPaid,
+		)
 		if err != nil {
-			return fmt.Errorf("failed to delete expense: %w", err)
+			return nil, err
 		}
 
-		// Check if expense was found
-		if result.RowsAffected() == 0 {
-			return ErrNotFound.Msgf("expense with id %s not found", expenseID)
+		existing, ok := byID[expense.ExpenseID]
+		if !ok {
+			expense.Splits = make([]models.ExpenseSplit, 0)
+			existing = &expense
+			byID[expense.ExpenseID] = existing
+		}
+
+		// Skip NULL splits (expense has no splits)
+		if splitUserID != nil {
+			existing.Splits = append(existing.Splits, models.ExpenseSplit{
+				ExpenseID: existing.ExpenseID,
+				UserID:    *splitUserID,
+				Amount:    *splitAmount,
+				IsPaid:    *splitIsPaid,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	expenses := make([]models.ExpenseDetails, 0, len(byID))
+	for _, expenseID := range expenseIDs {
+		if expense, ok := byID[expenseID]; ok {
+			expenses = append(expenses, *expense)
+		}
+	}
+	return expenses, nil
+}
+
+// GetExpenseSplits retrieves only the splits of an expense, for callers that already have the expense header.
+// Splits are ordered like GetExpense (is_paid DESC, user_id). Returns an empty slice if the expense has no splits
+// or does not exist.
+func GetExpenseSplits(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) ([]models.ExpenseSplit, error) {
+	query := `SELECT expense_id, user_id, amount, is_paid
+		FROM expense_splits
+		WHERE expense_id = $1
+		ORDER BY is_paid DESC, user_id`
+
+	rows, err := pool.Query(ctx, query, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	splits := make([]models.ExpenseSplit, 0)
+	for rows.Next() {
+		var split models.ExpenseSplit
+		if err := rows.Scan(&split.ExpenseID, &split.UserID, &split.Amount, &split.IsPaid); err != nil {
+			return nil, err
+		}
+		splits = append(splits, split)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return splits, nil
+}
+
+// GetExpenseBySeq retrieves a complete expense record by its per-group expense number.
+// Returns ErrNotFound if the group has no expense with that number.
+func GetExpenseBySeq(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, seq int64) (models.ExpenseDetails, error) {
+	var expenseID uuid.UUID
+	err := pool.QueryRow(ctx, `SELECT expense_id FROM expenses WHERE group_id = $1 AND seq = $2 AND deleted_at IS NULL`, groupID, seq).Scan(&expenseID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.ExpenseDetails{}, ErrNotFound.Msgf("expense #%d not found", seq)
+		}
+		return models.ExpenseDetails{}, err
+	}
+
+	return GetExpense(ctx, pool, expenseID)
+}
+
+// DeleteExpense moves an expense to the trash by setting deleted_at.
+// Trashed expenses are hidden from every read and balance query, can be brought back
+// with RestoreExpense, and are removed for good by PurgeDeletedExpenses.
+// The deletion is recorded in the group's activity log as made by actorID.
+// Returns ErrNotFound if no live expense with the ID exists.
+func DeleteExpense(ctx context.Context, pool *pgxpool.Pool, expenseID, actorID uuid.UUID) error {
+	return setExpenseDeleted(ctx, pool, expenseID, actorID, true)
+}
+
+// RestoreExpense takes an expense out of the trash.
+// The restore is recorded in the group's activity log as made by actorID.
+// Returns ErrNotFound if no soft-deleted expense with the ID exists.
+func RestoreExpense(ctx context.Context, pool *pgxpool.Pool, expenseID, actorID uuid.UUID) error {
+	return setExpenseDeleted(ctx, pool, expenseID, actorID, false)
+}
+
+// DeleteExpenses moves several expenses to the trash in one transaction, logging each deletion as made by actorID.
+// Either all of them are deleted or none are.
+// On failure it also returns the ID of the expense that failed, e.g. with ErrNotFound if it has no live row.
+func DeleteExpenses(ctx context.Context, pool *pgxpool.Pool, expenseIDs []uuid.UUID, actorID uuid.UUID) (uuid.UUID, error) {
+	if len(expenseIDs) == 0 {
+		return uuid.Nil, ErrInvalidInput.Msg("no expense ids provided")
+	}
+
+	var failedID uuid.UUID
+	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		for _, expenseID := range expenseIDs {
+			if err := setExpenseDeletedTx(ctx, tx, expenseID, actorID, true); err != nil {
+				failedID = expenseID
+				return err
+			}
+		}
+		return nil
+	})
+	return failedID, err
+}
+
+// DeleteGroupExpenses moves every live expense of a group to the trash in one transaction, leaving settlements alone.
+// If keepCovered is set, expenses covered by a live settlement are kept as well.
+// A single entry summarising the count is recorded in the activity log as made by actorID.
+// Returns the number of expenses deleted.
+func DeleteGroupExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, actorID uuid.UUID, keepCovered bool) (int64, error) {
+	if groupID == uuid.Nil {
+		return 0, ErrInvalidInput.Msg("group id missing")
+	}
+
+	var deleted int64
+	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			`UPDATE expenses e SET deleted_at = NOW()
+			WHERE e.group_id = $1
+				AND e.deleted_at IS NULL
+				AND NOT e.is_settlement
+				AND NOT ($2 AND EXISTS (
+					SELECT 1
+					FROM settlement_covers sc
+					JOIN expenses s ON s.expense_id = sc.settlement_id
+					WHERE sc.expense_id = e.expense_id AND s.deleted_at IS NULL
+				))`,
+			groupID, keepCovered,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete group expenses: %w", err)
+		}
+		deleted = result.RowsAffected()
+		if deleted == 0 {
+			return nil
 		}
 
+		_, err = tx.Exec(ctx,
+			`INSERT INTO activity_log (group_id, actor_id, action, target_type, summary)
+			VALUES ($1, $2, $3, $4, $5)`,
+			groupID, actorID, ActivityDeleted, TargetExpense, fmt.Sprintf("all expenses (%d)", deleted),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record activity: %w", err)
+		}
 		return nil
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return deleted, nil
+}
 
-	return nil
+// setExpenseDeleted moves an expense into (deleted) or out of the trash and logs the change.
+func setExpenseDeleted(ctx context.Context, pool *pgxpool.Pool, expenseID, actorID uuid.UUID, deleted bool) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		return setExpenseDeletedTx(ctx, tx, expenseID, actorID, deleted)
+	})
 }
 
-// GetExpenses retrieves all expenses for a given group, ordered by creation time descending.
-// Private expenses are only visible to the creator and split participants.
-// Returns an empty slice if no expenses are found.
-// Returns an error if the groupID is empty or the operation fails.
-func GetExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID) ([]models.Expense, error) {
-	// TODO: Add pagination support for large datasets
+// setExpenseDeletedTx is setExpenseDeleted within an existing transaction.
+func setExpenseDeletedTx(ctx context.Context, tx pgx.Tx, expenseID, actorID uuid.UUID, deleted bool) error {
+	query := `UPDATE expenses SET deleted_at = NOW()
+		WHERE expense_id = $1 AND deleted_at IS NULL
+		RETURNING group_id, title, amount, is_settlement, is_private`
+	action := ActivityDeleted
+	if !deleted {
+		query = `UPDATE expenses SET deleted_at = NULL
+			WHERE expense_id = $1 AND deleted_at IS NOT NULL
+			RETURNING group_id, title, amount, is_settlement, is_private`
+		action = ActivityRestored
+	}
 
-	// Validate input
-	if groupID == uuid.Nil {
-		return nil, ErrInvalidInput.Msg("group id missing")
+	var expense models.Expense
+	err := tx.QueryRow(ctx, query, expenseID).Scan(
+		&expense.GroupID, &expense.Title, &expense.Amount, &expense.IsSettlement, &expense.IsPrivate,
+	)
+	if err == pgx.ErrNoRows {
+		if deleted {
+			return ErrNotFound.Msgf("expense with id %s not found", expenseID)
+		}
+		return ErrNotFound.Msgf("deleted expense with id %s not found", expenseID)
 	}
-	if userID == uuid.Nil {
-		return nil, ErrInvalidInput.Msg("user id missing")
+	if err != nil {
+		return fmt.Errorf("failed to %s expense: %w", strings.TrimSuffix(action, "d"), err)
 	}
 
-	// Query to get all expenses for the group
-	// Private expenses are filtered to only show to creator or split participants
-	expensesQuery := `SELECT expense_id,
+	return RecordActivity(ctx, tx, expense.GroupID, actorID, action,
+		expenseTarget(expense.IsSettlement), expenseID,
+		expenseSummary(expense.Title, expense.Amount, expense.IsPrivate))
+}
+
+// PurgeDeletedExpenses permanently deletes expenses that have been in the trash for longer than retention.
+// Splits are removed by the cascading foreign key.
+func PurgeDeletedExpenses(ctx context.Context, pool *pgxpool.Pool, retention time.Duration) (int64, error) {
+	result, err := pool.Exec(ctx,
+		`DELETE FROM expenses WHERE deleted_at <= NOW() - make_interval(secs => $1)`,
+		retention.Seconds(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+// expenseColumns selects the columns scanned by scanExpenses, in order.
+const expenseColumns = `expense_id,
 		group_id,
+		seq,
 		added_by,
 		title,
 		description,
@@ -350,29 +813,22 @@ func GetExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.U
 		is_settlement,
 		is_private,
 		latitude,
-		longitude
-	FROM expenses
-	WHERE group_id = $1
-		AND is_settlement = false
-		AND (
-			is_private = false
-			OR added_by = $2
-			OR expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = $2)
-		)
-	ORDER BY created_at DESC`
+		longitude,
+		payment_method,
+		category,
+		extract(epoch from deleted_at)::bigint`
 
-	rows, err := pool.Query(ctx, expensesQuery, groupID, userID)
-	if err != nil {
-		return nil, err
-	}
+// scanExpenses reads rows selected with expenseColumns into a slice of expenses.
+func scanExpenses(rows pgx.Rows) ([]models.Expense, error) {
 	defer rows.Close()
 
 	expenses := make([]models.Expense, 0)
 	for rows.Next() {
 		var expense models.Expense
-		err = rows.Scan(
+		err := rows.Scan(
 			&expense.ExpenseID,
 			&expense.GroupID,
+			&expense.Seq,
 			&expense.AddedBy,
 			&expense.Title,
 			&expense.Description,
@@ -385,6 +841,9 @@ func GetExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.U
 			&expense.IsPrivate,
 			&expense.Latitude,
 			&expense.Longitude,
+			&expense.PaymentMethod,
+			&expense.Category,
+			&expense.DeletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -399,6 +858,336 @@ func GetExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.U
 	return expenses, nil
 }
 
+// expenseSortColumns whitelists the columns expense lists may be ordered by, keyed by the API sort name.
+// The values are interpolated into ORDER BY, so they must never come from user input.
+var expenseSortColumns = map[string]string{
+	ExpenseSortCreatedAt:    "created_at",
+	ExpenseSortTransactedAt: "transacted_at",
+	ExpenseSortAmount:       "amount",
+	ExpenseSortTitle:        "lower(title)",
+}
+
+// Sort keys accepted by GetExpenses
+const (
+	ExpenseSortCreatedAt    = "created_at"
+	ExpenseSortTransactedAt = "transacted_at"
+	ExpenseSortAmount       = "amount"
+	ExpenseSortTitle        = "title"
+)
+
+// IsExpenseSort reports whether sort is a key GetExpenses can order by.
+func IsExpenseSort(sort string) bool {
+	_, ok := expenseSortColumns[sort]
+	return ok
+}
+
+// ExpenseListOptions orders and filters the expenses returned by GetExpenses.
+// The zero value lists all expenses, newest first.
+type ExpenseListOptions struct {
+	Sort         string     // One of the ExpenseSort keys, ExpenseSortCreatedAt if empty
+	Ascending    bool       // Order smallest/oldest first instead of largest/newest first
+	AddedBy      *uuid.UUID // Only expenses added by this user
+	IsSettlement bool       // List settlements instead of regular expenses
+}
+
+// GetExpenses retrieves the expenses of a group, ordered and filtered by opts.
+// Ties are broken by creation time, newest first.
+// Private expenses are only visible to the creator and split participants.
+// Returns an empty slice if no expenses are found.
+// Returns an error if the groupID is empty, the sort key is unknown or the operation fails.
+func GetExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID, opts ExpenseListOptions) ([]models.Expense, error) {
+	// TODO: Add pagination support for large datasets
+
+	// Validate input
+	if groupID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("group id missing")
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+	if opts.Sort == "" {
+		opts.Sort = ExpenseSortCreatedAt
+	}
+	sortColumn, ok := expenseSortColumns[opts.Sort]
+	if !ok {
+		return nil, ErrInvalidInput.Msgf("unknown sort key: %s", opts.Sort)
+	}
+	direction := "DESC"
+	if opts.Ascending {
+		direction = "ASC"
+	}
+
+	// Private expenses are filtered to only show to creator or split participants
+	var where QueryBuilder
+	where.Where("group_id = ?", groupID).
+		Where("is_settlement = ?", opts.IsSettlement).
+		Where("deleted_at IS NULL").
+		Where(`(
+			is_private = false
+			OR added_by = ?
+			OR expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = ?)
+		)`, userID, userID)
+	if opts.AddedBy != nil {
+		where.Where("added_by = ?", *opts.AddedBy)
+	}
+
+	expensesQuery := `SELECT ` + expenseColumns + `
+	FROM expenses
+	` + where.WhereClause() + `
+	ORDER BY ` + sortColumn + ` ` + direction + `, created_at DESC`
+
+	rows, err := pool.Query(ctx, expensesQuery, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return scanExpenses(rows)
+}
+
+// GetDeletedExpenses retrieves the group's soft-deleted expenses, most recently deleted first.
+// Settlements are excluded and private expenses are only visible to the creator and split participants,
+// as in GetExpenses.
+func GetDeletedExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID) ([]models.Expense, error) {
+	if groupID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("group id missing")
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+
+	query := `SELECT ` + expenseColumns + `
+	FROM expenses
+	WHERE group_id = $1
+		AND is_settlement = false
+		AND deleted_at IS NOT NULL
+		AND (
+			is_private = false
+			OR added_by = $2
+			OR expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = $2)
+		)
+	ORDER BY deleted_at DESC`
+
+	rows, err := pool.Query(ctx, query, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanExpenses(rows)
+}
+
+// ExpenseFilter narrows down an expense search. Zero values mean "no constraint".
+type ExpenseFilter struct {
+	Query     string   // Case-insensitive substring of the title or description
+	MinAmount *float64 // Inclusive
+	MaxAmount *float64 // Inclusive
+	From      *int64   // Inclusive created_at lower bound, unix seconds
+	To        *int64   // Inclusive created_at upper bound, unix seconds
+}
+
+// SearchExpenses returns the group's expenses matching the filter, ordered by creation time descending.
+// Settlements are excluded and private expenses are only visible to the creator and split participants,
+// as in GetExpenses.
+// Returns ErrInvalidInput if an amount or date range is inverted.
+func SearchExpenses(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID, filter ExpenseFilter) ([]models.Expense, error) {
+	if groupID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("group id missing")
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, ErrInvalidInput.Msg("min_amount must not be greater than max_amount")
+	}
+	if filter.From != nil && filter.To != nil && *filter.From > *filter.To {
+		return nil, ErrInvalidInput.Msg("from must not be after to")
+	}
+
+	var where QueryBuilder
+	where.Where("group_id = ?", groupID).
+		Where("is_settlement = false").
+		Where("deleted_at IS NULL").
+		Where(`(
+			is_private = false
+			OR added_by = ?
+			OR expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = ?)
+		)`, userID, userID)
+	if filter.Query != "" {
+		pattern := escapeLike(filter.Query)
+		where.Where(`(title ILIKE '%' || ? || '%' ESCAPE '\' OR description ILIKE '%' || ? || '%' ESCAPE '\')`, pattern, pattern)
+	}
+	if filter.MinAmount != nil {
+		where.Where("amount >= ?::numeric", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		where.Where("amount <= ?::numeric", *filter.MaxAmount)
+	}
+	if filter.From != nil {
+		where.Where("created_at >= to_timestamp(?::bigint)", *filter.From)
+	}
+	if filter.To != nil {
+		where.Where("created_at <= to_timestamp(?::bigint)", *filter.To)
+	}
+
+	query := `SELECT ` + expenseColumns + `
+	FROM expenses
+	` + where.WhereClause() + `
+	ORDER BY created_at DESC`
+
+	rows, err := pool.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return scanExpenses(rows)
+}
+
+// GetUserSpendByCategory sums the user's owed splits across all their groups, grouped by
+// expense category and group currency, largest first.
+// Settlements and deleted expenses are excluded. from and to are optional inclusive created_at
+// bounds in unix seconds, as in ExpenseFilter. Uncategorized expenses are reported under a nil
+// category unless includeUncategorized is false.
+// Returns ErrInvalidInput if the date range is inverted.
+func GetUserSpendByCategory(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, from, to *int64, includeUncategorized bool) ([]models.CategorySpend, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+	if from != nil && to != nil && *from > *to {
+		return nil, ErrInvalidInput.Msg("from must not be after to")
+	}
+
+	query := `SELECT e.category, g.base_currency,
+		SUM(es.amount)::float8 AS total,
+		COUNT(DISTINCT e.expense_id)
+	FROM expense_splits es
+	JOIN expenses e ON e.expense_id = es.expense_id
+	JOIN groups g ON g.group_id = e.group_id
+	WHERE es.user_id = $1
+		AND es.is_paid = false
+		AND e.is_settlement = false
+		AND e.deleted_at IS NULL
+		AND ($2::bigint IS NULL OR e.created_at >= to_timestamp($2::bigint))
+		AND ($3::bigint IS NULL OR e.created_at <= to_timestamp($3::bigint))
+		AND ($4 OR e.category IS NOT NULL)
+	GROUP BY e.category, g.base_currency
+	ORDER BY total DESC, e.category`
+
+	rows, err := pool.Query(ctx, query, userID, from, to, includeUncategorized)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spend := make([]models.CategorySpend, 0)
+	for rows.Next() {
+		var entry models.CategorySpend
+		if err := rows.Scan(&entry.Category, &entry.Currency, &entry.Amount, &entry.Count); err != nil {
+			return nil, err
+		}
+		spend = append(spend, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return spend, nil
+}
+
+// GetUserTotalSpending aggregates the user's paid and owed splits across every group they belong to,
+// with a per-group breakdown listing the groups the user pinned first, then by group name.
+// Groups without activity are included with zero totals.
+// Overall totals are summed per base currency and sorted by currency, as amounts in different
+// currencies cannot be added up.
+// Settlements count towards TotalPaid and TotalOwed but not NetSpending (see models.SpendingTotals).
+// Sums are accumulated in NUMERIC by PostgreSQL, both per group and per currency, to avoid float drift.
+func GetUserTotalSpending(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (models.UserSpending, error) {
+	if userID == uuid.Nil {
+		return models.UserSpending{}, ErrInvalidInput.Msg("user id missing")
+	}
+
+	// The base_currency grouping set adds each currency's totals as a row with a NULL group_id
+	query := `SELECT g.group_id, g.group_name, g.base_currency,
+		COALESCE(SUM(es.amount) FILTER (WHERE es.is_paid), 0)::float8,
+		COALESCE(SUM(es.amount) FILTER (WHERE NOT es.is_paid), 0)::float8,
+		COALESCE(SUM(es.amount) FILTER (WHERE NOT es.is_paid AND NOT e.is_settlement), 0)::float8
+	FROM group_members gm
+	JOIN groups g ON g.group_id = gm.group_id
+	LEFT JOIN (
+		expense_splits es
+		JOIN expenses e ON e.expense_id = es.expense_id AND e.deleted_at IS NULL
+	) ON e.group_id = gm.group_id AND es.user_id = gm.user_id
+	WHERE gm.user_id = $1
+	GROUP BY GROUPING SETS ((g.group_id, g.group_name, g.base_currency, gm.pinned), (g.base_currency))
+	ORDER BY gm.pinned DESC, g.group_name, g.group_id, g.base_currency`
+
+	rows, err := pool.Query(ctx, query, userID)
+	if err != nil {
+		return models.UserSpending{}, err
+	}
+	defer rows.Close()
+
+	spending := models.UserSpending{Totals: make([]models.CurrencySpending, 0), Groups: make([]models.GroupSpending, 0)}
+	for rows.Next() {
+		var groupID *uuid.UUID
+		var groupName, currency *string
+		var totals models.SpendingTotals
+
+		if err := rows.Scan(&groupID, &groupName, &currency, &totals.TotalPaid, &totals.TotalOwed, &totals.NetSpending); err != nil {
+			return models.UserSpending{}, err
+		}
+
+		if groupID == nil {
+			spending.Totals = append(spending.Totals, models.CurrencySpending{Currency: *currency, SpendingTotals: totals})
+			continue
+		}
+		spending.Groups = append(spending.Groups, models.GroupSpending{
+			GroupID:        *groupID,
+			GroupName:      *groupName,
+			Currency:       *currency,
+			SpendingTotals: totals,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return models.UserSpending{}, err
+	}
+	return spending, nil
+}
+
+// GetGroupCategories returns the distinct categories used by the group's expenses, sorted alphabetically.
+// Settlements and deleted expenses are ignored, and private expenses only contribute for the
+// creator and split participants, as in GetExpenses.
+func GetGroupCategories(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID) ([]string, error) {
+	query := `SELECT DISTINCT category
+	FROM expenses
+	WHERE group_id = $1
+		AND category IS NOT NULL
+		AND is_settlement = false
+		AND deleted_at IS NULL
+		AND (
+			is_private = false
+			OR added_by = $2
+			OR expense_id IN (SELECT expense_id FROM expense_splits WHERE user_id = $2)
+		)
+	ORDER BY category`
+
+	rows, err := pool.Query(ctx, query, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]string, 0)
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
 // GetUserSpending retrieves all expenses where the user owes money in a group.
 // Each returned UserExpense includes the expense details and the user's owed amount.
 func GetUserSpending(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID) ([]models.UserExpense, error) {
@@ -414,6 +1203,7 @@ func GetUserSpending(ctx context.Context, pool *pgxpool.Pool, userID, groupID uu
 		SELECT
 			e.expense_id,
 			e.group_id,
+			e.seq,
 			e.added_by,
 			e.title,
 			e.description,
@@ -426,13 +1216,16 @@ func GetUserSpending(ctx context.Context, pool *pgxpool.Pool, userID, groupID uu
 			e.is_settlement,
 			e.is_private,
 			e.latitude,
-			e.longitude
+			e.longitude,
+			e.payment_method,
+			e.category
 		FROM expenses e
 		JOIN expense_splits es ON e.expense_id = es.expense_id
 		WHERE e.group_id = $1
 			AND es.user_id = $2
 			AND es.is_paid = false
 			AND e.is_settlement = false
+			AND e.deleted_at IS NULL
 		ORDER BY e.created_at DESC
 	`
 
@@ -449,6 +1242,7 @@ func GetUserSpending(ctx context.Context, pool *pgxpool.Pool, userID, groupID uu
 		err = rows.Scan(
 			&expense.ExpenseID,
 			&expense.GroupID,
+			&expense.Seq,
 			&expense.AddedBy,
 			&expense.Title,
 			&expense.Description,
@@ -462,6 +1256,8 @@ func GetUserSpending(ctx context.Context, pool *pgxpool.Pool, userID, groupID uu
 			&expense.IsPrivate,
 			&expense.Latitude,
 			&expense.Longitude,
+			&expense.PaymentMethod,
+			&expense.Category,
 		)
 		if err != nil {
 			return nil, err
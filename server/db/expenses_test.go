@@ -0,0 +1,150 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/db/dbtest"
+	"github.com/pranaovs/qashare/models"
+)
+
+func TestCreateExpenseRejectsNegativeAmounts(t *testing.T) {
+	payer, debtor := uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		expense models.ExpenseDetails
+	}{
+		{"negative split", models.ExpenseDetails{
+			Expense: models.Expense{Title: "Dinner", Amount: 10},
+			Splits:  []models.ExpenseSplit{dbtest.Paid(payer, 15), dbtest.Owes(debtor, -5)},
+		}},
+		{"negative amount", models.ExpenseDetails{
+			Expense: models.Expense{Title: "Dinner", Amount: -10},
+			Splits:  []models.ExpenseSplit{dbtest.Paid(payer, 10), dbtest.Owes(debtor, 10)},
+		}},
+		{"negative split of a settlement", models.ExpenseDetails{
+			Expense: models.Expense{Title: "Settlement", Amount: 10, IsSettlement: true},
+			Splits:  []models.ExpenseSplit{dbtest.Paid(payer, -10), dbtest.Owes(debtor, -10)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Rejected before a connection is needed, so no pool is passed
+			err := db.CreateExpense(context.Background(), nil, &tt.expense)
+			if !errors.Is(err, db.ErrInvalidInput) {
+				t.Errorf("CreateExpense = %v, want ErrInvalidInput", err)
+			}
+
+			tt.expense.ExpenseID = uuid.New()
+			err = db.UpdateExpense(context.Background(), nil, &tt.expense, payer)
+			if !errors.Is(err, db.ErrInvalidInput) {
+				t.Errorf("UpdateExpense = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestCreateExpenseAcceptsSettlementAndIncompleteAmount(t *testing.T) {
+	pool := dbtest.Pool(t)
+	payer, debtor := dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, payer.UserID, debtor.UserID)
+
+	t.Run("settlement", func(t *testing.T) {
+		settlement := dbtest.Settlement(t, pool, group.GroupID, debtor.UserID, payer.UserID, 10)
+		splits, err := db.GetExpenseSplits(context.Background(), pool, settlement.ExpenseID)
+		if err != nil {
+			t.Fatalf("GetExpenseSplits: %v", err)
+		}
+		if len(splits) != 2 {
+			t.Errorf("got %d splits, want the paid and the owed split", len(splits))
+		}
+	})
+
+	t.Run("negative amount flagged incomplete", func(t *testing.T) {
+		expense := models.ExpenseDetails{
+			Expense: models.Expense{
+				GroupID:            group.GroupID,
+				AddedBy:            payer.UserID,
+				Title:              "Draft",
+				Amount:             -10,
+				IsIncompleteAmount: true,
+			},
+			Splits: []models.ExpenseSplit{dbtest.Paid(payer.UserID, 10)},
+		}
+		if err := db.CreateExpense(context.Background(), pool, &expense); err != nil {
+			t.Fatalf("CreateExpense = %v, want nil", err)
+		}
+	})
+}
+
+func TestGetExpenseSplitsWithoutSplits(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	payer, debtor := dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, payer.UserID, debtor.UserID)
+	expense := dbtest.Expense(t, pool, group.GroupID, payer.UserID, 10,
+		dbtest.Paid(payer.UserID, 10), dbtest.Owes(debtor.UserID, 10))
+
+	// The API never stores an expense without splits, so remove them underneath it
+	if _, err := pool.Exec(ctx, `DELETE FROM expense_splits WHERE expense_id = $1`, expense.ExpenseID); err != nil {
+		t.Fatalf("delete splits: %v", err)
+	}
+
+	for name, expenseID := range map[string]uuid.UUID{
+		"expense without splits": expense.ExpenseID,
+		"unknown expense":        uuid.New(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			splits, err := db.GetExpenseSplits(ctx, pool, expenseID)
+			if err != nil {
+				t.Fatalf("GetExpenseSplits: %v", err)
+			}
+			// Empty rather than nil, so the response encodes [] instead of null
+			if splits == nil || len(splits) != 0 {
+				t.Errorf("splits = %#v, want an empty slice", splits)
+			}
+		})
+	}
+}
+
+func TestGetUserTotalSpendingTotalsPerCurrency(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	user, friend := dbtest.User(t, pool), dbtest.User(t, pool)
+
+	dollars := dbtest.Group(t, pool, user.UserID, friend.UserID)
+	euros := models.Group{Name: "Trip", CreatedBy: user.UserID, Currency: "EUR"}
+	if err := db.CreateGroup(ctx, pool, &euros); err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if _, _, err := db.AddGroupMembers(ctx, pool, euros.GroupID, user.UserID, []uuid.UUID{friend.UserID}); err != nil {
+		t.Fatalf("add group members: %v", err)
+	}
+
+	dbtest.Expense(t, pool, dollars.GroupID, user.UserID, 30, dbtest.Paid(user.UserID, 30), dbtest.Owes(user.UserID, 10), dbtest.Owes(friend.UserID, 20))
+	dbtest.Expense(t, pool, euros.GroupID, friend.UserID, 50, dbtest.Paid(friend.UserID, 50), dbtest.Owes(user.UserID, 25), dbtest.Owes(friend.UserID, 25))
+
+	spending, err := db.GetUserTotalSpending(ctx, pool, user.UserID)
+	if err != nil {
+		t.Fatalf("GetUserTotalSpending: %v", err)
+	}
+
+	want := []models.CurrencySpending{
+		{Currency: "EUR", SpendingTotals: models.SpendingTotals{TotalPaid: 0, TotalOwed: 25, NetSpending: 25}},
+		{Currency: "USD", SpendingTotals: models.SpendingTotals{TotalPaid: 30, TotalOwed: 10, NetSpending: 10}},
+	}
+	if len(spending.Totals) != len(want) {
+		t.Fatalf("totals = %+v, want one entry per currency", spending.Totals)
+	}
+	for i := range want {
+		if spending.Totals[i] != want[i] {
+			t.Errorf("totals[%d] = %+v, want %+v", i, spending.Totals[i], want[i])
+		}
+	}
+	if len(spending.Groups) != 2 {
+		t.Errorf("got %d groups, want 2", len(spending.Groups))
+	}
+}
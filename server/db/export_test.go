@@ -0,0 +1,4 @@
+package db
+
+// Exposes unexported helpers to the db_test package.
+var OptimizeSettlements = optimizeSettlements
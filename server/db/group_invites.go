@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
+)
+
+// PeekGroupInvite returns a preview of the group an invite points to without consuming a use.
+// Returns ErrNotFound if the token doesn't exist, or ErrExpiredToken if the invite
+// has expired or has no uses left.
+func PeekGroupInvite(ctx context.Context, pool *pgxpool.Pool, token uuid.UUID) (models.GroupPreview, error) {
+	var preview models.GroupPreview
+	var expiresAt *time.Time
+	var maxUses *int
+	var uses int
+
+	query := `
+		SELECT g.group_id, g.group_name, i.expires_at, i.max_uses, i.uses,
+			(SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.group_id),
+			COALESCE(u.user_name, $2)
+		FROM group_invites i
+		JOIN groups g ON g.group_id = i.group_id
+		LEFT JOIN users u ON u.user_id = g.created_by
+		WHERE i.token = $1`
+
+	err := pool.QueryRow(ctx, query, token, models.DeletedUserName).Scan(
+		&preview.GroupID, &preview.Name, &expiresAt, &maxUses, &uses, &preview.MemberCount, &preview.CreatorName,
+	)
+	if err == pgx.ErrNoRows {
+		return models.GroupPreview{}, ErrNotFound.Msg("invite not found")
+	}
+	if err != nil {
+		return models.GroupPreview{}, err
+	}
+
+	if expiresAt != nil && time.Now().After(*expiresAt) {
+		return models.GroupPreview{}, ErrExpiredToken.Msg("invite has expired")
+	}
+	if maxUses != nil && uses >= *maxUses {
+		return models.GroupPreview{}, ErrExpiredToken.Msg("invite has no uses left")
+	}
+
+	return preview, nil
+}
+
+// CreateGroupInvite creates a shareable invite to a group with a random token.
+// expiresAt (unix seconds) and maxUses are optional; nil means the invite never expires or has unlimited uses.
+func CreateGroupInvite(ctx context.Context, pool *pgxpool.Pool, groupID, createdBy uuid.UUID, expiresAt *int64, maxUses *int) (models.GroupInvite, error) {
+	if groupID == uuid.Nil {
+		return models.GroupInvite{}, ErrInvalidInput.Msg("group id missing")
+	}
+	if maxUses != nil && *maxUses < 1 {
+		return models.GroupInvite{}, ErrInvalidInput.Msg("max_uses must be at least 1")
+	}
+
+	invite := models.GroupInvite{GroupID: groupID, CreatedBy: &createdBy, MaxUses: maxUses}
+	err := pool.QueryRow(ctx,
+		`INSERT INTO group_invites (group_id, created_by, expires_at, max_uses)
+		VALUES ($1, $2, to_timestamp($3::bigint), $4)
+		RETURNING invite_id, token, extract(epoch from created_at)::bigint, extract(epoch from expires_at)::bigint`,
+		groupID, createdBy, expiresAt, maxUses,
+	).Scan(&invite.InviteID, &invite.Token, &invite.CreatedAt, &invite.ExpiresAt)
+	if err != nil {
+		if IsConstraintViolation(err) {
+			return models.GroupInvite{}, ErrNotFound.Msgf("group with id %s not found", groupID)
+		}
+		return models.GroupInvite{}, err
+	}
+
+	return invite, nil
+}
+
+// RedeemGroupInvite adds userID to the group of the invite and consumes one of its uses, in a single transaction.
+// The invite row is locked so concurrent redemptions cannot exceed max_uses.
+// Redeeming is idempotent: a user who is already a member gets the group back with joined=false and no use is consumed.
+// Returns ErrNotFound if the token doesn't exist, or ErrExpiredToken if the invite has expired or has no uses left.
+func RedeemGroupInvite(ctx context.Context, pool *pgxpool.Pool, token, userID uuid.UUID) (groupID uuid.UUID, joined bool, err error) {
+	err = WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		var inviteID uuid.UUID
+		var expiresAt *time.Time
+		var maxUses *int
+		var uses int
+		err := tx.QueryRow(ctx,
+			`SELECT invite_id, group_id, expires_at, max_uses, uses
+			FROM group_invites
+			WHERE token = $1
+			FOR UPDATE`,
+			token,
+		).Scan(&inviteID, &groupID, &expiresAt, &maxUses, &uses)
+		if err == pgx.ErrNoRows {
+			return ErrNotFound.Msg("invite not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		var isMember bool
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM group_members WHERE group_id = $1 AND user_id = $2)`,
+			groupID, userID,
+		).Scan(&isMember)
+		if err != nil {
+			return err
+		}
+		if isMember {
+			return nil
+		}
+
+		if expiresAt != nil && time.Now().After(*expiresAt) {
+			return ErrExpiredToken.Msg("invite has expired")
+		}
+		if maxUses != nil && uses >= *maxUses {
+			return ErrExpiredToken.Msg("invite has no uses left")
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO group_members (user_id, group_id, joined_at) VALUES ($1, $2, $3)`,
+			userID, groupID, time.Now(),
+		)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `UPDATE group_invites SET uses = uses + 1 WHERE invite_id = $1`, inviteID)
+		if err != nil {
+			return err
+		}
+		joined = true
+
+		if err := recordMemberActivity(ctx, tx, groupID, userID, ActivityAdded, []uuid.UUID{userID}); err != nil {
+			return err
+		}
+		return enqueueWebhookEvent(ctx, tx, groupID, userID, models.WebhookMemberAdded, models.WebhookMembers{UserIDs: []uuid.UUID{userID}})
+	})
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	return groupID, joined, nil
+}
+
+// GetGroupInviteGroup returns the ID of the group an invite token points to.
+// Returns ErrNotFound if the token doesn't exist.
+func GetGroupInviteGroup(ctx context.Context, pool *pgxpool.Pool, token uuid.UUID) (uuid.UUID, error) {
+	var groupID uuid.UUID
+	err := pool.QueryRow(ctx, `SELECT group_id FROM group_invites WHERE token = $1`, token).Scan(&groupID)
+	if err == pgx.ErrNoRows {
+		return uuid.Nil, ErrNotFound.Msg("invite not found")
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return groupID, nil
+}
+
+// DeleteGroupInvite revokes an invite of a group, so its token can no longer be redeemed.
+// Memberships created through the invite are kept.
+// Returns ErrNotFound if the group has no invite with the ID.
+func DeleteGroupInvite(ctx context.Context, pool *pgxpool.Pool, groupID, inviteID uuid.UUID) error {
+	result, err := pool.Exec(ctx, `DELETE FROM group_invites WHERE invite_id = $1 AND group_id = $2`, inviteID, groupID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound.Msgf("invite with id %s not found", inviteID)
+	}
+	return nil
+}
@@ -5,11 +5,13 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -23,32 +25,33 @@ import (
 func CreateGroup(ctx context.Context, pool *pgxpool.Pool, group *models.Group) error {
 	// Use WithTransaction helper for consistent transaction management
 	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
-		// Insert the group
-		query := `INSERT INTO groups (group_name, description, created_by, is_private)
-			VALUES ($1, $2, $3, $4)
-			RETURNING group_id, extract(epoch from created_at)::bigint`
-
-		err := tx.QueryRow(ctx, query, group.Name, group.Description, group.CreatedBy, group.Private).Scan(&group.GroupID, &group.CreatedAt)
-		if err != nil {
-			return err
-		}
+		return insertGroup(ctx, tx, group)
+	})
+	if err != nil {
+		return err
+	}
 
-		// Add creator as the first member
-		memberQuery := `INSERT INTO group_members (user_id, group_id, joined_at)
-			VALUES ($1, $2, $3)`
+	return nil
+}
 
-		_, err = tx.Exec(ctx, memberQuery, group.CreatedBy, group.GroupID, time.Now())
-		if err != nil {
-			return err
-		}
+// insertGroup inserts the group and its creator's membership within an existing transaction.
+// The group's GroupID and CreatedAt fields are populated on success.
+func insertGroup(ctx context.Context, tx pgx.Tx, group *models.Group) error {
+	query := `INSERT INTO groups (group_name, description, created_by, is_private, base_currency, require_description)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING group_id, extract(epoch from created_at)::bigint`
 
-		return nil
-	})
+	err := tx.QueryRow(ctx, query, group.Name, group.Description, group.CreatedBy, group.Private, group.Currency, group.RequireDesc).Scan(&group.GroupID, &group.CreatedAt)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Add creator as the first member
+	memberQuery := `INSERT INTO group_members (user_id, group_id, joined_at)
+		VALUES ($1, $2, $3)`
+
+	_, err = tx.Exec(ctx, memberQuery, group.CreatedBy, group.GroupID, time.Now())
+	return err
 }
 
 // GetGroupCreator retrieves the user ID of the group creator.
@@ -76,7 +79,7 @@ func GetGroup(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (model
 	var group models.GroupDetails
 
 	query := `SELECT g.group_id, g.group_name, g.description, g.created_by,
-		extract(epoch from g.created_at)::bigint, g.is_private,
+		extract(epoch from g.created_at)::bigint, g.is_private, g.base_currency, g.require_description,
 		u.user_id, u.user_name, u.email, u.is_guest,
 		extract(epoch from gm.joined_at)::bigint
 	FROM groups g
@@ -107,6 +110,8 @@ func GetGroup(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (model
 			&group.CreatedBy,
 			&group.CreatedAt,
 			&group.Private,
+			&group.Currency,
+			&group.RequireDesc,
 			&memberUserID,
 			&memberName,
 			&memberEmail,
@@ -120,13 +125,19 @@ func GetGroup(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (model
 
 		// Skip NULL members (group has no members)
 		if memberUserID != nil {
-			group.Members = append(group.Members, models.GroupUser{
+			member := models.GroupUser{
 				UserID:   *memberUserID,
 				Name:     *memberName,
 				Email:    *memberEmail,
 				Guest:    *memberGuest,
 				JoinedAt: *memberJoinedAt,
-			})
+			}
+			// Don't expose the placeholder email of deleted users
+			if isDeletedEmail(member.Email) {
+				member.Email = ""
+				member.Deleted = true
+			}
+			group.Members = append(group.Members, member)
 		}
 	}
 
@@ -142,19 +153,29 @@ func GetGroup(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (model
 }
 
 // AddGroupMembers adds multiple users to a group in a single batch operation.
-// Uses batch operations for better performance when adding many members at once.
-// Ignores duplicate memberships (ON CONFLICT DO NOTHING).
+// Users that already belong to the group are left untouched (ON CONFLICT DO NOTHING);
+// RETURNING user_id tells the two cases apart, so newly inserted members are returned
+// in added and the rest in existing.
+// This makes the call idempotent: clients that sync membership can send the full member list
+// without checking it first, and a repeated call adds nobody and records no activity.
+// Duplicate IDs in the input are collapsed before inserting.
+// Each newly added member is recorded in the group's activity log as added by actorID.
 // Returns ErrInvalidInput if no user IDs are provided.
-func AddGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, userIDs []uuid.UUID) error {
+func AddGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID, actorID uuid.UUID, userIDs []uuid.UUID) (added []uuid.UUID, existing []uuid.UUID, err error) {
 	if len(userIDs) == 0 {
-		return ErrInvalidInput.Msg("no user IDs provided")
+		return nil, nil, ErrInvalidInput.Msg("no user IDs provided")
 	}
 
-	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+	userIDs = utils.GetUniqueUserIDs(userIDs)
+	added = make([]uuid.UUID, 0, len(userIDs))
+	existing = make([]uuid.UUID, 0)
+
+	err = WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
 		batch := &pgx.Batch{}
 		insertQuery := `INSERT INTO group_members (user_id, group_id, joined_at)
 			VALUES ($1, $2, $3)
-			ON CONFLICT (user_id, group_id) DO NOTHING`
+			ON CONFLICT (user_id, group_id) DO NOTHING
+			RETURNING user_id`
 
 		now := time.Now()
 		for _, userID := range userIDs {
@@ -168,15 +189,36 @@ func AddGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID,
 			}
 		}()
 
-		for range userIDs {
-			_, err := br.Exec()
+		for _, userID := range userIDs {
+			var insertedID uuid.UUID
+			err := br.QueryRow().Scan(&insertedID)
+			if err == pgx.ErrNoRows {
+				// Conflict: the user was already a member
+				existing = append(existing, userID)
+				continue
+			}
 			if err != nil {
 				return err
 			}
+			added = append(added, insertedID)
+		}
+		if err := br.Close(); err != nil {
+			return err
 		}
 
-		return nil
+		if err := recordMemberActivity(ctx, tx, groupID, actorID, ActivityAdded, added); err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		return enqueueWebhookEvent(ctx, tx, groupID, actorID, models.WebhookMemberAdded, models.WebhookMembers{UserIDs: added})
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return added, existing, nil
 }
 
 // AddGroupMember adds a single user to a group.
@@ -195,6 +237,164 @@ func AddGroupMember(ctx context.Context, pool *pgxpool.Pool, groupID, userID uui
 	return nil
 }
 
+// GetGroupLabels returns the name and base currency of a group together with the names of the given users,
+// in a single query. Users that no longer exist are named DeletedUserName; the order of userIDs is kept.
+// Returns ErrNotFound if the group does not exist.
+func GetGroupLabels(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, userIDs []uuid.UUID) (string, string, []models.Participant, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT g.group_name, g.base_currency, p.user_id, COALESCE(u.user_name, $3)
+		FROM groups g
+		LEFT JOIN unnest($2::uuid[]) WITH ORDINALITY AS p(user_id, ord) ON true
+		LEFT JOIN users u ON u.user_id = p.user_id
+		WHERE g.group_id = $1
+		ORDER BY p.ord`,
+		groupID, userIDs, models.DeletedUserName,
+	)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer rows.Close()
+
+	var name, currency string
+	found := false
+	participants := make([]models.Participant, 0, len(userIDs))
+	for rows.Next() {
+		var userID *uuid.UUID
+		var userName string
+		if err := rows.Scan(&name, &currency, &userID, &userName); err != nil {
+			return "", "", nil, err
+		}
+		found = true
+		if userID != nil {
+			participants = append(participants, models.Participant{UserID: *userID, Name: userName})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return "", "", nil, err
+	}
+	if !found {
+		return "", "", nil, ErrNotFound.Msgf("group with id %s not found", groupID)
+	}
+
+	return name, currency, participants, nil
+}
+
+// GetGroupPreview returns the reduced view of a group shown to non-members: its name,
+// member count and creator name, without the member list.
+// Returns ErrNotFound if the group does not exist.
+func GetGroupPreview(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (models.GroupPreview, error) {
+	var preview models.GroupPreview
+	err := pool.QueryRow(ctx,
+		`SELECT g.group_id, g.group_name,
+			(SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.group_id),
+			COALESCE(u.user_name, $2)
+		FROM groups g
+		LEFT JOIN users u ON u.user_id = g.created_by
+		WHERE g.group_id = $1`,
+		groupID, models.DeletedUserName,
+	).Scan(&preview.GroupID, &preview.Name, &preview.MemberCount, &preview.CreatorName)
+	if err == pgx.ErrNoRows {
+		return models.GroupPreview{}, ErrNotFound.Msgf("group with id %s not found", groupID)
+	}
+	if err != nil {
+		return models.GroupPreview{}, err
+	}
+	return preview, nil
+}
+
+// SetGroupPinned pins or unpins the group in the user's own group list.
+// Returns ErrNotFound if the user is not a member of the group.
+func SetGroupPinned(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, pinned bool) error {
+	result, err := pool.Exec(ctx,
+		`UPDATE group_members SET pinned = $3 WHERE user_id = $1 AND group_id = $2`,
+		userID, groupID, pinned,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound.Msgf("user %s is not a member of group %s", userID, groupID)
+	}
+	return nil
+}
+
+// GetGroupMembers returns the members of a group, oldest first, without the group metadata.
+// Returns an empty list if the group has no members or does not exist.
+func GetGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) ([]models.GroupUser, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT u.user_id, u.user_name, u.email, u.is_guest, extract(epoch from gm.joined_at)::bigint
+		FROM group_members gm
+		JOIN users u ON u.user_id = gm.user_id
+		WHERE gm.group_id = $1
+		ORDER BY gm.joined_at ASC`,
+		groupID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := make([]models.GroupUser, 0)
+	for rows.Next() {
+		var member models.GroupUser
+		if err := rows.Scan(&member.UserID, &member.Name, &member.Email, &member.Guest, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		// Don't expose the placeholder email of deleted users
+		if isDeletedEmail(member.Email) {
+			member.Email = ""
+			member.Deleted = true
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// CountGroupMembers returns the number of members in a group without loading them.
+func CountGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (int, error) {
+	count, err := CountRecords(ctx, pool, "group_members", "group_id = $1", groupID)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// GroupRequiresDescription reports whether expenses in the group must have a description.
+// Returns ErrNotFound if no group with the ID exists.
+func GroupRequiresDescription(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) (bool, error) {
+	var required bool
+	err := pool.QueryRow(ctx, `SELECT require_description FROM groups WHERE group_id = $1`, groupID).Scan(&required)
+	if err == pgx.ErrNoRows {
+		return false, ErrNotFound.Msgf("group with id %s not found", groupID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return required, nil
+}
+
+// CountOwnedGroups returns the number of groups created by a user.
+func CountOwnedGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (int, error) {
+	count, err := CountRecords(ctx, pool, "groups", "created_by = $1", userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// CountExistingGroupMembers returns how many of the given users are already members of a group.
+func CountExistingGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, userIDs []uuid.UUID) (int, error) {
+	count, err := CountRecords(ctx, pool, "group_members", "group_id = $1 AND user_id = ANY($2)", groupID, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
 // RemoveGroupMember removes a single user from a group.
 // Note: The database will handle cascading deletes for related expenses if configured.
 func RemoveGroupMember(ctx context.Context, pool *pgxpool.Pool, groupID, userID uuid.UUID) error {
@@ -216,14 +416,33 @@ func RemoveGroupMember(ctx context.Context, pool *pgxpool.Pool, groupID, userID
 
 // RemoveGroupMembers removes multiple users from a group in a single atomic batch operation.
 // Uses a transaction so that either all removals succeed or none do.
+// Balances are checked in the same transaction, under the group row lock that creating an expense or
+// settlement also takes, so a debt recorded concurrently cannot land between the check and the removal.
+// Members whose balance is not settled within tolerance are returned in outstanding, keyed by user. Unless
+// force is set, nothing is removed and ErrOutstandingBalance is returned with them.
+// Each removal is recorded in the group's activity log as made by actorID; entries of members removed
+// despite an outstanding balance note the balance left behind.
 // Returns ErrNotFound if any user is not a member of the group.
 // Returns ErrInvalidInput if no user IDs are provided.
-func RemoveGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, userIDs []uuid.UUID) error {
+func RemoveGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID, actorID uuid.UUID, userIDs []uuid.UUID, force bool, tolerance float64) (outstanding map[uuid.UUID]float64, err error) {
 	if len(userIDs) == 0 {
-		return ErrInvalidInput.Msg("no user IDs provided")
+		return nil, ErrInvalidInput.Msg("no user IDs provided")
 	}
 
-	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+	err = WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT 1 FROM groups WHERE group_id = $1 FOR UPDATE`, groupID); err != nil {
+			return err
+		}
+
+		var err error
+		outstanding, err = getOutstandingBalances(ctx, tx, groupID, userIDs, tolerance)
+		if err != nil {
+			return err
+		}
+		if len(outstanding) > 0 && !force {
+			return ErrOutstandingBalance.Msgf("%d members have an outstanding balance", len(outstanding))
+		}
+
 		batch := &pgx.Batch{}
 		deleteQuery := `DELETE FROM group_members
 			WHERE user_id = $1 AND group_id = $2`
@@ -248,9 +467,36 @@ func RemoveGroupMembers(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UU
 				return ErrNotFound.Msgf("user %s is not a member of the group", userID)
 			}
 		}
+		if err := br.Close(); err != nil {
+			return err
+		}
 
-		return nil
+		settled := make([]uuid.UUID, 0, len(userIDs))
+		for _, userID := range userIDs {
+			balance, ok := outstanding[userID]
+			if !ok {
+				settled = append(settled, userID)
+				continue
+			}
+			_, err := tx.Exec(ctx,
+				`INSERT INTO activity_log (group_id, actor_id, action, target_type, target_id, summary)
+				SELECT $1, $2, $3, $4, u.user_id, u.user_name || $6
+				FROM users u
+				WHERE u.user_id = $5`,
+				groupID, actorID, ActivityRemoved, TargetMember, userID,
+				fmt.Sprintf(" (outstanding balance %.2f)", balance),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to record activity: %w", err)
+			}
+		}
+
+		return recordMemberActivity(ctx, tx, groupID, actorID, ActivityRemoved, settled)
 	})
+	if err != nil && !IsOutstandingBalance(err) {
+		return nil, err
+	}
+	return outstanding, err
 }
 
 // UpdateGroup updates an existing group's editable fields (name and description).
@@ -266,9 +512,12 @@ func UpdateGroup(ctx context.Context, pool *pgxpool.Pool, group *models.Group) e
 	}
 
 	// Update group fields
+	// An empty currency keeps the current one
 	updateQuery := `UPDATE groups
 		SET group_name = $2,
-			description = $3
+			description = $3,
+			base_currency = COALESCE(NULLIF($4, ''), base_currency),
+			require_description = $5
 		WHERE group_id = $1`
 
 	result, err := pool.Exec(
@@ -277,6 +526,8 @@ func UpdateGroup(ctx context.Context, pool *pgxpool.Pool, group *models.Group) e
 		group.GroupID,
 		group.Name,
 		group.Description,
+		group.Currency,
+		group.RequireDesc,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,50 @@
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/db/dbtest"
+)
+
+func TestRemoveGroupMembersChecksBalancesInTheTransaction(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	owner, debtor, settled := dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, owner.UserID, debtor.UserID, settled.UserID)
+	dbtest.Expense(t, pool, group.GroupID, owner.UserID, 30, dbtest.Paid(owner.UserID, 30), dbtest.Owes(debtor.UserID, 30))
+
+	isMember := func(userID uuid.UUID) bool {
+		t.Helper()
+		member, err := db.MemberOfGroup(ctx, pool, userID, group.GroupID)
+		if err != nil {
+			t.Fatalf("MemberOfGroup: %v", err)
+		}
+		return member
+	}
+	both := []uuid.UUID{debtor.UserID, settled.UserID}
+
+	outstanding, err := db.RemoveGroupMembers(ctx, pool, group.GroupID, owner.UserID, both, false, 0.01)
+	if !db.IsOutstandingBalance(err) {
+		t.Fatalf("RemoveGroupMembers without force = %v, want ErrOutstandingBalance", err)
+	}
+	if len(outstanding) != 1 || outstanding[debtor.UserID] != -30 {
+		t.Errorf("outstanding = %v, want only the debtor at -30", outstanding)
+	}
+	if !isMember(debtor.UserID) || !isMember(settled.UserID) {
+		t.Error("a refused removal removed members")
+	}
+
+	outstanding, err = db.RemoveGroupMembers(ctx, pool, group.GroupID, owner.UserID, both, true, 0.01)
+	if err != nil {
+		t.Fatalf("RemoveGroupMembers with force = %v, want nil", err)
+	}
+	if outstanding[debtor.UserID] != -30 {
+		t.Errorf("outstanding = %v, want the debtor's balance reported", outstanding)
+	}
+	if isMember(debtor.UserID) || isMember(settled.UserID) {
+		t.Error("a forced removal left members in the group")
+	}
+}
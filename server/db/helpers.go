@@ -10,17 +10,29 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/utils"
 )
 
+// querier runs queries on either a pool or a transaction, for reads that are also needed inside one.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 // TxFunc is a function that executes within a database transaction
 type TxFunc func(ctx context.Context, tx pgx.Tx) error
 
 // WithTransaction executes a function within a database transaction.
 // If the function returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
+// If the connection is lost while beginning the transaction, it is retried once.
 // This provides a consistent pattern for transaction management.
 func WithTransaction(ctx context.Context, pool *pgxpool.Pool, fn TxFunc) error {
 	tx, err := pool.Begin(ctx)
+	if err != nil && utils.IsConnectionError(err) {
+		// The pool discards broken connections, so one retry gets a fresh one
+		slog.Warn("Database connection lost, retrying transaction begin", "error", err)
+		tx, err = pool.Begin(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -53,6 +65,16 @@ func WithTransaction(ctx context.Context, pool *pgxpool.Pool, fn TxFunc) error {
 	return nil
 }
 
+// WithTimeout returns a copy of ctx that is cancelled after d, for queries that need a tighter
+// bound than the pool's statement timeout. A non-positive d only adds cancellation.
+// The caller must call the returned cancel function once done.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
 // ExecuteInBatch executes multiple queries in a batch for better performance.
 // Useful for bulk inserts, updates, or deletes.
 func ExecuteInBatch(ctx context.Context, pool *pgxpool.Pool, queries []BatchQuery) error {
@@ -193,6 +215,10 @@ func isRetryableError(err error) bool {
 		return false
 	}
 
+	if utils.IsConnectionError(err) {
+		return true
+	}
+
 	errStr := err.Error()
 	// Check for common transient errors
 	retryablePatterns := []string{
@@ -228,3 +254,9 @@ func ValidateUUID(uuidStr string) bool {
 	_, err := uuid.Parse(uuidStr)
 	return err == nil
 }
+
+// escapeLike escapes the LIKE/ILIKE wildcard characters in user input so it is
+// matched literally. Use with ESCAPE '\' in the query.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
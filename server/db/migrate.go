@@ -26,6 +26,7 @@ type MigrationStatus struct {
 	AppliedMigrations int
 	PendingMigrations int
 	Migrations        []MigrationInfo
+	Pending           []string // names of migration files not yet applied
 }
 
 // Migrate applies all pending database migrations from the specified directory.
@@ -159,6 +160,13 @@ func applyMigration(ctx context.Context, pool *pgxpool.Pool, filePath string) (b
 		}
 	}()
 
+	// Lift the pool's statement_timeout for this transaction only: backfills and index
+	// builds on a real dataset can take far longer than a request-sized query
+	_, err = tx.Exec(ctx, "SET LOCAL statement_timeout = 0")
+	if err != nil {
+		return false, fmt.Errorf("failed to disable statement timeout for '%s': %w", migrationName, err)
+	}
+
 	// Execute the migration SQL
 	_, err = tx.Exec(ctx, string(sqlContent))
 	if err != nil {
@@ -208,8 +216,10 @@ func calculateChecksum(content []byte) string {
 	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
-// GetMigrationStatus returns the current status of all migrations
-func GetMigrationStatus(ctx context.Context, pool *pgxpool.Pool) (*MigrationStatus, error) {
+// GetMigrationStatus returns the current status of all migrations.
+// Applied migrations are read from schema_migrations and diffed against the
+// files in migrationsDir to find the ones that are still pending.
+func GetMigrationStatus(ctx context.Context, pool *pgxpool.Pool, migrationsDir string) (*MigrationStatus, error) {
 	rows, err := pool.Query(ctx,
 		`SELECT migration_name, applied_at, checksum
 		 FROM schema_migrations
@@ -222,17 +232,36 @@ func GetMigrationStatus(ctx context.Context, pool *pgxpool.Pool) (*MigrationStat
 
 	status := &MigrationStatus{
 		Migrations: make([]MigrationInfo, 0),
+		Pending:    make([]string, 0),
 	}
 
+	applied := make(map[string]bool)
 	for rows.Next() {
 		var info MigrationInfo
 		if err := rows.Scan(&info.Name, &info.AppliedAt, &info.Checksum); err != nil {
 			return nil, fmt.Errorf("failed to scan migration info: %w", err)
 		}
 		status.Migrations = append(status.Migrations, info)
+		applied[info.Name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read migration status: %w", err)
+	}
+
+	files, err := getMigrationFiles(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		if !applied[name] {
+			status.Pending = append(status.Pending, name)
+		}
 	}
 
+	status.TotalMigrations = len(files)
 	status.AppliedMigrations = len(status.Migrations)
+	status.PendingMigrations = len(status.Pending)
 	return status, nil
 }
 
@@ -240,7 +269,7 @@ func GetMigrationStatus(ctx context.Context, pool *pgxpool.Pool) (*MigrationStat
 func VerifyMigrationIntegrity(ctx context.Context, pool *pgxpool.Pool, migrationsDir string) error {
 	slog.Info("Verifying migration integrity...")
 
-	status, err := GetMigrationStatus(ctx, pool)
+	status, err := GetMigrationStatus(ctx, pool, migrationsDir)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+)
+
+// QueryBuilder accumulates the conditions of a WHERE clause together with their arguments,
+// so optional filters can be combined without building SQL from user input.
+// Values are always passed as query arguments and never interpolated into the SQL.
+type QueryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+// Where adds a condition, joined to the others with AND.
+// Each "?" in cond is replaced by the placeholder of the matching value in args, in order,
+// so cond itself must not contain a literal "?".
+func (b *QueryBuilder) Where(cond string, args ...any) *QueryBuilder {
+	var sql strings.Builder
+	next := 0
+	for _, r := range cond {
+		if r != '?' || next >= len(args) {
+			sql.WriteRune(r)
+			continue
+		}
+		b.args = append(b.args, args[next])
+		sql.WriteString("$" + strconv.Itoa(len(b.args)))
+		next++
+	}
+	b.conditions = append(b.conditions, sql.String())
+	return b
+}
+
+// WhereClause returns the accumulated conditions as a WHERE clause, or an empty string if there are none.
+func (b *QueryBuilder) WhereClause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, "\n\t\tAND ")
+}
+
+// Args returns the query arguments in placeholder order.
+func (b *QueryBuilder) Args() []any {
+	return b.args
+}
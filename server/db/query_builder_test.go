@@ -0,0 +1,71 @@
+package db_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pranaovs/qashare/db"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	type where struct {
+		cond string
+		args []any
+	}
+	tests := []struct {
+		name      string
+		wheres    []where
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "no conditions",
+			wantWhere: "",
+			wantArgs:  nil,
+		},
+		{
+			name: "placeholders numbered across calls",
+			wheres: []where{
+				{"e.group_id = ?", []any{"g"}},
+				{"e.amount BETWEEN ? AND ?", []any{1.5, 20.0}},
+				{"e.category = ?", []any{"food"}},
+			},
+			wantWhere: "WHERE e.group_id = $1\n\t\tAND e.amount BETWEEN $2 AND $3\n\t\tAND e.category = $4",
+			wantArgs:  []any{"g", 1.5, 20.0, "food"},
+		},
+		{
+			name: "condition with no args",
+			wheres: []where{
+				{"e.group_id = ?", []any{"g"}},
+				{"e.deleted_at IS NULL", nil},
+				{"e.title ILIKE ?", []any{"%taxi%"}},
+			},
+			wantWhere: "WHERE e.group_id = $1\n\t\tAND e.deleted_at IS NULL\n\t\tAND e.title ILIKE $2",
+			wantArgs:  []any{"g", "%taxi%"},
+		},
+		{
+			name: "more ? than args",
+			wheres: []where{
+				{"e.group_id = ?", []any{"g"}},
+				{"e.amount > ? AND e.amount < ?", []any{5}},
+			},
+			// Unmatched "?" are left as written instead of taking a placeholder with no value
+			wantWhere: "WHERE e.group_id = $1\n\t\tAND e.amount > $2 AND e.amount < ?",
+			wantArgs:  []any{"g", 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b db.QueryBuilder
+			for _, w := range tt.wheres {
+				b.Where(w.cond, w.args...)
+			}
+			if got := b.WhereClause(); got != tt.wantWhere {
+				t.Errorf("WhereClause() = %q, want %q", got, tt.wantWhere)
+			}
+			if got := b.Args(); !slices.Equal(got, tt.wantArgs) {
+				t.Errorf("Args() = %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
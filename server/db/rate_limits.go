@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HitRateLimit records one hit against a fixed rate-limit window identified by key.
+// The window is created (or restarted if it has expired) atomically with an upsert,
+// so concurrent instances sharing the database see a consistent count.
+// Returns the number of hits in the current window and when the window ends.
+func HitRateLimit(ctx context.Context, pool *pgxpool.Pool, key string, window time.Duration) (int, time.Time, error) {
+	if key == "" {
+		return 0, time.Time{}, ErrInvalidInput.Msg("rate limit key missing")
+	}
+
+	query := `INSERT INTO rate_limits (key, hits, expires_at)
+		VALUES ($1, 1, NOW() + make_interval(secs => $2))
+		ON CONFLICT (key) DO UPDATE SET
+			hits = CASE WHEN rate_limits.expires_at <= NOW() THEN 1 ELSE rate_limits.hits + 1 END,
+			expires_at = CASE WHEN rate_limits.expires_at <= NOW() THEN EXCLUDED.expires_at ELSE rate_limits.expires_at END
+		RETURNING hits, expires_at`
+
+	var hits int
+	var expiresAt time.Time
+	err := pool.QueryRow(ctx, query, key, window.Seconds()).Scan(&hits, &expiresAt)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return hits, expiresAt, nil
+}
+
+// DeleteExpiredRateLimits removes all rate-limit windows that have ended.
+func DeleteExpiredRateLimits(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
+	result, err := pool.Exec(ctx, `DELETE FROM rate_limits WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,10 +11,37 @@ import (
 )
 
 // StoreToken inserts a refresh token record into the database.
-func StoreToken(ctx context.Context, pool *pgxpool.Pool, tokenID, userID uuid.UUID, expiresAt time.Time) error {
-	query := `INSERT INTO refresh_tokens (token_id, user_id, expires_at) VALUES ($1, $2, $3)`
-	_, err := pool.Exec(ctx, query, tokenID, userID, expiresAt)
-	return err
+// If maxSessions is positive, the user's oldest refresh tokens beyond the newest maxSessions
+// are evicted in the same transaction, which logs those sessions out. 0 means unlimited.
+func StoreToken(ctx context.Context, pool *pgxpool.Pool, tokenID, userID uuid.UUID, expiresAt time.Time, maxSessions int) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		query := `INSERT INTO refresh_tokens (token_id, user_id, expires_at) VALUES ($1, $2, $3)`
+		if _, err := tx.Exec(ctx, query, tokenID, userID, expiresAt); err != nil {
+			return err
+		}
+
+		if maxSessions <= 0 {
+			return nil
+		}
+
+		result, err := tx.Exec(ctx,
+			`DELETE FROM refresh_tokens WHERE token_id IN (
+				SELECT token_id FROM refresh_tokens
+				WHERE user_id = $1
+				ORDER BY created_at DESC, token_id
+				OFFSET $2
+			)`,
+			userID, maxSessions,
+		)
+		if err != nil {
+			return err
+		}
+		if evicted := result.RowsAffected(); evicted > 0 {
+			slog.InfoContext(ctx, "Evicted oldest refresh tokens over the session limit",
+				"user_id", userID, "evicted", evicted, "max_sessions", maxSessions)
+		}
+		return nil
+	})
 }
 
 // DeleteToken removes a specific refresh token (e.g., for logout or revocation).
@@ -72,9 +99,10 @@ func DeleteExpiredTokens(ctx context.Context, pool *pgxpool.Pool) (int64, error)
 	return result.RowsAffected(), nil
 }
 
-// StartTokenCleanup runs a background goroutine that periodically deletes expired refresh tokens.
+// StartTokenCleanup runs a background goroutine that periodically deletes expired refresh tokens,
+// along with other expired rows and expenses that have been in the trash longer than expenseRetention.
 // It stops when the context is cancelled. The returned channel is closed once the goroutine exits.
-func StartTokenCleanup(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) <-chan struct{} {
+func StartTokenCleanup(ctx context.Context, pool *pgxpool.Pool, interval, expenseRetention time.Duration) <-chan struct{} {
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
@@ -100,6 +128,20 @@ func StartTokenCleanup(ctx context.Context, pool *pgxpool.Pool, interval time.Du
 				} else if deletedVerification > 0 {
 					slog.Info("Cleaned up expired verification tokens", "count", deletedVerification)
 				}
+
+				deletedRateLimits, err := DeleteExpiredRateLimits(ctx, pool)
+				if err != nil {
+					slog.Error("Failed to clean up expired rate limits", "error", err)
+				} else if deletedRateLimits > 0 {
+					slog.Info("Cleaned up expired rate limits", "count", deletedRateLimits)
+				}
+
+				purgedExpenses, err := PurgeDeletedExpenses(ctx, pool, expenseRetention)
+				if err != nil {
+					slog.Error("Failed to purge deleted expenses", "error", err)
+				} else if purgedExpenses > 0 {
+					slog.Info("Purged deleted expenses", "count", purgedExpenses)
+				}
 			}
 		}
 	}()
@@ -2,25 +2,33 @@ package db
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
 )
 
 // GetSettlement calculates the net balance between the current user and all other group members.
-// It analyzes all expenses in a group and determines who owes whom, then optimizes the settlements
-// using a debt minimization algorithm.
+// It analyzes all expenses in a group and determines who owes whom.
 //
-// Returns a slice of optimized Settlement where each entry represents a single payment:
+// When simplify is true, the settlements are optimized using a debt minimization algorithm, so
+// debts may be rerouted through other members. When false, the direct pairwise balance with
+// each member is returned, computed from proportional debts without any rematching.
+//
+// In both modes each entry represents a single payment:
 //   - UserID: Who the current user needs to interact with (pay or receive from)
 //   - Amount: Transaction amount
 //   - Positive: Current user receives from UserID
 //   - Negative: Current user pays to UserID
 //
-// Uses greedy algorithm to minimize number of transactions while settling all debts.
-func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, splitTolerance float64) ([]models.Settlement, error) {
+// Simplification uses a greedy algorithm to minimize the number of transactions while settling all debts.
+func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, splitTolerance float64, simplify bool) ([]models.Settlement, error) {
 	// Validate input
 	if groupID == uuid.Nil {
 		return nil, ErrInvalidInput.Msg("group id missing")
@@ -29,10 +37,24 @@ func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid
 		return nil, ErrInvalidInput.Msg("user id missing")
 	}
 
-	// Query to calculate proportional debt distribution when multiple payers exist.
-	// Accumulation is done in PostgreSQL using NUMERIC precision to avoid
-	// floating-point errors that would occur if summed in Go with float64.
-	query := `
+	if !simplify {
+		return getPairwiseSettlements(ctx, pool, userID, groupID, splitTolerance)
+	}
+
+	balances, err := getGroupBalances(ctx, pool, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Optimize settlements to minimize transactions
+	optimized := optimizeSettlements(balances, userID, splitTolerance)
+
+	return optimized, nil
+}
+
+// proportionalDebtsCTE distributes each owed split of group $1 across the expense's payers in
+// proportion to what they paid. Each row is a debt from debtor_id to payer_id.
+const proportionalDebtsCTE = `
 	WITH expense_totals AS (
 	  SELECT
 	    expense_id,
@@ -51,11 +73,60 @@ func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid
 	  JOIN expenses e ON e.expense_id = es_payer.expense_id
 	  JOIN expense_totals et ON et.expense_id = es_payer.expense_id
 	  WHERE e.group_id = $1
+	    AND e.deleted_at IS NULL
 	    AND es_payer.is_paid = true
 	    AND es_debtor.is_paid = false
 	    AND es_payer.user_id != es_debtor.user_id
 	    AND et.total_paid > 0
-	)
+	)`
+
+// getPairwiseSettlements returns the direct net balance between the user and every other member
+// of the group, without rerouting debts through third parties.
+// Balances within splitTolerance of zero are omitted. Sign convention matches optimizeSettlements.
+func getPairwiseSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, splitTolerance float64) ([]models.Settlement, error) {
+	query := proportionalDebtsCTE + `
+	SELECT other_id, SUM(amount)::float8 AS net_balance
+	FROM (
+	  SELECT debtor_id AS other_id, proportional_amount AS amount
+	  FROM proportional_debts WHERE payer_id = $2
+	  UNION ALL
+	  SELECT payer_id AS other_id, -proportional_amount AS amount
+	  FROM proportional_debts WHERE debtor_id = $2
+	) AS pairs
+	GROUP BY other_id
+	HAVING ABS(SUM(amount)) > $3
+	ORDER BY net_balance DESC
+	`
+
+	rows, err := pool.Query(ctx, query, groupID, userID, splitTolerance)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settlements := make([]models.Settlement, 0)
+	for rows.Next() {
+		var settlement models.Settlement
+		if err := rows.Scan(&settlement.UserID, &settlement.Amount); err != nil {
+			return nil, err
+		}
+		settlements = append(settlements, settlement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settlements, nil
+}
+
+// getGroupBalances returns the net balance of every member with a non-zero position in the group.
+// Positive means the member is owed money, negative means the member owes money.
+func getGroupBalances(ctx context.Context, q querier, groupID uuid.UUID) (map[uuid.UUID]float64, error) {
+	// Query to calculate proportional debt distribution when multiple payers exist.
+	// Accumulation is done in PostgreSQL using NUMERIC precision to avoid
+	// floating-point errors that would occur if summed in Go with float64.
+	query := proportionalDebtsCTE + `
 	SELECT user_id, SUM(balance)::float8 AS net_balance
 	FROM (
 	  SELECT payer_id AS user_id, SUM(proportional_amount) AS balance
@@ -67,7 +138,7 @@ func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid
 	GROUP BY user_id
 	`
 
-	rows, err := pool.Query(ctx, query, groupID)
+	rows, err := q.Query(ctx, query, groupID)
 	if err != nil {
 		return nil, err
 	}
@@ -92,17 +163,327 @@ func GetSettlement(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid
 		return nil, err
 	}
 
-	// Step 3: Optimize settlements to minimize transactions
-	optimized := optimizeSettlements(balances, userID, splitTolerance)
+	return balances, nil
+}
 
-	return optimized, nil
+// GetGroupSummary computes group-wide totals: overall spend, the net balance of every member,
+// the number of user pairs with an unsettled direct debt, and the biggest creditor and debtor.
+// Sums are accumulated in NUMERIC by PostgreSQL; trashed expenses are excluded.
+// Balances within splitTolerance of zero are reported as 0, so a fully settled group has zero totals
+// rather than an empty summary. Users who left the group with a balance are listed as well.
+// Returns ErrNotFound if the group does not exist.
+func GetGroupSummary(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, splitTolerance float64) (models.GroupSummary, error) {
+	summary := models.GroupSummary{Balances: make([]models.MemberBalance, 0)}
+	if groupID == uuid.Nil {
+		return summary, ErrInvalidInput.Msg("group id missing")
+	}
+
+	err := pool.QueryRow(ctx, proportionalDebtsCTE+`,
+	pair_balances AS (
+	  SELECT LEAST(payer_id, debtor_id) AS a, GREATEST(payer_id, debtor_id) AS b,
+	    SUM(CASE WHEN payer_id < debtor_id THEN proportional_amount ELSE -proportional_amount END) AS net
+	  FROM proportional_debts
+	  GROUP BY 1, 2
+	)
+	SELECT g.base_currency,
+	  COALESCE((
+	    SELECT SUM(amount) FROM expenses
+	    WHERE group_id = $1 AND deleted_at IS NULL AND NOT is_settlement
+	  ), 0)::float8,
+	  (SELECT COUNT(*) FROM pair_balances WHERE ABS(net) > $2)::int
+	FROM groups g
+	WHERE g.group_id = $1`,
+		groupID, splitTolerance,
+	).Scan(&summary.Currency, &summary.TotalSpend, &summary.UnsettledPairs)
+	if err == pgx.ErrNoRows {
+		return summary, ErrNotFound.Msgf("group with id %s not found", groupID)
+	}
+	if err != nil {
+		return summary, err
+	}
+
+	rows, err := pool.Query(ctx, proportionalDebtsCTE+`,
+	net AS (
+	  SELECT payer_id AS user_id, proportional_amount AS balance FROM proportional_debts
+	  UNION ALL
+	  SELECT debtor_id AS user_id, -proportional_amount AS balance FROM proportional_debts
+	  UNION ALL
+	  SELECT user_id, 0 FROM group_members WHERE group_id = $1
+	)
+	SELECT n.user_id, COALESCE(u.user_name, $3),
+	  CASE WHEN ABS(SUM(n.balance)) > $2 THEN SUM(n.balance) ELSE 0 END::float8 AS net_balance
+	FROM net n
+	LEFT JOIN users u ON u.user_id = n.user_id
+	GROUP BY n.user_id, u.user_name
+	HAVING ABS(SUM(n.balance)) > $2 OR n.user_id IN (SELECT user_id FROM group_members WHERE group_id = $1)
+	ORDER BY net_balance DESC, n.user_id`,
+		groupID, splitTolerance, models.DeletedUserName,
+	)
+	if err != nil {
+		return summary, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var balance models.MemberBalance
+		if err := rows.Scan(&balance.UserID, &balance.Name, &balance.Balance); err != nil {
+			return summary, err
+		}
+		summary.Balances = append(summary.Balances, balance)
+	}
+	if err := rows.Err(); err != nil {
+		return summary, err
+	}
+
+	// Balances are sorted highest first, so the extremes sit at either end
+	if n := len(summary.Balances); n > 0 {
+		if first := summary.Balances[0]; first.Balance > 0 {
+			summary.TopCreditor = &first
+		}
+		if last := summary.Balances[n-1]; last.Balance < 0 {
+			summary.TopDebtor = &last
+		}
+	}
+
+	return summary, nil
+}
+
+// getOutstandingBalances returns the net balance of each of the given users that is not settled within tolerance.
+// Positive means the user is owed money, negative means the user owes money; settled users are left out.
+func getOutstandingBalances(ctx context.Context, q querier, groupID uuid.UUID, userIDs []uuid.UUID, tolerance float64) (map[uuid.UUID]float64, error) {
+	balances, err := getGroupBalances(ctx, q, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding := make(map[uuid.UUID]float64)
+	for _, userID := range userIDs {
+		if balance := balances[userID]; math.Abs(balance) > tolerance {
+			outstanding[userID] = balance
+		}
+	}
+	return outstanding, nil
+}
+
+// GetUserNetOwed returns how much the user owes in the group overall: what they owe across all live
+// expenses and settlements minus what they paid. Negative means the group owes them.
+// It is a single sum over the user's own splits, so it is cheap enough to poll. For complete expenses it
+// matches the sum of the user's balances in GetSettlement; incomplete expenses whose paid and owed
+// totals differ count at face value.
+func GetUserNetOwed(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID) (float64, error) {
+	if userID == uuid.Nil || groupID == uuid.Nil {
+		return 0, ErrInvalidInput.Msg("user id or group id missing")
+	}
+
+	var owed float64
+	err := pool.QueryRow(ctx,
+		`SELECT COALESCE(SUM(CASE WHEN es.is_paid THEN -es.amount ELSE es.amount END), 0)::float8
+		FROM expense_splits es
+		JOIN expenses e ON e.expense_id = es.expense_id
+		WHERE e.group_id = $2 AND es.user_id = $1 AND e.deleted_at IS NULL`,
+		userID, groupID,
+	).Scan(&owed)
+	if err != nil {
+		return 0, err
+	}
+	return owed, nil
+}
+
+// GetNotificationCounts counts the groups in which the user still has an unsettled balance,
+// split into groups where they owe money and groups where they are owed.
+// Balances within splitTolerance of zero are treated as settled.
+// It also counts the user's pending drafts (expenses with an incomplete amount or split that they
+// added or take part in) and overdue splits (their owed splits in expenses transacted more than
+// overdueAfter ago, where someone else paid).
+// Everything is aggregated in a single query so the endpoint stays cheap to poll.
+func GetNotificationCounts(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, splitTolerance float64, overdueAfter time.Duration) (models.NotificationCounts, error) {
+	query := `
+	WITH expense_totals AS (
+	  SELECT es.expense_id, SUM(es.amount) AS total_paid
+	  FROM expense_splits es
+	  JOIN expenses e ON e.expense_id = es.expense_id
+	  JOIN group_members gm ON gm.group_id = e.group_id AND gm.user_id = $1
+	  WHERE es.is_paid = true
+	    AND e.deleted_at IS NULL
+	  GROUP BY es.expense_id
+	),
+	proportional_debts AS (
+	  SELECT
+	    e.group_id,
+	    es_payer.user_id AS payer_id,
+	    es_debtor.user_id AS debtor_id,
+	    es_debtor.amount * (es_payer.amount / et.total_paid) AS proportional_amount
+	  FROM expense_splits es_payer
+	  JOIN expense_splits es_debtor ON es_payer.expense_id = es_debtor.expense_id
+	  JOIN expenses e ON e.expense_id = es_payer.expense_id
+	  JOIN expense_totals et ON et.expense_id = es_payer.expense_id
+	  WHERE es_payer.is_paid = true
+	    AND es_debtor.is_paid = false
+	    AND es_payer.user_id != es_debtor.user_id
+	    AND (es_payer.user_id = $1 OR es_debtor.user_id = $1)
+	    AND et.total_paid > 0
+	),
+	balances AS (
+	  SELECT group_id,
+	    SUM(CASE WHEN payer_id = $1 THEN proportional_amount ELSE -proportional_amount END) AS balance
+	  FROM proportional_debts
+	  GROUP BY group_id
+	)
+	SELECT
+	  COUNT(*) FILTER (WHERE balance < -$2::numeric),
+	  COUNT(*) FILTER (WHERE balance > $2::numeric),
+	  (SELECT COUNT(*)
+	    FROM expenses e
+	    JOIN group_members gm ON gm.group_id = e.group_id AND gm.user_id = $1
+	    WHERE e.deleted_at IS NULL
+	      AND e.is_settlement = false
+	      AND (e.is_incomplete_amount OR e.is_incomplete_split)
+	      AND (e.added_by = $1 OR EXISTS (
+	        SELECT 1 FROM expense_splits es WHERE es.expense_id = e.expense_id AND es.user_id = $1))),
+	  (SELECT COUNT(*)
+	    FROM expense_splits es
+	    JOIN expenses e ON e.expense_id = es.expense_id
+	    JOIN group_members gm ON gm.group_id = e.group_id AND gm.user_id = $1
+	    WHERE es.user_id = $1
+	      AND es.is_paid = false
+	      AND e.deleted_at IS NULL
+	      AND e.is_settlement = false
+	      AND e.transacted_at < NOW() - make_interval(secs => $3)
+	      AND EXISTS (
+	        SELECT 1 FROM expense_splits payer
+	        WHERE payer.expense_id = es.expense_id AND payer.is_paid = true AND payer.user_id <> $1))
+	FROM balances
+	`
+
+	var counts models.NotificationCounts
+	err := pool.QueryRow(ctx, query, userID, splitTolerance, overdueAfter.Seconds()).Scan(
+		&counts.Owing, &counts.Owed, &counts.PendingDrafts, &counts.OverdueSplits,
+	)
+	if err != nil {
+		return models.NotificationCounts{}, err
+	}
+
+	counts.Total = counts.Owing + counts.Owed + counts.PendingDrafts + counts.OverdueSplits
+	return counts, nil
+}
+
+// Balance history intervals accepted by GetUserBalanceHistory
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// GetUserBalanceHistory returns the user's net position across all groups over time, one point per interval
+// from the interval of their first expense up to the current one. Intervals without activity are zero-filled
+// so the series is continuous. Each expense contributes what the user paid minus what they owe, dated by its
+// transaction time (falling back to creation time); settlements are included, deleted expenses are not.
+// Returns ErrInvalidInput if interval is not one of IntervalDay, IntervalWeek or IntervalMonth.
+func GetUserBalanceHistory(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, interval string) ([]models.BalancePoint, error) {
+	switch interval {
+	case IntervalDay, IntervalWeek, IntervalMonth:
+	default:
+		return nil, ErrInvalidInput.Msgf("interval must be one of %s, %s or %s", IntervalDay, IntervalWeek, IntervalMonth)
+	}
+
+	query := `
+	WITH deltas AS (
+	  SELECT
+	    date_trunc($2, COALESCE(e.transacted_at, e.created_at)) AS period,
+	    SUM(CASE WHEN es.is_paid THEN es.amount ELSE -es.amount END) AS change
+	  FROM expense_splits es
+	  JOIN expenses e ON e.expense_id = es.expense_id
+	  WHERE es.user_id = $1
+	    AND e.deleted_at IS NULL
+	  GROUP BY 1
+	),
+	periods AS (
+	  SELECT generate_series(
+	    (SELECT MIN(period) FROM deltas),
+	    GREATEST((SELECT MAX(period) FROM deltas), date_trunc($2, NOW())),
+	    ('1 ' || $2)::interval
+	  ) AS period
+	)
+	SELECT
+	  extract(epoch from p.period)::bigint,
+	  COALESCE(d.change, 0)::float8,
+	  (SUM(COALESCE(d.change, 0)) OVER (ORDER BY p.period))::float8
+	FROM periods p
+	LEFT JOIN deltas d ON d.period = p.period
+	ORDER BY p.period`
+
+	rows, err := pool.Query(ctx, query, userID, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := make([]models.BalancePoint, 0)
+	for rows.Next() {
+		var point models.BalancePoint
+		if err := rows.Scan(&point.PeriodStart, &point.Change, &point.Balance); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetSettlementPlan computes the optimized settlement plan for the whole group:
+// the minimal set of payments (debtor pays creditor) that settles every member's balance.
+// Uses the same balance computation and greedy matching as GetSettlement.
+func GetSettlementPlan(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, splitTolerance float64) ([]models.SettlementTransfer, error) {
+	if groupID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("group id missing")
+	}
+
+	balances, err := getGroupBalances(ctx, pool, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return planSettlements(balances, splitTolerance), nil
 }
 
 // optimizeSettlements uses greedy algorithm to minimize transactions
 // Returns settlements for the given user
+//
+// Every transfer of the group-wide plan that involves userID is kept, whichever side of the
+// match the user was on, so a balance settled against several members yields one entry per
+// member. Since planSettlements settles every balance, the returned amounts sum to the user's
+// net balance within tolerance.
 func optimizeSettlements(balances map[uuid.UUID]float64, userID uuid.UUID, tolerance float64) []models.Settlement {
+	settlements := make([]models.Settlement, 0)
+
+	for _, transfer := range planSettlements(balances, tolerance) {
+		if transfer.FromUserID == userID {
+			// Current user owes, so negative amount
+			settlements = append(settlements, models.Settlement{
+				UserID: transfer.ToUserID,
+				Amount: -transfer.Amount,
+			})
+		} else if transfer.ToUserID == userID {
+			// Current user is owed, so positive amount
+			settlements = append(settlements, models.Settlement{
+				UserID: transfer.FromUserID,
+				Amount: transfer.Amount,
+			})
+		}
+	}
+
+	return settlements
+}
+
+// planSettlements uses greedy algorithm to minimize transactions
+// Returns every transfer needed to settle the group
+func planSettlements(balances map[uuid.UUID]float64, tolerance float64) []models.SettlementTransfer {
 	if len(balances) == 0 {
-		return []models.Settlement{}
+		return []models.SettlementTransfer{}
 	}
 
 	// Separate users into creditors (positive) and debtors (negative)
@@ -138,7 +519,7 @@ func optimizeSettlements(balances map[uuid.UUID]float64, userID uuid.UUID, toler
 	})
 
 	// Greedy matching: pair largest debtors with largest creditors
-	settlements := make([]models.Settlement, 0)
+	transfers := make([]models.SettlementTransfer, 0)
 
 	for len(debtors) > 0 && len(creditors) > 0 {
 		debtor := debtors[0]
@@ -150,20 +531,11 @@ func optimizeSettlements(balances map[uuid.UUID]float64, userID uuid.UUID, toler
 			transfer = creditor.amount
 		}
 
-		// Record settlement based on relationship to userID
-		if debtor.userID == userID {
-			// Current user owes, so negative amount
-			settlements = append(settlements, models.Settlement{
-				UserID: creditor.userID,
-				Amount: -transfer,
-			})
-		} else if creditor.userID == userID {
-			// Current user is owed, so positive amount
-			settlements = append(settlements, models.Settlement{
-				UserID: debtor.userID,
-				Amount: transfer,
-			})
-		}
+		transfers = append(transfers, models.SettlementTransfer{
+			FromUserID: debtor.userID,
+			ToUserID:   creditor.userID,
+			Amount:     transfer,
+		})
 
 		// Update remaining balances
 		debtors[0].amount -= transfer
@@ -178,61 +550,123 @@ func optimizeSettlements(balances map[uuid.UUID]float64, userID uuid.UUID, toler
 		}
 	}
 
-	return settlements
+	return transfers
+}
+
+// GetSettlementByClientRef returns the ID of the group's settlement that was created with clientRef,
+// and whether that settlement is in the trash.
+// Returns ErrNotFound if no settlement in the group uses the reference.
+func GetSettlementByClientRef(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, clientRef string) (uuid.UUID, bool, error) {
+	var expenseID uuid.UUID
+	var deleted bool
+	err := pool.QueryRow(ctx,
+		`SELECT expense_id, deleted_at IS NOT NULL
+		FROM expenses
+		WHERE group_id = $1 AND client_ref = $2 AND is_settlement = true`,
+		groupID, clientRef,
+	).Scan(&expenseID, &deleted)
+	if err == pgx.ErrNoRows {
+		return uuid.Nil, false, ErrNotFound.Msgf("no settlement with client_ref %q", clientRef)
+	}
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	return expenseID, deleted, nil
 }
 
-// GetSettlements retrieves all settlement expenses in a group where the
+// GetSettlements retrieves a page of settlement expenses in a group where the
 // specified user is a participant (either payer or receiver).
-// Returns a slice of ExpenseDetails ordered by creation time descending.
-func GetSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID) ([]models.ExpenseDetails, error) {
+// If withUser is set, only settlements between userID and withUser are returned.
+// Settlements are ordered by creation time descending. Pass the returned cursor
+// back to fetch the next page; an empty cursor means there are no more pages.
+func GetSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, withUser *uuid.UUID, limit int, cursor string) ([]models.ExpenseDetails, string, error) {
 	if groupID == uuid.Nil {
-		return nil, ErrInvalidInput.Msg("group id missing")
+		return nil, "", ErrInvalidInput.Msg("group id missing")
 	}
 	if userID == uuid.Nil {
-		return nil, ErrInvalidInput.Msg("user id missing")
+		return nil, "", ErrInvalidInput.Msg("user id missing")
+	}
+	if withUser != nil && *withUser == userID {
+		return nil, "", ErrInvalidInput.Msg("with_user must be another user")
+	}
+
+	var afterTime *time.Time
+	var afterID *uuid.UUID
+	if cursor != "" {
+		values, err := utils.DecodeCursor(cursor, 2)
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		t, err := time.Parse(time.RFC3339Nano, values[0])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		id, err := uuid.Parse(values[1])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		afterTime, afterID = &t, &id
 	}
 
+	// Page over expenses first so splits do not count towards the limit
 	query := `
-		SELECT e.expense_id, e.group_id, e.added_by, e.title, e.description,
+		WITH page AS (
+			SELECT e.expense_id, e.created_at
+			FROM expenses e
+			WHERE e.group_id = $1
+				AND e.is_settlement = true
+				AND e.deleted_at IS NULL
+				AND EXISTS (
+					SELECT 1 FROM expense_splits WHERE expense_id = e.expense_id AND user_id = $2
+				)
+				AND ($6::uuid IS NULL OR EXISTS (
+					SELECT 1 FROM expense_splits WHERE expense_id = e.expense_id AND user_id = $6
+				))
+				AND ($3::timestamptz IS NULL OR (e.created_at, e.expense_id) < ($3::timestamptz, $4::uuid))
+			ORDER BY e.created_at DESC, e.expense_id DESC
+			LIMIT $5
+		)
+		SELECT e.expense_id, e.group_id, e.seq, e.added_by, e.title, e.description,
 			extract(epoch from e.created_at)::bigint,
 			extract(epoch from e.transacted_at)::bigint,
 			e.amount,
 			e.is_incomplete_amount, e.is_incomplete_split, e.is_settlement, e.is_private,
-			e.latitude, e.longitude,
+			e.latitude, e.longitude, e.payment_method, e.client_ref,
+			p.created_at,
 			es.user_id, es.amount, es.is_paid
-		FROM expenses e
+		FROM page p
+		JOIN expenses e ON e.expense_id = p.expense_id
 		JOIN expense_splits es ON e.expense_id = es.expense_id
-		WHERE e.group_id = $1
-			AND e.is_settlement = true
-			AND e.expense_id IN (
-				SELECT expense_id FROM expense_splits WHERE user_id = $2
-			)
-		ORDER BY e.created_at DESC, es.is_paid DESC, es.user_id`
+		ORDER BY p.created_at DESC, p.expense_id DESC, es.is_paid DESC, es.user_id`
 
-	rows, err := pool.Query(ctx, query, groupID, userID)
+	// Fetch one extra expense to know whether another page exists
+	rows, err := pool.Query(ctx, query, groupID, userID, afterTime, afterID, limit+1, withUser)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
 	expenseMap := make(map[uuid.UUID]*models.ExpenseDetails)
+	createdAt := make(map[uuid.UUID]time.Time)
 	var order []uuid.UUID
 
 	for rows.Next() {
 		var exp models.Expense
+		var rawCreatedAt time.Time
 		var splitUserID *uuid.UUID
 		var splitAmount *float64
 		var splitIsPaid *bool
 
 		err = rows.Scan(
-			&exp.ExpenseID, &exp.GroupID, &exp.AddedBy, &exp.Title,
+			&exp.ExpenseID, &exp.GroupID, &exp.Seq, &exp.AddedBy, &exp.Title,
 			&exp.Description, &exp.CreatedAt, &exp.TransactedAt, &exp.Amount,
 			&exp.IsIncompleteAmount, &exp.IsIncompleteSplit, &exp.IsSettlement, &exp.IsPrivate,
-			&exp.Latitude, &exp.Longitude,
+			&exp.Latitude, &exp.Longitude, &exp.PaymentMethod, &exp.ClientRef,
+			&rawCreatedAt,
 			&splitUserID, &splitAmount, &splitIsPaid,
 		)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 
 		if _, exists := expenseMap[exp.ExpenseID]; !exists {
@@ -240,6 +674,7 @@ func GetSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uui
 				Expense: exp,
 				Splits:  make([]models.ExpenseSplit, 0),
 			}
+			createdAt[exp.ExpenseID] = rawCreatedAt
 			order = append(order, exp.ExpenseID)
 		}
 
@@ -254,7 +689,14 @@ func GetSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uui
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(order) > limit {
+		order = order[:limit]
+		last := order[limit-1]
+		nextCursor = utils.EncodeCursor(createdAt[last].Format(time.RFC3339Nano), last.String())
 	}
 
 	results := make([]models.ExpenseDetails, 0, len(order))
@@ -262,5 +704,115 @@ func GetSettlements(ctx context.Context, pool *pgxpool.Pool, userID, groupID uui
 		results = append(results, *expenseMap[id])
 	}
 
-	return results, nil
+	return results, nextCursor, nil
+}
+
+// GetRecentCounterparties returns the current group members the user most recently
+// shared an expense or settlement with, most recent first, up to limit entries.
+func GetRecentCounterparties(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, limit int) ([]models.Counterparty, error) {
+	if groupID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("group id missing")
+	}
+	if userID == uuid.Nil {
+		return nil, ErrInvalidInput.Msg("user id missing")
+	}
+
+	query := `
+		SELECT u.user_id, u.user_name, extract(epoch from MAX(e.created_at))::bigint AS last_activity_at
+		FROM expenses e
+		JOIN expense_splits mine ON mine.expense_id = e.expense_id AND mine.user_id = $2
+		JOIN expense_splits other ON other.expense_id = e.expense_id AND other.user_id != $2
+		JOIN group_members gm ON gm.group_id = e.group_id AND gm.user_id = other.user_id
+		JOIN users u ON u.user_id = other.user_id
+		WHERE e.group_id = $1
+			AND e.deleted_at IS NULL
+		GROUP BY u.user_id, u.user_name
+		ORDER BY last_activity_at DESC, u.user_id
+		LIMIT $3`
+
+	rows, err := pool.Query(ctx, query, groupID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counterparties := make([]models.Counterparty, 0, limit)
+	for rows.Next() {
+		var cp models.Counterparty
+		if err := rows.Scan(&cp.UserID, &cp.Name, &cp.LastActivityAt); err != nil {
+			return nil, err
+		}
+		counterparties = append(counterparties, cp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counterparties, nil
+}
+
+// coverSettledExpenses links a new settlement to the expenses it settles: the group's live,
+// non-settlement expenses in which one of the two settling users paid and the other owes.
+// Amounts are not matched, so any settlement between the pair covers all of those expenses,
+// even a partial one that leaves some of the debt open.
+func coverSettledExpenses(ctx context.Context, tx pgx.Tx, settlement *models.ExpenseDetails) error {
+	var payerID, receiverID uuid.UUID
+	for _, split := range settlement.Splits {
+		if split.IsPaid {
+			payerID = split.UserID
+		} else {
+			receiverID = split.UserID
+		}
+	}
+	if payerID == uuid.Nil || receiverID == uuid.Nil {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO settlement_covers (settlement_id, expense_id)
+		SELECT $1, e.expense_id
+		FROM expenses e
+		WHERE e.group_id = $2
+			AND e.is_settlement = false
+			AND e.deleted_at IS NULL
+			AND EXISTS (
+				SELECT 1
+				FROM expense_splits a
+				JOIN expense_splits b ON b.expense_id = a.expense_id
+				WHERE a.expense_id = e.expense_id
+					AND a.user_id = $3
+					AND b.user_id = $4
+					AND a.is_paid <> b.is_paid
+			)
+		ON CONFLICT DO NOTHING`,
+		settlement.ExpenseID, settlement.GroupID, payerID, receiverID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to link settled expenses: %w", err)
+	}
+	return nil
+}
+
+// GetCoveringSettlement returns the most recent live settlement that cleared the expense,
+// or uuid.Nil if no settlement covers it.
+func GetCoveringSettlement(ctx context.Context, pool *pgxpool.Pool, expenseID uuid.UUID) (uuid.UUID, error) {
+	var settlementID uuid.UUID
+	err := pool.QueryRow(ctx, `
+		SELECT s.expense_id
+		FROM settlement_covers sc
+		JOIN expenses s ON s.expense_id = sc.settlement_id
+		WHERE sc.expense_id = $1
+			AND s.deleted_at IS NULL
+		ORDER BY s.created_at DESC
+		LIMIT 1`,
+		expenseID,
+	).Scan(&settlementID)
+	if err == pgx.ErrNoRows {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return settlementID, nil
 }
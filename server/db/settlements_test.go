@@ -0,0 +1,125 @@
+package db_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/db/dbtest"
+	"github.com/pranaovs/qashare/models"
+)
+
+func TestOptimizeSettlementsDebtAgainstTwoCreditors(t *testing.T) {
+	user, other, creditorA, creditorB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	// The user's debt of 90 is larger than either creditor's claim, so it is split across both
+	balances := map[uuid.UUID]float64{
+		user:      -90,
+		other:     -10,
+		creditorA: 60,
+		creditorB: 40,
+	}
+
+	settlements := db.OptimizeSettlements(balances, user, 0.01)
+
+	got := make(map[uuid.UUID]float64)
+	for _, s := range settlements {
+		got[s.UserID] += s.Amount
+	}
+	want := map[uuid.UUID]float64{creditorA: -60, creditorB: -30}
+	if len(got) != len(want) {
+		t.Fatalf("settlements = %+v, want one leg to each creditor", settlements)
+	}
+	for id, amount := range want {
+		if math.Abs(got[id]-amount) > 0.01 {
+			t.Errorf("settlement with %s = %.2f, want %.2f", id, got[id], amount)
+		}
+	}
+}
+
+func TestOptimizeSettlementsSumsToEachNetBalance(t *testing.T) {
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	balances := map[uuid.UUID]float64{
+		users[0]: -90,
+		users[1]: -10,
+		users[2]: 60,
+		users[3]: 40,
+	}
+
+	// Whichever side of the greedy matches a member lands on, their legs add up to their balance
+	for _, id := range users {
+		var sum float64
+		for _, s := range db.OptimizeSettlements(balances, id, 0.01) {
+			sum += s.Amount
+		}
+		if math.Abs(sum-balances[id]) > 0.01 {
+			t.Errorf("settlements for %s sum to %.2f, want %.2f", id, sum, balances[id])
+		}
+	}
+}
+
+func TestGetSettlementThreePayersFourDebtors(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+
+	payers := []models.User{dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)}
+	debtors := []models.User{dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)}
+	var memberIDs []uuid.UUID
+	for _, u := range append(payers[1:], debtors...) {
+		memberIDs = append(memberIDs, u.UserID)
+	}
+	group := dbtest.Group(t, pool, payers[0].UserID, memberIDs...)
+
+	// The payers cover 1/2, 1/3 and 1/6 of 120, so each debt is spread across them in that proportion
+	paid := []float64{60, 40, 20}
+	owed := []float64{40, 30, 30, 20}
+	var splits []models.ExpenseSplit
+	for i, u := range payers {
+		splits = append(splits, dbtest.Paid(u.UserID, paid[i]))
+	}
+	for i, u := range debtors {
+		splits = append(splits, dbtest.Owes(u.UserID, owed[i]))
+	}
+	dbtest.Expense(t, pool, group.GroupID, payers[0].UserID, 120, splits...)
+
+	net := make(map[uuid.UUID]float64)
+	for i, u := range payers {
+		net[u.UserID] = paid[i]
+	}
+	for i, u := range debtors {
+		net[u.UserID] = -owed[i]
+	}
+
+	// The first debtor owes each payer their share of 40
+	pairwise, err := db.GetSettlement(ctx, pool, debtors[0].UserID, group.GroupID, 0.01, false)
+	if err != nil {
+		t.Fatalf("GetSettlement: %v", err)
+	}
+	want := map[uuid.UUID]float64{payers[0].UserID: -20, payers[1].UserID: -40.0 / 3, payers[2].UserID: -20.0 / 3}
+	if len(pairwise) != len(want) {
+		t.Fatalf("pairwise settlements = %+v, want one per payer", pairwise)
+	}
+	for _, s := range pairwise {
+		if math.Abs(s.Amount-want[s.UserID]) > 0.01 {
+			t.Errorf("pairwise settlement with %s = %.4f, want %.4f", s.UserID, s.Amount, want[s.UserID])
+		}
+	}
+
+	// In both modes, every member's settlements net to their balance
+	for _, simplify := range []bool{false, true} {
+		for id, balance := range net {
+			settlements, err := db.GetSettlement(ctx, pool, id, group.GroupID, 0.01, simplify)
+			if err != nil {
+				t.Fatalf("GetSettlement: %v", err)
+			}
+			var sum float64
+			for _, s := range settlements {
+				sum += s.Amount
+			}
+			if math.Abs(sum-balance) > 0.01 {
+				t.Errorf("simplify=%v: settlements for %s sum to %.4f, want %.2f", simplify, id, sum, balance)
+			}
+		}
+	}
+}
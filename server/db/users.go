@@ -24,8 +24,11 @@ import (
 // Takes a User model with Name, Email, PasswordHash, and EmailVerified populated.
 // If EmailVerified is false, a verification token is created inside the same transaction
 // and its UUID is returned. If EmailVerified is true, uuid.Nil is returned.
+// If defaultGroup is non-nil, it is created in the same transaction with the user as its
+// creator and only member, unless an unverified user is re-registering (they already have one).
+// Its GroupID, CreatedBy and CreatedAt fields are populated on success.
 // Returns ErrDuplicateKey if a verified non-guest user with the email already exists.
-func CreateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, verificationExpiry time.Duration) (uuid.UUID, error) {
+func CreateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, verificationExpiry time.Duration, defaultGroup *models.Group) (uuid.UUID, error) {
 	user.Guest = false
 	var verificationToken uuid.UUID
 
@@ -34,6 +37,7 @@ func CreateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, veri
 		var existingUserID uuid.UUID
 		var isGuest bool
 		var existingEmailVerified bool
+		reregistering := false
 		err := tx.QueryRow(ctx,
 			`SELECT user_id, COALESCE(is_guest, false), email_verified FROM users WHERE email = $1 FOR UPDATE`,
 			user.Email,
@@ -47,6 +51,7 @@ func CreateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, veri
 
 			if !isGuest {
 				// Unverified user re-registering — update credentials and resend verification
+				reregistering = true
 				query := `UPDATE users
 					SET user_name = $1, password_hash = $2, email_verified = $3, created_at = NOW()
 					WHERE user_id = $4
@@ -91,22 +96,17 @@ func CreateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, veri
 			return err
 		}
 
-		// Create verification token if email is not yet verified
-		if !user.EmailVerified {
-			_, err = tx.Exec(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1`, user.UserID)
-			if err != nil {
+		if defaultGroup != nil && !reregistering {
+			defaultGroup.CreatedBy = user.UserID
+			if err := insertGroup(ctx, tx, defaultGroup); err != nil {
 				return err
 			}
+		}
 
-			err = tx.QueryRow(ctx,
-				`INSERT INTO email_verification_tokens (user_id, expires_at)
-				VALUES ($1, NOW() + make_interval(secs => $2))
-				RETURNING token`,
-				user.UserID, verificationExpiry.Seconds(),
-			).Scan(&verificationToken)
-			if err != nil {
-				return err
-			}
+		// Create verification token if email is not yet verified
+		if !user.EmailVerified {
+			verificationToken, err = issueVerificationToken(ctx, tx, user.UserID, nil, verificationExpiry)
+			return err
 		}
 
 		return nil
@@ -201,7 +201,7 @@ func GetUserFromEmail(ctx context.Context, pool *pgxpool.Pool, email string) (mo
 // GetUserCredentials retrieves the user ID, password hash, and email verification
 // status for authentication. This function is specifically designed for login verification.
 // Returns ErrNotFound if no user with the email exists or if the user has no password (guest).
-// The caller is responsible for checking emailVerified against the app config.
+// The caller decides whether an unverified email should restrict the user.
 func GetUserCredentials(ctx context.Context, pool *pgxpool.Pool, email string) (uuid.UUID, string, bool, error) {
 	var userID uuid.UUID
 	var passwordHash *string
@@ -226,16 +226,62 @@ func GetUserCredentials(ctx context.Context, pool *pgxpool.Pool, email string) (
 	return userID, *passwordHash, emailVerified, nil
 }
 
+// GetUserPasswordHash retrieves the password hash of a user.
+// Returns ErrNotFound if the user doesn't exist, and ErrInvalidInput if the user has no password (guests and deleted accounts).
+func GetUserPasswordHash(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (string, error) {
+	var passwordHash *string
+	err := pool.QueryRow(ctx, `SELECT password_hash FROM users WHERE user_id = $1`, userID).Scan(&passwordHash)
+	if err == pgx.ErrNoRows {
+		return "", ErrNotFound.Msg("user not found")
+	}
+	if err != nil {
+		return "", err
+	}
+	if passwordHash == nil {
+		return "", ErrInvalidInput.Msg("user has no password")
+	}
+	return *passwordHash, nil
+}
+
+// UpdateUserPassword replaces a user's password hash.
+// If keepSessionID is non-nil, every other refresh token of the user is revoked in the same
+// transaction, logging out all sessions except the one that changed the password.
+func UpdateUserPassword(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, passwordHash string, keepSessionID *uuid.UUID) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		result, err := tx.Exec(ctx,
+			`UPDATE users SET password_hash = $2 WHERE user_id = $1 AND password_hash IS NOT NULL`,
+			userID, passwordHash,
+		)
+		if err != nil {
+			return err
+		}
+		if result.RowsAffected() == 0 {
+			return ErrNotFound.Msg("user not found")
+		}
+
+		if keepSessionID == nil {
+			return nil
+		}
+		_, err = tx.Exec(ctx,
+			`DELETE FROM refresh_tokens WHERE user_id = $1 AND token_id <> $2`,
+			userID, *keepSessionID,
+		)
+		return err
+	})
+}
+
 // GetUser retrieves a user by their unique user ID.
 // Returns ErrNotFound if no user with the ID exists.
 func GetUser(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (models.User, error) {
 	var user models.User
-	query := `SELECT user_id, user_name, email, email_verified, COALESCE(is_guest, false), extract(epoch from created_at)::bigint
+	query := `SELECT user_id, user_name, email, email_verified, pending_email, COALESCE(is_guest, false), extract(epoch from created_at)::bigint,
+			default_currency, locale
 		FROM users
 		WHERE user_id = $1`
 
 	err := pool.QueryRow(ctx, query, userID).Scan(
-		&user.UserID, &user.Name, &user.Email, &user.EmailVerified, &user.Guest, &user.CreatedAt,
+		&user.UserID, &user.Name, &user.Email, &user.EmailVerified, &user.PendingEmail, &user.Guest, &user.CreatedAt,
+		&user.Currency, &user.Locale,
 	)
 
 	if err == pgx.ErrNoRows {
@@ -272,12 +318,75 @@ func UsersRelated(ctx context.Context, pool *pgxpool.Pool, userID1, userID2 uuid
 	return areRelated, nil
 }
 
+// GetRelatedUsers returns the distinct users that share at least one group with userID
+// (the user's "address book"), excluding the user themselves.
+// Results are ordered by name, then user ID, and paginated with a keyset cursor.
+// An optional nameFilter restricts results to names containing it (case-insensitive).
+// Returns the page of users and the cursor for the next page (empty on the last page).
+// Returns ErrInvalidInput if the cursor is malformed.
+func GetRelatedUsers(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, nameFilter string, limit int, cursor string) ([]models.User, string, error) {
+	var afterName *string
+	var afterID *uuid.UUID
+	if cursor != "" {
+		values, err := utils.DecodeCursor(cursor, 2)
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		id, err := uuid.Parse(values[1])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		afterName, afterID = &values[0], &id
+	}
+
+	query := `
+		SELECT DISTINCT u.user_id, u.user_name, u.email, u.is_guest, extract(epoch from u.created_at)::bigint
+		FROM group_members me
+		JOIN group_members gm ON gm.group_id = me.group_id AND gm.user_id != me.user_id
+		JOIN users u ON u.user_id = gm.user_id
+		WHERE me.user_id = $1
+			AND ($2 = '' OR u.user_name ILIKE '%' || $2 || '%' ESCAPE '\')
+			AND ($3::text IS NULL OR (u.user_name, u.user_id) > ($3::text, $4::uuid))
+		ORDER BY u.user_name, u.user_id
+		LIMIT $5`
+
+	// Fetch one extra row to know whether another page exists
+	rows, err := pool.Query(ctx, query, userID, escapeLike(nameFilter), afterName, afterID, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0, limit)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.UserID, &user.Name, &user.Email, &user.Guest, &user.CreatedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[limit-1]
+		nextCursor = utils.EncodeCursor(last.Name, last.UserID.String())
+	}
+
+	return users, nextCursor, nil
+}
+
 // OwnerOfGroups returns all groups where the user is the creator/administrator.
 // Groups are returned in descending order by creation date (newest first).
 // This is useful for showing users the groups they manage.
 func OwnerOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([]models.Group, error) {
 	query := `
-		SELECT group_id, group_name, description, created_by, extract(epoch from created_at)::bigint, is_private
+		SELECT group_id, group_name, description, created_by, extract(epoch from created_at)::bigint, is_private, base_currency, require_description
 		FROM groups
 		WHERE created_by = $1
 		ORDER BY created_at DESC`
@@ -292,7 +401,7 @@ func OwnerOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([
 	groups := make([]models.Group, 0)
 	for rows.Next() {
 		var g models.Group
-		err := rows.Scan(&g.GroupID, &g.Name, &g.Description, &g.CreatedBy, &g.CreatedAt, &g.Private)
+		err := rows.Scan(&g.GroupID, &g.Name, &g.Description, &g.CreatedBy, &g.CreatedAt, &g.Private, &g.Currency, &g.RequireDesc)
 		if err != nil {
 			return nil, err
 		}
@@ -309,14 +418,16 @@ func OwnerOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([
 
 // MemberOfGroups returns all groups where the user is a member.
 // This includes both groups the user created and groups they were added to.
-// Groups are returned in descending order by creation date (newest first).
-func MemberOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([]models.Group, error) {
+// Groups the user pinned come first; within each part groups are returned in descending
+// order by creation date (newest first).
+func MemberOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) ([]models.MemberGroup, error) {
 	query := `
-		SELECT g.group_id, g.group_name, g.description, g.created_by, extract(epoch from g.created_at)::bigint, g.is_private
+		SELECT g.group_id, g.group_name, g.description, g.created_by, extract(epoch from g.created_at)::bigint, g.is_private, g.base_currency, g.require_description,
+			gm.pinned
 		FROM groups g
 		JOIN group_members gm ON gm.group_id = g.group_id
 		WHERE gm.user_id = $1
-		ORDER BY g.created_at DESC`
+		ORDER BY gm.pinned DESC, g.created_at DESC`
 
 	rows, err := pool.Query(ctx, query, userID)
 	if err != nil {
@@ -325,10 +436,10 @@ func MemberOfGroups(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (
 	defer rows.Close()
 
 	// Scan results into groups slice
-	groups := make([]models.Group, 0)
+	groups := make([]models.MemberGroup, 0)
 	for rows.Next() {
-		var g models.Group
-		err := rows.Scan(&g.GroupID, &g.Name, &g.Description, &g.CreatedBy, &g.CreatedAt, &g.Private)
+		var g models.MemberGroup
+		err := rows.Scan(&g.GroupID, &g.Name, &g.Description, &g.CreatedBy, &g.CreatedAt, &g.Private, &g.Currency, &g.RequireDesc, &g.Pinned)
 		if err != nil {
 			return nil, err
 		}
@@ -359,38 +470,102 @@ func UserExists(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) error
 	return nil
 }
 
-// UsersExist checks if all users with the given IDs exist in the database.
-// Returns nil if all users exist, or ErrNotFound with the first missing user ID if any are missing.
-func UsersExist(ctx context.Context, pool *pgxpool.Pool, userIDs []uuid.UUID) error {
+// GetGroupUsersByIDs is GetUsersByIDs for responses scoped to a group: it returns the public details
+// of the given users in a single query, in the order of userIDs. JoinedAt is when the user joined groupID,
+// or 0 if they are not a member. Unknown IDs are skipped, and deleted users are reported with Deleted set and no email.
+func GetGroupUsersByIDs(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID, userIDs []uuid.UUID) ([]models.GroupUser, error) {
+	users := make([]models.GroupUser, 0, len(userIDs))
 	if len(userIDs) == 0 {
-		return nil
+		return users, nil
 	}
 
-	query := `SELECT user_id FROM users WHERE user_id = ANY($1::uuid[])`
-	rows, err := pool.Query(ctx, query, userIDs)
+	rows, err := pool.Query(ctx,
+		`SELECT u.user_id, u.user_name, u.email, COALESCE(u.is_guest, false),
+			COALESCE(extract(epoch from gm.joined_at)::bigint, 0)
+		FROM users u
+		LEFT JOIN group_members gm ON gm.user_id = u.user_id AND gm.group_id = $1
+		WHERE u.user_id = ANY($2)
+		ORDER BY array_position($2, u.user_id)`,
+		groupID, userIDs,
+	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer rows.Close()
 
-	found := make(map[uuid.UUID]bool, len(userIDs))
 	for rows.Next() {
-		var id uuid.UUID
-		if err := rows.Scan(&id); err != nil {
-			return err
+		var user models.GroupUser
+		if err := rows.Scan(&user.UserID, &user.Name, &user.Email, &user.Guest, &user.JoinedAt); err != nil {
+			return nil, err
 		}
-		found[id] = true
+		// Don't expose the placeholder email of deleted users
+		if isDeletedEmail(user.Email) {
+			user.Email = ""
+			user.Deleted = true
+		}
+		users = append(users, user)
 	}
 	if err := rows.Err(); err != nil {
-		return err
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUsersByIDs fetches the given users in a single query (WHERE user_id = ANY($1)).
+// Found users are returned keyed by ID; IDs with no user are returned in missing,
+// in input order and without duplicates. Deleted users are found, since their rows are kept.
+func GetUsersByIDs(ctx context.Context, pool *pgxpool.Pool, userIDs []uuid.UUID) (users map[uuid.UUID]models.User, missing []uuid.UUID, err error) {
+	users = make(map[uuid.UUID]models.User, len(userIDs))
+	missing = make([]uuid.UUID, 0)
+	if len(userIDs) == 0 {
+		return users, missing, nil
 	}
 
-	for _, id := range userIDs {
-		if !found[id] {
-			return ErrNotFound.Msgf("user with id %s not found", id)
+	rows, err := pool.Query(ctx,
+		`SELECT user_id, user_name, email, email_verified, COALESCE(is_guest, false), extract(epoch from created_at)::bigint,
+			default_currency, locale
+		FROM users
+		WHERE user_id = ANY($1::uuid[])`,
+		userIDs,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.UserID, &user.Name, &user.Email, &user.EmailVerified, &user.Guest, &user.CreatedAt,
+			&user.Currency, &user.Locale,
+		)
+		if err != nil {
+			return nil, nil, err
 		}
+		users[user.UserID] = user
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
 	}
 
+	for _, id := range utils.GetUniqueUserIDs(userIDs) {
+		if _, ok := users[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return users, missing, nil
+}
+
+// UsersExist checks if all users with the given IDs exist in the database.
+// Returns nil if all users exist, or ErrNotFound naming every missing user ID if any are missing.
+func UsersExist(ctx context.Context, pool *pgxpool.Pool, userIDs []uuid.UUID) error {
+	_, missing, err := GetUsersByIDs(ctx, pool, userIDs)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return ErrNotFound.Msgf("users not found: %s", utils.JoinUserIDs(missing))
+	}
 	return nil
 }
 
@@ -445,47 +620,131 @@ func AllMembersOfGroup(ctx context.Context, pool *pgxpool.Pool, userIDs []uuid.U
 	return nil
 }
 
-// UpdateUser updates an existing user's editable fields (name and email).
-// This operation updates the user's basic information.
-// Returns an error if validation fails or the operation fails.
-func UpdateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User) error {
+// UpdateUser updates an existing user's editable fields (name, email, default currency and locale).
+//
+// With a zero verificationExpiry a new email takes effect immediately. Otherwise the current
+// email stays in place (and keeps working for login) while the new one is stored as the user's
+// pending email, and a verification token for it is returned; VerifyEmail swaps it in.
+// A pending email is kept only while user.PendingEmail is set and the email is unchanged;
+// otherwise it is cleared and its verification tokens are invalidated, so callers cancel a
+// pending change by submitting the current email with user.PendingEmail nil.
+// user.Email and user.PendingEmail are updated to reflect what was stored.
+//
+// Returns ErrDuplicateKey if another account owns the new email, including guest accounts,
+// which can only be claimed by registering with their email.
+// Returns ErrNotFound if no user with the ID exists.
+func UpdateUser(ctx context.Context, pool *pgxpool.Pool, user *models.User, verificationExpiry time.Duration) (uuid.UUID, error) {
 	// Validate input
 	if user.UserID == uuid.Nil {
-		return ErrInvalidInput.Msg("user_id is required")
+		return uuid.Nil, ErrInvalidInput.Msg("user_id is required")
 	}
 	if user.Name == "" {
-		return ErrInvalidInput.Msg("name is required")
+		return uuid.Nil, ErrInvalidInput.Msg("name is required")
 	}
 	if user.Email == "" {
-		return ErrInvalidInput.Msg("email is required")
+		return uuid.Nil, ErrInvalidInput.Msg("email is required")
 	}
 
-	// Update user fields (password_hash is immutable and not updated here)
-	updateQuery := `UPDATE users
-		SET user_name = $2,
-			email = $3
-		WHERE user_id = $1`
+	var verificationToken uuid.UUID
+	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		var currentEmail string
+		var pendingEmail *string
+		err := tx.QueryRow(ctx,
+			`SELECT email, pending_email FROM users WHERE user_id = $1 FOR UPDATE`,
+			user.UserID,
+		).Scan(&currentEmail, &pendingEmail)
+		if err == pgx.ErrNoRows {
+			return ErrNotFound.Msgf("user with id %s not found", user.UserID)
+		}
+		if err != nil {
+			return err
+		}
 
-	result, err := pool.Exec(
-		ctx,
-		updateQuery,
-		user.UserID,
-		user.Name,
-		user.Email,
-	)
-	if err != nil {
-		if IsDuplicateKey(err) {
-			return ErrDuplicateKey
+		newEmail := user.Email
+		changed := newEmail != currentEmail
+		if changed {
+			if err := checkEmailAvailable(ctx, tx, newEmail, user.UserID); err != nil {
+				return err
+			}
+		}
+
+		email := newEmail
+		cancelled := false
+		switch {
+		case changed && verificationExpiry > 0:
+			email, pendingEmail = currentEmail, &newEmail
+		case changed || user.PendingEmail == nil:
+			cancelled = pendingEmail != nil
+			pendingEmail = nil
+		}
+
+		// Update user fields (password_hash is immutable and not updated here)
+		_, err = tx.Exec(ctx,
+			`UPDATE users
+			SET user_name = $2,
+				email = $3,
+				pending_email = $4,
+				default_currency = $5,
+				locale = $6
+			WHERE user_id = $1`,
+			user.UserID, user.Name, email, pendingEmail, user.Currency, user.Locale,
+		)
+		if err != nil {
+			if IsDuplicateKey(err) {
+				return ErrDuplicateKey
+			}
+			return err
+		}
+		user.Email, user.PendingEmail = email, pendingEmail
+
+		if changed && verificationExpiry > 0 {
+			verificationToken, err = issueVerificationToken(ctx, tx, user.UserID, &newEmail, verificationExpiry)
+			return err
+		}
+		if cancelled {
+			_, err = tx.Exec(ctx,
+				`DELETE FROM email_verification_tokens WHERE user_id = $1 AND email IS NOT NULL`,
+				user.UserID,
+			)
 		}
 		return err
+	})
+	if err != nil {
+		return uuid.Nil, err
 	}
+	return verificationToken, nil
+}
 
-	// Check if user was found
-	if result.RowsAffected() == 0 {
-		return ErrNotFound.Msgf("user with id %s not found", user.UserID)
+// checkEmailAvailable returns ErrDuplicateKey if an account other than userID owns email.
+// Guest accounts get their own message: they are claimed by registering with their email,
+// not by moving another account onto it.
+func checkEmailAvailable(ctx context.Context, tx pgx.Tx, email string, userID uuid.UUID) error {
+	var isGuest bool
+	err := tx.QueryRow(ctx,
+		`SELECT COALESCE(is_guest, false) FROM users WHERE email = $1 AND user_id <> $2`,
+		email, userID,
+	).Scan(&isGuest)
+	if err == pgx.ErrNoRows {
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+	if isGuest {
+		return ErrDuplicateKey.Msgf("email %s belongs to a guest account; register with it to claim the account", email)
+	}
+	return ErrDuplicateKey.Msgf("user with email %s already exists", email)
+}
 
-	return nil
+// Deleted users keep a unique placeholder email of the form deleted_<user id>@deleted
+const (
+	deletedEmailPrefix = "deleted_"
+	deletedEmailSuffix = "@deleted"
+)
+
+// isDeletedEmail reports whether email is the placeholder of an anonymized user.
+func isDeletedEmail(email string) bool {
+	return strings.HasPrefix(email, deletedEmailPrefix) && strings.HasSuffix(email, deletedEmailSuffix)
 }
 
 // DeleteUser anonymizes a user instead of hard-deleting, so that FK references
@@ -500,8 +759,8 @@ func DeleteUser(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) error
 		if len(suffix) > 4 {
 			suffix = suffix[len(suffix)-4:]
 		}
-		anonName := "Deleted User (" + suffix + ")"
-		anonEmail := "deleted_" + userID.String() + "@deleted"
+		anonName := models.DeletedUserName + " (" + suffix + ")"
+		anonEmail := deletedEmailPrefix + userID.String() + deletedEmailSuffix
 
 		query := `UPDATE users
 			SET user_name = $2, email = $3, password_hash = NULL
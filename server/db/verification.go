@@ -7,20 +7,24 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/models"
 )
 
 // VerifyEmail looks up the verification token, checks expiry, sets email_verified=true,
 // and deletes all verification tokens for the user.
-// Returns ErrNotFound if the token doesn't exist, or ErrExpiredToken if it has expired.
+// A token issued for an email change also replaces the user's email with the pending one.
+// Returns ErrNotFound if the token doesn't exist, ErrExpiredToken if it has expired,
+// or ErrDuplicateKey if another account took the pending email in the meantime.
 func VerifyEmail(ctx context.Context, pool *pgxpool.Pool, token uuid.UUID) error {
 	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
 		var userID uuid.UUID
 		var expiresAt time.Time
+		var email *string
 
 		err := tx.QueryRow(ctx,
-			`SELECT user_id, expires_at FROM email_verification_tokens WHERE token = $1 FOR UPDATE`,
+			`SELECT user_id, expires_at, email FROM email_verification_tokens WHERE token = $1 FOR UPDATE`,
 			token,
-		).Scan(&userID, &expiresAt)
+		).Scan(&userID, &expiresAt, &email)
 
 		if err == pgx.ErrNoRows {
 			return ErrNotFound
@@ -29,12 +33,26 @@ func VerifyEmail(ctx context.Context, pool *pgxpool.Pool, token uuid.UUID) error
 			return err
 		}
 
+		// An expired token is left to DeleteExpiredVerificationTokens; deleting it here would be
+		// rolled back along with the rest of the transaction
 		if time.Now().After(expiresAt) {
-			_, _ = tx.Exec(ctx, `DELETE FROM email_verification_tokens WHERE token = $1`, token)
 			return ErrExpiredToken
 		}
 
-		_, err = tx.Exec(ctx, `UPDATE users SET email_verified = true WHERE user_id = $1`, userID)
+		if email != nil {
+			if err := checkEmailAvailable(ctx, tx, *email, userID); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx,
+				`UPDATE users SET email = $2, pending_email = NULL, email_verified = true WHERE user_id = $1`,
+				userID, *email,
+			)
+			if IsDuplicateKey(err) {
+				return ErrDuplicateKey.Msgf("user with email %s already exists", *email)
+			}
+		} else {
+			_, err = tx.Exec(ctx, `UPDATE users SET email_verified = true WHERE user_id = $1`, userID)
+		}
 		if err != nil {
 			return err
 		}
@@ -44,6 +62,101 @@ func VerifyEmail(ctx context.Context, pool *pgxpool.Pool, token uuid.UUID) error
 	})
 }
 
+// GetGuestClaimStatus reports whether an unclaimed guest exists for the email and whether an
+// account with the email is waiting on an unexpired verification token.
+// Unknown emails and fully verified accounts both report false for everything, so the result
+// does not reveal whether a verified account exists.
+func GetGuestClaimStatus(ctx context.Context, pool *pgxpool.Pool, email string) (models.GuestClaimStatus, error) {
+	var status models.GuestClaimStatus
+	err := pool.QueryRow(ctx,
+		`SELECT COALESCE(u.is_guest, false),
+			NOT COALESCE(u.is_guest, false) AND NOT u.email_verified AND EXISTS (
+				SELECT 1 FROM email_verification_tokens t
+				WHERE t.user_id = u.user_id AND t.expires_at > now()
+			)
+		FROM users u
+		WHERE u.email = $1`,
+		email,
+	).Scan(&status.Guest, &status.ClaimPending)
+	if err == pgx.ErrNoRows {
+		return models.GuestClaimStatus{}, nil
+	}
+	if err != nil {
+		return models.GuestClaimStatus{}, err
+	}
+	return status, nil
+}
+
+// CreateVerificationToken issues a fresh verification token for the user, replacing
+// any tokens issued before it. If an email change is pending, the token is for the pending email.
+// Returns the token and the address it must be sent to.
+// Returns ErrNotFound if the user doesn't exist, or ErrInvalidInput if the email is already verified
+// and no change is pending.
+func CreateVerificationToken(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, expiry time.Duration) (uuid.UUID, string, error) {
+	var token uuid.UUID
+	var email string
+	err := WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		var verified bool
+		var pendingEmail *string
+		err := tx.QueryRow(ctx,
+			`SELECT email, email_verified, pending_email FROM users WHERE user_id = $1 FOR UPDATE`,
+			userID,
+		).Scan(&email, &verified, &pendingEmail)
+
+		if err == pgx.ErrNoRows {
+			return ErrNotFound.Msgf("user with id %s not found", userID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if pendingEmail != nil {
+			email = *pendingEmail
+		} else if verified {
+			return ErrInvalidInput.Msg("email already verified")
+		}
+
+		token, err = issueVerificationToken(ctx, tx, userID, pendingEmail, expiry)
+		return err
+	})
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+	return token, email, nil
+}
+
+// EmailVerified reports whether the user's email address has been verified.
+// Returns ErrNotFound if the user doesn't exist.
+func EmailVerified(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID) (bool, error) {
+	var verified bool
+	err := pool.QueryRow(ctx, `SELECT email_verified FROM users WHERE user_id = $1`, userID).Scan(&verified)
+	if err == pgx.ErrNoRows {
+		return false, ErrNotFound.Msgf("user with id %s not found", userID)
+	}
+	if err != nil {
+		return false, err
+	}
+	return verified, nil
+}
+
+// issueVerificationToken deletes the user's existing verification tokens and inserts a new one.
+// email is the pending address the token confirms, or nil for the user's current email.
+func issueVerificationToken(ctx context.Context, tx pgx.Tx, userID uuid.UUID, email *string, expiry time.Duration) (uuid.UUID, error) {
+	_, err := tx.Exec(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var token uuid.UUID
+	err = tx.QueryRow(ctx,
+		`INSERT INTO email_verification_tokens (user_id, expires_at, email)
+		VALUES ($1, NOW() + make_interval(secs => $2), $3)
+		RETURNING token`,
+		userID, expiry.Seconds(), email,
+	).Scan(&token)
+	return token, err
+}
+
 // DeleteExpiredVerificationTokens removes all expired verification tokens.
 func DeleteExpiredVerificationTokens(ctx context.Context, pool *pgxpool.Pool) (int64, error) {
 	result, err := pool.Exec(ctx, `DELETE FROM email_verification_tokens WHERE expires_at <= NOW()`)
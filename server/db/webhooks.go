@@ -0,0 +1,378 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// Webhook delivery limits. Attempts and backoff are configurable, see config.WebhookRetryPolicy.
+const (
+	webhookBatchSize    = 50 // Deliveries attempted per poll
+	webhookDisableAfter = 20 // Consecutive failed attempts after which the webhook is disabled
+	webhookMaxErrorLen  = 500
+
+	// webhookDeliveryLease is how long a claimed delivery is hidden from other pollers.
+	// A batch is sent one delivery at a time, so the lease must outlast every delivery in it
+	// timing out; otherwise another instance re-claims rows still being sent and delivers them twice.
+	webhookDeliveryLease = webhookBatchSize*utils.WebhookTimeout + time.Minute
+)
+
+// webhookDelivery is a pending delivery together with the target it is sent to.
+type webhookDelivery struct {
+	DeliveryID uuid.UUID
+	WebhookID  uuid.UUID
+	Event      string
+	Payload    string
+	Attempts   int
+	URL        string
+	Secret     string
+}
+
+// CreateWebhook registers a webhook for a group.
+// The webhook's ID, CreatedAt and CreatedBy are set on success.
+// Returns ErrNotFound if the group does not exist.
+func CreateWebhook(ctx context.Context, pool *pgxpool.Pool, webhook *models.Webhook, createdBy uuid.UUID) error {
+	if webhook.GroupID == uuid.Nil {
+		return ErrInvalidInput.Msg("group id missing")
+	}
+
+	err := pool.QueryRow(ctx,
+		`INSERT INTO webhooks (group_id, url, secret, events, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING webhook_id, extract(epoch from created_at)::bigint`,
+		webhook.GroupID, webhook.URL, webhook.Secret, webhook.Events, createdBy,
+	).Scan(&webhook.WebhookID, &webhook.CreatedAt)
+	if err != nil {
+		if IsConstraintViolation(err) {
+			return ErrNotFound.Msgf("group with id %s not found", webhook.GroupID)
+		}
+		return err
+	}
+	webhook.CreatedBy = &createdBy
+	return nil
+}
+
+// GetWebhooks lists the webhooks of a group, oldest first. Secrets are not returned.
+func GetWebhooks(ctx context.Context, pool *pgxpool.Pool, groupID uuid.UUID) ([]models.Webhook, error) {
+	rows, err := pool.Query(ctx,
+		`SELECT webhook_id, group_id, url, events, created_by, extract(epoch from created_at)::bigint,
+			consecutive_failures, extract(epoch from disabled_at)::bigint
+		FROM webhooks
+		WHERE group_id = $1
+		ORDER BY created_at, webhook_id`,
+		groupID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		var webhook models.Webhook
+		err := rows.Scan(&webhook.WebhookID, &webhook.GroupID, &webhook.URL, &webhook.Events, &webhook.CreatedBy,
+			&webhook.CreatedAt, &webhook.ConsecutiveFailures, &webhook.DisabledAt)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhookFailures retrieves a page of a webhook's dead-lettered deliveries, newest first.
+// Pass the returned cursor back to fetch the next page; an empty cursor means there are no more pages.
+// Returns ErrNotFound if the group has no webhook with the ID.
+func GetWebhookFailures(ctx context.Context, pool *pgxpool.Pool, groupID, webhookID uuid.UUID, limit int, cursor string) ([]models.WebhookFailure, string, error) {
+	var afterTime *time.Time
+	var afterID *uuid.UUID
+	if cursor != "" {
+		values, err := utils.DecodeCursor(cursor, 2)
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		t, err := time.Parse(time.RFC3339Nano, values[0])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		id, err := uuid.Parse(values[1])
+		if err != nil {
+			return nil, "", ErrInvalidInput.Msg("invalid cursor")
+		}
+		afterTime, afterID = &t, &id
+	}
+
+	exists, err := RecordExists(ctx, pool, "webhooks", "webhook_id = $1 AND group_id = $2", webhookID, groupID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !exists {
+		return nil, "", ErrNotFound.Msg("webhook not found")
+	}
+
+	// Fetch one extra entry to know whether another page exists
+	rows, err := pool.Query(ctx,
+		`SELECT failure_id, webhook_id, delivery_id, event, payload, attempts, last_error, failed_at
+		FROM webhook_failures
+		WHERE webhook_id = $1
+			AND ($2::timestamptz IS NULL OR (failed_at, failure_id) < ($2::timestamptz, $3::uuid))
+		ORDER BY failed_at DESC, failure_id DESC
+		LIMIT $4`,
+		webhookID, afterTime, afterID, limit+1,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	failures := make([]models.WebhookFailure, 0, limit)
+	failedAt := make([]time.Time, 0, limit)
+	for rows.Next() {
+		var failure models.WebhookFailure
+		var rawFailedAt time.Time
+		err := rows.Scan(&failure.FailureID, &failure.WebhookID, &failure.DeliveryID, &failure.Event,
+			&failure.Payload, &failure.Attempts, &failure.LastError, &rawFailedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		failure.FailedAt = rawFailedAt.Unix()
+		failures = append(failures, failure)
+		failedAt = append(failedAt, rawFailedAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(failures) > limit {
+		failures = failures[:limit]
+		nextCursor = utils.EncodeCursor(failedAt[limit-1].Format(time.RFC3339Nano), failures[limit-1].FailureID.String())
+	}
+	return failures, nextCursor, nil
+}
+
+// DeleteWebhook removes a webhook of a group together with its pending deliveries.
+// Returns ErrNotFound if the group has no webhook with the ID.
+func DeleteWebhook(ctx context.Context, pool *pgxpool.Pool, groupID, webhookID uuid.UUID) error {
+	result, err := pool.Exec(ctx, `DELETE FROM webhooks WHERE webhook_id = $1 AND group_id = $2`, webhookID, groupID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound.Msg("webhook not found")
+	}
+	return nil
+}
+
+// enqueueWebhookEvent queues a delivery of an event for every enabled webhook of the group subscribed to it.
+// It runs inside the transaction that produced the event, so an event is queued if and only if it happened.
+func enqueueWebhookEvent(ctx context.Context, tx pgx.Tx, groupID, actorID uuid.UUID, event string, data any) error {
+	payload, err := json.Marshal(models.WebhookPayload{
+		Event:     event,
+		GroupID:   groupID,
+		ActorID:   actorID,
+		CreatedAt: time.Now().Unix(),
+		Data:      data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT webhook_id, $2, $3::jsonb
+		FROM webhooks
+		WHERE group_id = $1 AND disabled_at IS NULL AND $2 = ANY(events)`,
+		groupID, event, string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook event: %w", err)
+	}
+	return nil
+}
+
+// claimWebhookDeliveries picks up to limit due deliveries and pushes their next attempt back by the lease,
+// so concurrent pollers (e.g. other instances) skip them while they are being sent.
+func claimWebhookDeliveries(ctx context.Context, pool *pgxpool.Pool, limit int) ([]webhookDelivery, error) {
+	rows, err := pool.Query(ctx,
+		`UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM webhooks w
+		WHERE w.webhook_id = d.webhook_id
+			AND d.delivery_id IN (
+				SELECT delivery_id FROM webhook_deliveries
+				WHERE next_attempt_at <= now()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING d.delivery_id, d.webhook_id, d.event, d.payload::text, d.attempts, w.url, w.secret`,
+		limit, webhookDeliveryLease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []webhookDelivery
+	for rows.Next() {
+		var d webhookDelivery
+		if err := rows.Scan(&d.DeliveryID, &d.WebhookID, &d.Event, &d.Payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// completeWebhookDelivery removes a delivered event and resets the webhook's failure count.
+func completeWebhookDelivery(ctx context.Context, pool *pgxpool.Pool, d webhookDelivery) error {
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM webhook_deliveries WHERE delivery_id = $1`, d.DeliveryID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx,
+			`UPDATE webhooks SET consecutive_failures = 0 WHERE webhook_id = $1 AND consecutive_failures <> 0`,
+			d.WebhookID,
+		)
+		return err
+	})
+}
+
+// failWebhookDelivery records a failed attempt.
+// The delivery is rescheduled with exponential backoff, or moved to the dead-letter table once it has used all its attempts.
+// Once the webhook has failed webhookDisableAfter times in a row it is disabled and all its pending deliveries are dead-lettered.
+func failWebhookDelivery(ctx context.Context, pool *pgxpool.Pool, d webhookDelivery, retry config.WebhookRetryPolicy, sendErr error) error {
+	lastError := sendErr.Error()
+	if len(lastError) > webhookMaxErrorLen {
+		lastError = lastError[:webhookMaxErrorLen]
+	}
+	attempts := d.Attempts + 1
+
+	return WithTransaction(ctx, pool, func(ctx context.Context, tx pgx.Tx) error {
+		var disabled bool
+		err := tx.QueryRow(ctx,
+			`UPDATE webhooks
+			SET consecutive_failures = consecutive_failures + 1,
+				disabled_at = COALESCE(disabled_at, CASE WHEN consecutive_failures + 1 >= $2 THEN now() END)
+			WHERE webhook_id = $1
+			RETURNING disabled_at IS NOT NULL`,
+			d.WebhookID, webhookDisableAfter,
+		).Scan(&disabled)
+		if err == pgx.ErrNoRows {
+			// The webhook was deleted while the delivery was in flight
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if disabled {
+			slog.WarnContext(ctx, "Disabled webhook after repeated failed deliveries",
+				"webhook_id", d.WebhookID, "failures", webhookDisableAfter)
+			// The failed delivery keeps its own error; the others never got to their next attempt
+			_, err := tx.Exec(ctx,
+				`WITH moved AS (
+					DELETE FROM webhook_deliveries WHERE webhook_id = $1
+					RETURNING webhook_id, delivery_id, event, payload, attempts
+				)
+				INSERT INTO webhook_failures (webhook_id, delivery_id, event, payload, attempts, last_error)
+				SELECT webhook_id, delivery_id, event, payload,
+					CASE WHEN delivery_id = $2 THEN $3 ELSE attempts END,
+					CASE WHEN delivery_id = $2 THEN $4 ELSE 'webhook disabled after repeated failed deliveries' END
+				FROM moved`,
+				d.WebhookID, d.DeliveryID, attempts, lastError,
+			)
+			return err
+		}
+
+		if attempts >= retry.MaxAttempts {
+			slog.WarnContext(ctx, "Dead-lettered webhook delivery after its last attempt",
+				"delivery_id", d.DeliveryID, "webhook_id", d.WebhookID, "event", d.Event, "attempts", attempts)
+			_, err := tx.Exec(ctx,
+				`WITH moved AS (
+					DELETE FROM webhook_deliveries WHERE delivery_id = $1
+					RETURNING webhook_id, delivery_id, event, payload
+				)
+				INSERT INTO webhook_failures (webhook_id, delivery_id, event, payload, attempts, last_error)
+				SELECT webhook_id, delivery_id, event, payload, $2, $3 FROM moved`,
+				d.DeliveryID, attempts, lastError,
+			)
+			return err
+		}
+
+		// Doubled per attempt; stopping at the cap keeps large attempt counts from overflowing
+		backoff := retry.BaseBackoff
+		for i := 1; i < attempts && backoff < retry.MaxBackoff; i++ {
+			backoff *= 2
+		}
+		backoff = min(backoff, retry.MaxBackoff)
+		_, err = tx.Exec(ctx,
+			`UPDATE webhook_deliveries
+			SET attempts = $2, next_attempt_at = now() + make_interval(secs => $3)
+			WHERE delivery_id = $1`,
+			d.DeliveryID, attempts, backoff.Seconds(),
+		)
+		return err
+	})
+}
+
+// deliverWebhooks sends every due delivery once, recording the outcome of each.
+func deliverWebhooks(ctx context.Context, pool *pgxpool.Pool, retry config.WebhookRetryPolicy) {
+	deliveries, err := claimWebhookDeliveries(ctx, pool, webhookBatchSize)
+	if err != nil {
+		slog.Error("Failed to claim webhook deliveries", "error", err)
+		return
+	}
+
+	for _, d := range deliveries {
+		sendErr := utils.SendWebhook(ctx, d.URL, d.Secret, d.Event, d.DeliveryID, []byte(d.Payload))
+		if sendErr == nil {
+			err = completeWebhookDelivery(ctx, pool, d)
+		} else {
+			slog.Debug("Webhook delivery failed",
+				"delivery_id", d.DeliveryID, "webhook_id", d.WebhookID, "attempt", d.Attempts+1, "error", sendErr)
+			err = failWebhookDelivery(ctx, pool, d, retry, sendErr)
+		}
+		if err != nil {
+			slog.Error("Failed to record webhook delivery", "delivery_id", d.DeliveryID, "error", err)
+		}
+	}
+}
+
+// StartWebhookDelivery starts a background goroutine that sends queued webhook deliveries every interval.
+// Deliveries happen outside the request that produced the event, so slow endpoints do not add latency.
+// Failed deliveries are retried according to retry.
+// The returned channel is closed once the goroutine exits after ctx is cancelled.
+func StartWebhookDelivery(ctx context.Context, pool *pgxpool.Pool, interval time.Duration, retry config.WebhookRetryPolicy) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Webhook delivery stopped")
+				return
+			case <-ticker.C:
+				deliverWebhooks(ctx, pool, retry)
+			}
+		}
+	}()
+	return done
+}
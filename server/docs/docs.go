@@ -42,9 +42,159 @@ const docTemplate = `{
                 }
             }
         },
+        "/metrics": {
+            "get": {
+                "description": "Report database connection pool statistics and request counts by status code in the Prometheus text exposition format.\nRequires either the METRICS_TOKEN as a bearer token in the Authorization header, or an API key with the metrics scope in the X-API-Key header. The read replica pool is only reported when one is configured.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Metrics endpoint",
+                "responses": {
+                    "200": {
+                        "description": "Metrics in the Prometheus text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_API_KEY: Neither a valid metrics token nor a valid API key was provided",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "NO_PERMISSIONS: The API key lacks the metrics scope",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check if the API can serve traffic. Verifies the database is reachable.\nWith deep=true, also verifies that every migration file has been applied, so instances on an old schema are taken out of rotation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check endpoint",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Also check for pending migrations",
+                        "name": "deep",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Server is ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessCheck"
+                        }
+                    },
+                    "503": {
+                        "description": "Database is unreachable or migrations are pending",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessCheck"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/guest/claim/status": {
+            "get": {
+                "description": "Check whether an unclaimed guest account exists for an email, and whether a claim (a registration with that email) is waiting on email verification.\nTo limit enumeration, the endpoint is rate limited and unknown emails are indistinguishable from verified accounts: both report false for every field.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get guest claim status for an email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email address",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Claim status for the email",
+                        "schema": {
+                            "$ref": "#/definitions/models.GuestClaimStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_EMAIL: Missing or malformed email",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/auth/introspect": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return the decoded claims of the access token used for the request, without touching the database. Clients can use expires_in to refresh the token before it expires.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Introspect the access token",
+                "responses": {
+                    "200": {
+                        "description": "Returns the token claims",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenIntrospection"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is missing, malformed or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/auth/login": {
             "post": {
-                "description": "Authenticate user and return access and refresh tokens",
+                "description": "Authenticate user and return access and refresh tokens\nUsers with unverified emails can log in, but endpoints that require a verified email return EMAIL_NOT_VERIFIED\nWith JWT_MAX_SESSIONS set, logging in beyond that many active sessions revokes the refresh token of the oldest one",
                 "consumes": [
                     "application/json"
                 ],
@@ -72,6 +222,12 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -93,8 +249,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "403": {
-                        "description": "EMAIL_NOT_VERIFIED: The email address has not been verified",
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -231,6 +387,12 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -252,6 +414,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -263,7 +431,7 @@ const docTemplate = `{
         },
         "/v1/auth/register": {
             "post": {
-                "description": "Create a new user account",
+                "description": "Create a new user account\nWhen CREATE_DEFAULT_GROUP is enabled, a personal group named by DEFAULT_GROUP_NAME is created for the new user in the same transaction.",
                 "consumes": [
                     "application/json"
                 ],
@@ -294,6 +462,12 @@ const docTemplate = `{
                                 }
                             }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -310,7 +484,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body format, missing required fields, or JSON parsing error | BAD_NAME: Name contains invalid characters or is too short/long | BAD_EMAIL: Invalid email format | BAD_PASSWORD: Password does not meet requirements (e.g., too short, too weak)",
+                        "description": "BAD_REQUEST: Invalid request body format, missing required fields, or JSON parsing error | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_EMAIL: Invalid email format | BAD_PASSWORD: Password does not meet requirements (e.g., too short, too weak)",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -321,6 +495,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error - unexpected database or system error",
                         "schema": {
@@ -330,28 +510,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/auth/verify": {
-            "get": {
-                "description": "Verify a user's email address using a token sent to their email",
+        "/v1/auth/send-verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a new email verification token for the authenticated user and email it to them. Any previously issued token is invalidated. If an email change is pending, the link is sent to the new email instead.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Verify email address",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Email verification token",
-                        "name": "token",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
+                "summary": "Send verification email",
                 "responses": {
                     "200": {
-                        "description": "Email successfully verified",
+                        "description": "Verification email sent",
                         "schema": {
                             "type": "object",
                             "properties": {
@@ -362,13 +538,31 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Missing token | EMAIL_VERIFICATION_TOKEN_ERROR: Token is invalid, malformed, or not found",
+                        "description": "BAD_REQUEST: Email is already verified and no change is pending",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "403": {
-                        "description": "EMAIL_VERIFICATION_TOKEN_EXPIRED: Token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: User does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -378,74 +572,77 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
+                    },
+                    "503": {
+                        "description": "SERVICE_UNAVAILABLE: Email verification is not enabled on this server",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
                     }
                 }
             }
         },
-        "/v1/expenses/{id}": {
+        "/v1/auth/verify": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Get detailed information about an expense including splits",
+                "description": "Verify a user's email address using a token sent to their email",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "expenses"
+                    "auth"
                 ],
-                "summary": "Get expense details",
+                "summary": "Verify email address",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Expense ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Email verification token",
+                        "name": "token",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns expense details including all splits",
+                        "description": "Email successfully verified",
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                    "400": {
+                        "description": "BAD_REQUEST: Missing token | EMAIL_VERIFICATION_TOKEN_ERROR: Token is invalid, malformed, or not found",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: The authenticated user is not a member of the group this expense belongs to",
+                        "description": "EMAIL_VERIFICATION_TOKEN_EXPIRED: Token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist",
+                    "409": {
+                        "description": "EMAIL_EXISTS: The token confirms an email change, but another account now owns the new email",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error - unexpected database error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update an existing expense (requires being the expense creator). Immutable fields will be ignored if included in the request body.",
+            }
+        },
+        "/v1/auth/verify-email": {
+            "post": {
+                "description": "Consume an email verification token and mark the owning user's email as verified. A token sent for an email change also replaces the user's email with the pending one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -453,113 +650,134 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "expenses"
+                    "auth"
                 ],
-                "summary": "Update an expense",
+                "summary": "Verify email address",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Expense ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated expense details",
+                        "description": "Email verification token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "type": "object",
+                            "properties": {
+                                "token": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated expense with all fields",
+                        "description": "Email successfully verified",
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or missing required fields | INVALID_SPLIT: No splits provided or split totals do not match expense amount",
+                        "description": "BAD_REQUEST: Missing token | EMAIL_VERIFICATION_TOKEN_ERROR: Token is invalid, malformed, or not found",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                    "403": {
+                        "description": "EMAIL_VERIFICATION_TOKEN_EXPIRED: Token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
+                    "409": {
+                        "description": "EMAIL_EXISTS: The token confirms an email change, but another account now owns the new email",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist",
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error - unexpected database error",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/expenses/batch-get": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an expense (requires being the expense creator or group admin)",
+                "description": "Fetch up to 100 expenses by ID in one request, with their splits, instead of one GET /expenses/{id} per expense. Repeated IDs count toward the limit and are returned once.\nOnly expenses the user can read are returned, in request order; settlements are excluded as on GET /expenses/{id}. IDs that do not exist or belong to a group or private expense the user cannot see are listed under missing, without telling the two apart.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "expenses"
                 ],
-                "summary": "Delete an expense",
+                "summary": "Get several expenses",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Expense ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Expense IDs to fetch",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expense_ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns success message",
+                        "description": "Returns the accessible expenses and the missing IDs",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.ExpenseBatch"
                         }
                     },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                    "400": {
+                        "description": "BAD_REQUEST: Missing, malformed or too many expense IDs",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin",
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist",
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -571,14 +789,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/expenses/bulk-delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update specific fields of an expense (requires being the expense creator). Only provided fields are updated, others remain unchanged. Immutable fields are automatically protected.",
+                "description": "Move up to 100 expenses to the trash at once, for example to clean up after a bad import. Repeated IDs count toward the limit and are deleted once.\nThe caller needs delete access to every expense (expense creator or group admin). All expenses are deleted in one transaction: if any of them is not found, not deletable or fails to delete, nothing is deleted.\nThe response lists a result per expense. On failure the blocking expenses carry the error code and message, and the status is that of the first one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -588,34 +808,52 @@ const docTemplate = `{
                 "tags": [
                     "expenses"
                 ],
-                "summary": "Partially update an expense",
+                "summary": "Delete several expenses",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Expense ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Partial expense details (all fields optional except where validation requires)",
+                        "description": "Expense IDs to delete",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetailsPatch"
+                            "type": "object",
+                            "properties": {
+                                "expense_ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated expense with all fields",
+                        "description": "Returns a result per expense",
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "type": "object",
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkDeleteResult"
+                                    }
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or validation failed | INVALID_SPLIT: Split totals do not match expense amount",
+                        "description": "BAD_REQUEST: Missing, malformed or too many expense IDs",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -627,15 +865,63 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the creator or group admin of an expense",
                         "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkDeleteResult"
+                                    }
+                                }
+                            }
                         }
                     },
                     "404": {
-                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist",
+                        "description": "EXPENSE_NOT_FOUND: An expense does not exist",
                         "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkDeleteResult"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "EXPENSE_SETTLED: A settlement covers an expense (only with LOCK_SETTLED_EXPENSES)",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "code": {
+                                    "type": "string"
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "results": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.BulkDeleteResult"
+                                    }
+                                }
+                            }
                         }
                     },
                     "500": {
@@ -647,14 +933,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/": {
+        "/v1/expenses/preview-splits": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new group with the logged in user as the creator. A is_private group means all expenses are forced is_private.",
+                "description": "Compute the splits an expense would be stored with, without creating anything. Takes the same split fields as expense creation (amount, split_method, paid splits, participants, weights, shares, payer_included_in_split) and returns exactly the splits the server would store, including how the rounding remainder is distributed.\nThe splits are checked like on creation, so a preview that succeeds will also be accepted on submit (apart from group membership, which is checked against the group on creation).",
                 "consumes": [
                     "application/json"
                 ],
@@ -662,40 +948,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Create a new group",
+                "summary": "Preview computed splits",
                 "parameters": [
                     {
-                        "description": "Group details",
+                        "description": "Split input",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "description": {
-                                    "type": "string"
-                                },
-                                "name": {
-                                    "type": "string"
-                                },
-                                "private": {
-                                    "type": "boolean"
-                                }
-                            }
+                            "$ref": "#/definitions/models.SplitPreview"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Group successfully created",
+                    "200": {
+                        "description": "Returns the computed splits, paid first, then by user ID",
                         "schema": {
-                            "$ref": "#/definitions/models.GroupDetails"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExpenseSplit"
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body format or missing required fields | BAD_NAME: Name contains invalid characters or is too short/long",
+                        "description": "BAD_REQUEST: Invalid request body | INVALID_SPLIT: Splits could not be computed or do not add up to the amount",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -711,45 +995,54 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error - unexpected database error",
-                        "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
                     }
                 }
             }
         },
-        "/v1/groups/{id}": {
+        "/v1/expenses/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a group",
+                "description": "Get detailed information about an expense including splits",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Get group details",
+                "summary": "Get expense details",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Set to users to embed the name, email, guest flag and joined_at of the users involved",
+                        "name": "expand",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns group details including members and expenses",
+                        "description": "Returns expense details including all splits; users is only present with expand=users",
                         "schema": {
-                            "$ref": "#/definitions/models.GroupDetails"
+                            "$ref": "#/definitions/models.ExpenseWithUsers"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid expand value",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "401": {
@@ -759,13 +1052,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -784,7 +1077,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update group name and description (requires group admin permission). Immutable fields will be ignored if included in the request body.",
+                "description": "Update an existing expense (requires being the expense creator). Immutable fields will be ignored if included in the request body.",
                 "consumes": [
                     "application/json"
                 ],
@@ -792,36 +1085,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Update a group (full replacement)",
+                "summary": "Update an expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated group details",
+                        "description": "Updated expense details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Group"
+                            "$ref": "#/definitions/models.ExpenseDetails"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated group",
+                        "description": "Returns updated expense with all fields",
                         "schema": {
-                            "$ref": "#/definitions/models.GroupDetails"
+                            "$ref": "#/definitions/models.ExpenseDetails"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or missing required fields | BAD_NAME: Name contains invalid characters or is too short/long",
+                        "description": "BAD_REQUEST: Invalid request body or missing required fields | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: No splits provided or split totals do not match expense amount | BAD_REQUEST: transacted_at is more than a day in the future",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -833,13 +1132,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -858,18 +1163,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a group and all its associated data (requires group admin/owner permission)",
+                "description": "Move an expense to the trash (requires being the expense creator or group admin).\nDeleted expenses no longer count towards balances, can be restored, and are permanently removed after the configured retention period.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Delete a group",
+                "summary": "Delete an expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -892,13 +1197,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin/owner",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -917,7 +1228,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update specific fields of a group. Only provided fields are updated, others remain unchanged.",
+                "description": "Update specific fields of an expense (requires being the expense creator). Only provided fields are updated, others remain unchanged. Immutable fields are automatically protected.\nOmitting splits leaves the existing splits unchanged; sending an empty splits list is rejected, since an expense always needs at least one split.",
                 "consumes": [
                     "application/json"
                 ],
@@ -925,36 +1236,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Partially update a group",
+                "summary": "Partially update an expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Partial group details (name and/or description, all optional)",
+                        "description": "Partial expense details (all fields optional except where validation requires)",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.GroupPatch"
+                            "$ref": "#/definitions/models.ExpenseDetailsPatch"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated group with all fields",
+                        "description": "Returns updated expense with all fields",
                         "schema": {
-                            "$ref": "#/definitions/models.GroupDetails"
+                            "$ref": "#/definitions/models.ExpenseDetails"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or validation failed | BAD_NAME: Name contains invalid characters or is too short/long",
+                        "description": "BAD_REQUEST: Invalid request body or validation failed | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: Empty splits list or split totals do not match expense amount | BAD_REQUEST: transacted_at is more than a day in the future",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -966,13 +1283,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -986,25 +1309,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/{id}/expenses": {
+        "/v1/expenses/{id}/attachments": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all expenses of a group",
+                "description": "Get the receipt references attached to an expense, oldest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "expenses"
                 ],
-                "summary": "List group expenses",
+                "summary": "List expense attachments",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1012,11 +1335,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns list of all expenses in the group. If an expense is is_private, only the splits related to the authenticated user will be included in the response (creator or involved in splits)",
+                        "description": "Returns the expense's attachments",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Expense"
+                                "$ref": "#/definitions/models.ExpenseAttachment"
                             }
                         }
                     },
@@ -1027,13 +1350,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1052,7 +1375,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new expense with splits for a group. The logged in user will be set as the AddedBy user.",
+                "description": "Record a reference to a receipt stored outside the server, either as an absolute http(s) url or a storage object_key (exactly one is required).\nThe content type must be an image type or application/pdf, and size_bytes may not exceed MAX_ATTACHMENT_SIZE. The file itself is never uploaded to or stored by the server.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1062,34 +1385,54 @@ const docTemplate = `{
                 "tags": [
                     "expenses"
                 ],
-                "summary": "Create a new expense",
+                "summary": "Attach a receipt to an expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Expense details with splits",
+                        "description": "Attachment reference and metadata",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "type": "object",
+                            "properties": {
+                                "content_type": {
+                                    "type": "string"
+                                },
+                                "object_key": {
+                                    "type": "string"
+                                },
+                                "size_bytes": {
+                                    "type": "integer"
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Expense successfully created with splits",
+                        "description": "Returns the created attachment",
                         "schema": {
-                            "$ref": "#/definitions/models.ExpenseDetails"
+                            "$ref": "#/definitions/models.ExpenseAttachment"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body, missing required fields, or no splits provided | INVALID_SPLIT: Split totals do not match expense amount or split validation failed",
+                        "description": "BAD_REQUEST: Invalid request body | BAD_ATTACHMENT: Missing or ambiguous reference, unsupported content type, or size too large",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1101,13 +1444,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1121,60 +1464,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/{id}/members": {
-            "post": {
+        "/v1/expenses/{id}/attachments/{attachment_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Add one or more users to a group (requires group admin permission)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Remove a receipt reference from an expense (requires being the uploader or the expense creator). The referenced file itself is not deleted.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Add members to group",
+                "summary": "Remove an expense attachment",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User IDs to add",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "user_ids": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachment_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns success message and list of added member IDs",
+                        "description": "Attachment removed",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "properties": {
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body, missing required fields, or constraint violation",
+                        "description": "BAD_REQUEST: Invalid expense or attachment ID",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1186,13 +1520,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is neither the uploader nor the expense creator",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist | USER_NOT_FOUND: One or more specified users do not exist or no valid user IDs provided",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist | ATTACHMENT_NOT_FOUND: The expense has no such attachment",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1204,72 +1538,53 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/expenses/{id}/context": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove one or more users from a group (requires group admin permission)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get an expense together with the group's name and currency, the names of everyone with a split, and what the authenticated user paid and owes for it. Meant for an expense detail screen, in place of separate expense, group and user lookups.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Remove members from group",
+                "summary": "Get an expense with its context",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "User IDs to remove",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "properties": {
-                                "user_ids": {
-                                    "type": "array",
-                                    "items": {
-                                        "type": "string"
-                                    }
-                                }
-                            }
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns success message and list of removed member IDs",
+                        "description": "Returns the expense with its group and viewer context",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.ExpenseContext"
                         }
                     },
-                    "400": {
-                        "description": "BAD_REQUEST: Invalid request body, missing required fields, or attempting to remove self from group",
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin | USER_NOT_IN_GROUP: One or more specified users are not members of the group",
+                    "404": {
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group | GROUP_NOT_FOUND: The expense's group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1283,25 +1598,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/{id}/settle": {
-            "get": {
+        "/v1/expenses/{id}/finalize": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the payment balances between the authenticated user and all other members in a group. Positive amount means other user owes you, negative means you owe them.",
+                "description": "Clear the is_incomplete_amount and is_incomplete_split flags of a draft expense (requires being the expense creator or group admin).\nThe expense must have a positive amount, and its paid and owed splits must each sum to it within SPLIT_TOLERANCE; otherwise nothing changes. Finalizing an expense that is already complete returns it unchanged.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "expenses"
                 ],
-                "summary": "Get payment settlements for a group",
+                "summary": "Mark an incomplete expense as complete",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1309,12 +1624,15 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of non-zero settlement balances",
+                        "description": "Returns the completed expense",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Settlement"
-                            }
+                            "$ref": "#/definitions/models.ExpenseDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "INVALID_AMOUNT: The expense has no amount yet | INVALID_SPLIT: Split totals do not match the expense amount",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "401": {
@@ -1324,13 +1642,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1342,53 +1660,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/expenses/{id}/remaining": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Settle a payment with another user in a group by specifying the user_id and amount. Positive amount means you are paying them, negative means they are paying you. The settlement is stored as an expense with is_settlement=true.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get how much of an expense is still not assigned to participants (amount minus the sum of owed splits), along with the amount assigned to each participant. Useful for finishing incomplete expenses.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "expenses"
                 ],
-                "summary": "Settle a payment with another user in a group",
+                "summary": "Get unassigned amount of an expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Settle payment request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Settlement"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created settlement expense with splits",
-                        "schema": {
-                            "$ref": "#/definitions/models.Settlement"
-                        }
-                    },
-                    "400": {
-                        "description": "BAD_REQUEST: Cannot settle with yourself or missing group_id | INVALID_AMOUNT: Settlement amount cannot be zero",
+                    "200": {
+                        "description": "Returns the assigned and remaining amounts",
                         "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
+                            "$ref": "#/definitions/models.ExpenseRemaining"
                         }
                     },
                     "401": {
@@ -1398,19 +1700,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user or the other user is not a member of the specified group",
+                        "description": "EXPIRED_TOKEN: Access token has expired",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1418,25 +1720,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/{id}/settlements": {
-            "get": {
+        "/v1/expenses/{id}/restore": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all settlement transactions where the authenticated user is a participant (payer or receiver)",
+                "description": "Take an expense out of the trash so it counts towards balances again (requires being the expense creator or group admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "expenses"
                 ],
-                "summary": "Get settlement history for the current user in the group",
+                "summary": "Restore a deleted expense",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1444,12 +1746,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of settlement history entries",
+                        "description": "Returns the restored expense",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Settlement"
-                            }
+                            "$ref": "#/definitions/models.ExpenseDetails"
                         }
                     },
                     "401": {
@@ -1459,19 +1758,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense is not in the trash or the user is not a member of its group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1479,38 +1778,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/groups/{id}/spendings": {
-            "get": {
+        "/v1/expenses/{id}/splits/{user_id}": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all expenses where the authenticated user owes money in a specific group, with the user's owed amount per expense",
+                "description": "Set whether a user's split in an expense is on the paid side (is_paid=true) or the owed side, keeping its amount, without resending the whole expense.\nOnly the user the split belongs to, the expense creator or the group admin may change it. Moving a split always unbalances a complete expense, so it is only allowed on expenses flagged incomplete (is_incomplete_amount or is_incomplete_split); to record that a debtor paid their share of a complete expense, create a settlement instead.\nA user with splits on both sides cannot have one moved on its own. A split already on the requested side is returned unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "groups"
+                    "expenses"
                 ],
-                "summary": "Get user expenses in group",
+                "summary": "Move a split to the paid or owed side",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group ID",
+                        "description": "Expense ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID of the split",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Side to move the split to",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "is_paid": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of expenses with user-specific amounts",
+                        "description": "Returns the updated expense",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserExpense"
-                            }
+                            "$ref": "#/definitions/models.ExpenseDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or user ID, the user has splits on both sides, or the expense is complete | INVALID_SPLIT: Split totals would no longer match the expense amount",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "401": {
@@ -1520,13 +1852,19 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the split's user, the expense creator or the group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The specified expense does not exist | USER_NOT_FOUND: The user has no split in the expense",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES)",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1540,42 +1878,85 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/me": {
-            "get": {
+        "/v1/groups/": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the authenticated user's profile information",
+                "description": "Create a new group with the logged in user as the creator. A is_private group means all expenses are forced is_private.\nWith require_description=true, expenses in the group cannot be created or updated without a description.\nbase_currency is an ISO 4217 code; when omitted the user's default_currency is used, or the server's default currency if they have none.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
                 ],
-                "summary": "Get current user",
-                "responses": {
-                    "200": {
-                        "description": "Returns the authenticated user's profile information",
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                "summary": "Create a new group",
+                "parameters": [
+                    {
+                        "description": "Group details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
-                    },
+                            "type": "object",
+                            "properties": {
+                                "base_currency": {
+                                    "type": "string"
+                                },
+                                "description": {
+                                    "type": "string"
+                                },
+                                "name": {
+                                    "type": "string"
+                                },
+                                "private": {
+                                    "type": "boolean"
+                                },
+                                "require_description": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Group successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body format, missing required fields, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | EMAIL_NOT_VERIFIED: The user's email address has not been verified",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "USER_NOT_FOUND: The authenticated user no longer exists in the database",
+                    "409": {
+                        "description": "GROUP_LIMIT_REACHED: The user already owns the maximum number of groups",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1587,46 +1968,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/groups/invites/{token}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the authenticated user's editable details. This is a full replacement, so all required fields (name and email) must be provided. Immutable fields will be ignored if included in the request body.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the name, member count and creator name of the group an invite points to, without consuming a use of the invite",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
                 ],
-                "summary": "Update current user (full replacement)",
+                "summary": "Preview a group invite",
                 "parameters": [
                     {
-                        "description": "Updated user details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
+                        "type": "string",
+                        "description": "Invite token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated user",
-                        "schema": {
-                            "$ref": "#/definitions/models.User"
-                        }
-                    },
-                    "400": {
-                        "description": "BAD_REQUEST: Invalid request body or missing required fields | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect",
+                        "description": "Returns the invite's target group",
                         "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
+                            "$ref": "#/definitions/models.GroupPreview"
                         }
                     },
                     "401": {
@@ -1642,13 +2014,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "USER_NOT_FOUND: The authenticated user no longer exists",
-                        "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
-                    },
-                    "409": {
-                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                        "description": "INVITE_INVALID: The invite does not exist, has expired, or has no uses left",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1660,29 +2026,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/groups/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Anonymize the authenticated user's account. The user's name is replaced with \"Deleted User\" and their email and password are cleared. Group memberships and expense history are preserved.",
+                "description": "Get detailed information about a group",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
+                ],
+                "summary": "Get group details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Delete current user account",
                 "responses": {
                     "200": {
-                        "description": "Returns success message",
+                        "description": "Returns group details including members and expenses",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.GroupDetails"
                         }
                     },
                     "401": {
@@ -1692,19 +2066,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "USER_NOT_FOUND: The authenticated user no longer exists in the database",
-                        "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
-                    },
-                    "409": {
-                        "description": "USER_OWNS_GROUPS: User owns groups and must delete the groups or transfer ownership first",
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1717,13 +2085,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update specific fields of the authenticated user. Only provided fields are updated, others remain unchanged. Immutable fields (like user_id) will be ignored if included in the request body.",
+                "description": "Update group name, description, base currency and require_description setting (requires group admin permission). Immutable fields will be ignored if included in the request body. An omitted base_currency keeps the current one.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1731,29 +2099,42 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
                 ],
-                "summary": "Partially update current user",
+                "summary": "Update a group (full replacement)",
                 "parameters": [
                     {
-                        "description": "Partial user details (name and/or email, all optional)",
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated group details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserPatch"
+                            "$ref": "#/definitions/models.Group"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated user",
+                        "description": "Returns updated group",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.GroupDetails"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or validation failed | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect",
+                        "description": "BAD_REQUEST: Invalid request body, missing required fields, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1765,19 +2146,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "USER_NOT_FOUND: The authenticated user no longer exists",
-                        "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
-                    },
-                    "409": {
-                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1789,30 +2164,37 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/me/admin": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all groups that the authenticated user created (is owner of)",
+                "description": "Delete a group and all its associated data (requires group admin/owner permission)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
+                ],
+                "summary": "Delete a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List groups user owns",
                 "responses": {
                     "200": {
-                        "description": "Returns list of groups the user is owner of",
+                        "description": "Returns success message",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Group"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
@@ -1823,7 +2205,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin/owner",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1835,31 +2223,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/me/groups": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all groups the logged in user is a member of",
+                "description": "Update specific fields of a group. Only provided fields are updated, others remain unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "me"
+                    "groups"
+                ],
+                "summary": "Partially update a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Partial group details (name, description, base_currency and/or require_description, all optional)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupPatch"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List user's groups",
                 "responses": {
                     "200": {
-                        "description": "Returns list of groups the user is a member of",
+                        "description": "Returns updated group with all fields",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Group"
-                            }
+                            "$ref": "#/definitions/models.GroupDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body, validation failed, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "401": {
@@ -1869,7 +2285,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1883,57 +2305,139 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/settlements/{id}": {
+        "/v1/groups/{id}/activity": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a settlement including splits",
+                "description": "Get who changed what in the group, newest first: expenses and settlements created, updated, deleted or restored, and members added or removed.\nEach entry carries the actor, the action, the target and a compact summary of the change. Summaries of private expenses are left empty. Results are paginated; pass next_cursor back as cursor to get the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "groups"
                 ],
-                "summary": "Get settlement details",
+                "summary": "Get group activity log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Settlement ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns settlement details",
+                        "description": "Returns a page of activity entries",
                         "schema": {
-                            "$ref": "#/definitions/models.Settlement"
-                        }
-                    },
-                    "401": {
-                        "description": "INVALID_TOKEN: Access token is invalid",
+                            "type": "object",
+                            "properties": {
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Activity"
+                                    }
+                                },
+                                "next_cursor": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/budget": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the group's budget together with the spend of the current period (calendar week, month or year in UTC). Spend is the sum of live expenses transacted in the period, settlements excluded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get the group budget",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the budget and the current period's spend",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not a member of the settlement's group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement",
+                        "description": "BUDGET_NOT_FOUND: The group has no budget set",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1946,7 +2450,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Replace a settlement with new values (requires being the payer). The user_id and settlement direction (payer/receiver) are immutable and cannot be changed. Amount must preserve the original sign convention.",
+                "description": "Create or replace the group's spending budget (requires group admin permission). period defaults to month.\nExpenses that leave the current period over budget are still created; their response carries over_budget=true.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1954,36 +2458,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "groups"
                 ],
-                "summary": "Update a settlement",
+                "summary": "Set the group budget",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Settlement ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated settlement details",
+                        "description": "Budget amount in the group's base currency and period (week, month or year)",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Settlement"
+                            "type": "object",
+                            "properties": {
+                                "amount": {
+                                    "type": "number"
+                                },
+                                "period": {
+                                    "type": "string"
+                                }
+                            }
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated settlement",
+                        "description": "Returns the budget and the current period's spend",
                         "schema": {
-                            "$ref": "#/definitions/models.Settlement"
+                            "$ref": "#/definitions/models.BudgetStatus"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero",
+                        "description": "BAD_REQUEST: Invalid request body, amount is not positive, or period is unknown",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -1995,43 +2513,45 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement",
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/groups/{id}/categories": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a settlement (requires being the payer)",
+                "description": "Get the distinct categories used by the group's expenses, merged with the server's EXPENSE_CATEGORIES allow-list, sorted alphabetically. Categories are lowercase. Useful for populating a category filter.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "expenses"
                 ],
-                "summary": "Delete a settlement",
+                "summary": "List categories in a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Settlement ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2039,10 +2559,10 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns success message",
+                        "description": "Returns the categories",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
+                            "type": "array",
+                            "items": {
                                 "type": "string"
                             }
                         }
@@ -2054,69 +2574,90 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
-                        "schema": {
-                            "$ref": "#/definitions/apierrors.AppError"
-                        }
-                    },
-                    "404": {
-                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/groups/{id}/expenses": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update specific fields of a settlement (requires being the payer). Only provided fields are updated. The user_id and settlement direction (payer/receiver) are immutable and cannot be changed. If amount is provided, its sign must preserve the original direction.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get all expenses of a group, newest first. By default expenses are ordered by when they were added; sort=transacted_at orders them by when they took place instead, and amount or title (case-insensitive) are also available.\nSettlements are excluded unless is_settlement=true, which lists only settlements.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "settlements"
+                    "expenses"
                 ],
-                "summary": "Partially update a settlement",
+                "summary": "List group expenses",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Settlement ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Partial settlement details (all fields optional)",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SettlementPatch"
-                        }
+                        "enum": [
+                            "created_at",
+                            "transacted_at",
+                            "amount",
+                            "title"
+                        ],
+                        "type": "string",
+                        "description": "Order by created_at, transacted_at, amount or title (default created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort direction (default desc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only expenses added by this user ID",
+                        "name": "added_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List settlements instead of regular expenses (default false)",
+                        "name": "is_settlement",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns updated settlement",
+                        "description": "Returns list of all expenses in the group. If an expense is is_private, only the splits related to the authenticated user will be included in the response (creator or involved in splits)",
                         "schema": {
-                            "$ref": "#/definitions/models.Settlement"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Expense"
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero",
+                        "description": "BAD_REQUEST: Unknown sort key or order, invalid added_by or is_settlement value",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2128,34 +2669,32 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement",
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal server error - unexpected database error",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     }
                 }
-            }
-        },
-        "/v1/users/guest": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new guest user by email (requires authentication). Used to add non-registered users to groups. Name will be set to [name]@domain.tld",
+                "description": "Create a new expense with splits for a group. The logged in user will be set as the AddedBy user.\nWith split_method=equal, only the paid splits are sent and the owed splits are computed by dividing the amount equally among participants (remaining cents go to the first participants).\nWith split_method=percentage, only the paid splits are sent and the owed splits are computed from weights, a map of user ID to percentage of the amount that must sum to 100 (the rounding remainder goes to the largest share).\nWith split_method=shares, only the paid splits are sent and the owed splits are computed from shares, a map of user ID to a positive integer share (e.g. 2:1:1); leftover cents go to the largest fractional remainders, ties by user ID.\nBy default payers listed in participants owe a share like everyone else. With payer_included_in_split=false, payers are left out of the owed side: they pay but owe nothing. When omitted, the server's PAYER_INCLUDED_IN_SPLIT setting is used.\nWith autobalance=true, owed totals that are off by a few cents (up to 5x the split tolerance) are corrected by adjusting the largest owed split instead of being rejected. Paid splits are never adjusted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2163,34 +2702,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "expenses"
                 ],
-                "summary": "Register a guest user",
+                "summary": "Create a new expense",
                 "parameters": [
                     {
-                        "description": "Guest user email",
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Absorb small rounding differences into the largest owed split",
+                        "name": "autobalance",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Expense details with splits",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "email": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.ExpenseCreate"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Guest user successfully created",
+                        "description": "Expense successfully created with splits; over_budget is set when the group's current budget period is now exceeded",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.ExpenseCreated"
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid request body format or missing required fields | BAD_EMAIL: Invalid email format",
+                        "description": "BAD_REQUEST: Invalid request body or missing required fields | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: No splits provided, split totals do not match expense amount, split validation failed, or splits could not be computed | BAD_REQUEST: transacted_at is more than a day in the future",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2202,13 +2755,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | GUESTS_DISABLED: Guest user creation is disabled",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
-                    "409": {
-                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2220,41 +2773,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/users/search/email/{email}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Find a user by their email address",
+                "description": "Move every expense of the group to the trash in one step (requires group admin permission). Settlements are kept, and with LOCK_SETTLED_EXPENSES so are expenses a settlement covers.\nThe deleted expenses can be restored from the trash individually until the retention period ends. Requires confirm=true to guard against accidental calls.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "expenses"
                 ],
-                "summary": "Search user by email",
+                "summary": "Delete all expenses of a group",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User Email",
-                        "name": "email",
+                        "description": "Group ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Must be true",
+                        "name": "confirm",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns user profile information matching the email",
+                        "description": "Returns the number of expenses deleted",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "object",
+                            "properties": {
+                                "deleted": {
+                                    "type": "integer"
+                                },
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_EMAIL: Invalid email format",
+                        "description": "BAD_REQUEST: confirm=true is missing",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2266,13 +2832,13 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "USER_NOT_FOUND: No user found with the specified email",
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2286,39 +2852,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/users/{id}": {
+        "/v1/groups/{id}/expenses/search": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get user information by user ID (users must be related through a common group)",
+                "description": "Search the expenses of a group by text, amount and creation date. All filters are optional and combined; empty values mean no constraint.\nq matches the title or description (case-insensitive substring). Amount and date ranges are inclusive; dates are unix seconds.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "expenses"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Search group expenses",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Group ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text to search for in title and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum amount (inclusive)",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum amount (inclusive)",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created at or after (unix seconds)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created at or before (unix seconds)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns user profile information",
+                        "description": "Returns matching expenses, newest first",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Expense"
+                            }
                         }
                     },
                     "400": {
-                        "description": "BAD_REQUEST: Invalid user ID format",
+                        "description": "BAD_REQUEST: Invalid filter value or inverted amount/date range",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2330,13 +2929,296 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The users are not related through any common group",
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/expenses/seq/{seq}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an expense by its per-group expense number (the seq field), e.g. expense #14",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expenses"
+                ],
+                "summary": "Get expense by number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Expense number within the group",
+                        "name": "seq",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns expense details including all splits",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpenseDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid expense number",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
                     },
                     "404": {
-                        "description": "USER_NOT_FOUND: The specified user does not exist",
+                        "description": "EXPENSE_NOT_FOUND: The group has no expense with this number",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/expenses/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the expenses of a group that are in the trash, most recently deleted first. Private expenses are only listed for their creator and split participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "expenses"
+                ],
+                "summary": "List deleted group expenses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the deleted expenses, with deleted_at set",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Expense"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/invites": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a shareable invite link to the group (requires group admin permission). Anyone with the token can join the group by accepting it.\nexpires_at (unix seconds) and max_uses are optional; without them the invite never expires and has unlimited uses.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a group invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional expiry and use limit",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "expires_at": {
+                                    "type": "integer"
+                                },
+                                "max_uses": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Returns the created invite with its token",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupInvite"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body, expires_at not in the future, or max_uses below 1",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/invites/{invite_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an invite so its token can no longer be used to join the group (requires group admin permission). Members who already joined through it stay in the group.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Revoke a group invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invite ID",
+                        "name": "invite_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid invite ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "INVITE_INVALID: The group has no invite with this ID",
                         "schema": {
                             "$ref": "#/definitions/apierrors.AppError"
                         }
@@ -2349,23 +3231,3798 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "apierrors.AppError": {
+        },
+        "/v1/groups/{id}/leave": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the authenticated user from a group. The group admin cannot leave and must transfer ownership first.\nLeaving is refused while the user still owes or is owed money in the group, so settle up first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Leave a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the user's membership state after leaving",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "group_id": {
+                                    "type": "string"
+                                },
+                                "member": {
+                                    "type": "boolean"
+                                },
+                                "message": {
+                                    "type": "string"
+                                },
+                                "user_id": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: The user is the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "OUTSTANDING_BALANCE: The user has a non-zero balance in the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/me/owed-total": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's net balance in the group as one number: what they owe across all expenses and settlements minus what they paid. Positive means they owe the group, negative means the group owes them.\nComputed with a single sum over the user's own splits, so it is cheap enough to poll for a badge. Use GET /groups/{id}/settle for who to pay.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get what the user owes in a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the user's net owed total in the group",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "group_id": {
+                                    "type": "string"
+                                },
+                                "owed_total": {
+                                    "type": "number"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the members of a group, oldest first, without the group metadata. Lighter than the group details for member pickers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the group members",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.GroupUser"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add one or more users to a group (requires group admin permission).\nThe operation is idempotent: users that are already members are not an error and are reported under already_members.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Add members to group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs to add",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message, list of newly added member IDs (added_members) and IDs that were already members (already_members)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body, missing required fields, or constraint violation",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist | USER_NOT_FOUND: One or more specified users do not exist (all are listed in the message)",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "GROUP_FULL: Adding the users would exceed the maximum group size",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove one or more users from a group (requires group admin permission)\nMembers who still owe or are owed money in the group are not removed unless force=true, since their debts would be orphaned. Forced removals note the balance left behind in the activity log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Remove members from group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User IDs to remove",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user_ids": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove members even if they have an outstanding balance (default false)",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message and list of removed member IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body, missing required fields, invalid force value, or attempting to remove self from group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin | USER_NOT_IN_GROUP: One or more specified users are not members of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "OUTSTANDING_BALANCE: One or more members have a non-zero balance; the message lists their IDs",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/members/count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the number of members in a group without loading the member list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get group member count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the group ID (group_id), its member count (member_count) and the configured maximum (max_members, 0 for unlimited)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/pin": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pin the group to the top of the authenticated user's own group list (GET /me/groups and GET /me/spending), or unpin it. Only affects the order of the user's own lists, not other members or permissions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Pin or unpin a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the group is pinned",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pinned": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the new pinned state",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "group_id": {
+                                    "type": "string"
+                                },
+                                "pinned": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/public": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a reduced view of a group that does not require membership: its name, member count and creator name. The member list, expenses and balances are never included; use GET /groups/{id} as a member for those.\nMeant for showing a teaser of a group before joining it. Knowing the group ID is enough to see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get the public view of a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the public view of the group",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid group ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the payment balances between the authenticated user and all other members in a group. Positive amount means other user owes you, negative means you owe them.\nWith simplify=true (default), debts are minimized across the whole group, so you may be asked to pay someone you never shared an expense with. With simplify=false, the direct net balance with each member is returned instead. The sign convention is the same in both modes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get payment settlements for a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Minimize transactions across the group (default true)",
+                        "name": "simplify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of non-zero settlement balances",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Settlement"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid simplify value",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Settle a payment with another user in a group by specifying the user_id and amount. Positive amount means you are paying them, negative means they are paying you. The settlement is stored as an expense with is_settlement=true.\nSet client_ref (up to 64 characters, unique per group) to make retries safe: if a settlement with the same client_ref already exists in the group, it is returned with status 200 and nothing is created. The rest of the request is not compared against it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Settle a payment with another user in a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Settle payment request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Existing settlement with the same client_ref",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    "201": {
+                        "description": "Created settlement expense with splits",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Cannot settle with yourself, missing group_id, invalid client_ref, or client_ref belongs to a deleted settlement | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user or the other user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the optimized \"who pays whom\" plan for the whole group as a CSV file with a header row. The filename is derived from the group name.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Export the group's settlement plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format (only csv is supported)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with columns payer_id, payer_name, receiver_id, receiver_name, amount, currency (the group base currency)",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Unsupported export format",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle/ical": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download an iCalendar (.ics) file with one all-day event and reminder for each member the authenticated user owes in the group, using the same balances as GET /groups/{id}/settle.\nSettlements have no due date, so events are dated on the day of the download. Amounts are formatted in the user's locale, if set. Event UIDs are stable per group and member, so re-importing updates the existing events.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Download settlement reminders as a calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar file with one VEVENT per debt",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle/partial": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pay off part of the direct balance between the authenticated user and another member, e.g. half of what you owe them.\nThe amount is always positive; the settlement follows the direction of the debt, so whoever owes pays. It is capped at the current direct balance (as returned by GET /groups/{id}/settle?simplify=false), so a balance is never overpaid.\nReturns the requested amount, the amount actually settled, and the created settlement.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Settle part of a balance with another user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member to settle with, amount to settle and optional transaction time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "amount": {
+                                    "type": "number"
+                                },
+                                "transacted_at": {
+                                    "type": "integer"
+                                },
+                                "user_id": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created settlement with the requested and applied amounts",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "applied_amount": {
+                                    "type": "number"
+                                },
+                                "requested_amount": {
+                                    "type": "number"
+                                },
+                                "settlement": {
+                                    "$ref": "#/definitions/models.Settlement"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Cannot settle with yourself, or there is nothing to settle with the user | INVALID_AMOUNT: Amount is not positive | BAD_REQUEST: transacted_at is more than a day in the future",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user or the other user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle/recent": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the group members the authenticated user most recently shared an expense or settlement with, most recent first. Intended to suggest who to settle with; at most 5 members are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get recent counterparties in a group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Members ordered by most recent shared activity",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Counterparty"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settle/{user_id}/explain": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restate the authenticated user's balance with one group member as an explicit direction and a positive amount, so clients don't have to interpret the sign of GET /groups/{id}/settle.\nUses the same balances as GET /groups/{id}/settle, including the simplify parameter. A member with no balance is reported as settled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Explain the settlement with one member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID of the counterparty",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Minimize transactions across the group (default true)",
+                        "name": "simplify",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Direction and amount of the balance with the member",
+                        "schema": {
+                            "$ref": "#/definitions/models.SettlementExplanation"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid user ID or simplify value, or the user is the authenticated user",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: The counterparty is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/settlements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get settlement transactions where the authenticated user is a participant (payer or receiver), newest first. Results are paginated; pass next_cursor back as cursor to get the next page.\nWith detailed=true each entry is a models.SettlementDetails, which adds the expense ID and the raw splits the signed amount was derived from.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get settlement history for the current user in the group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Include the expense ID and splits of each settlement",
+                        "name": "detailed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return settlements with this counterparty",
+                        "name": "with_user",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns a page of settlement history entries",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.Settlement"
+                                    }
+                                },
+                                "next_cursor": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid limit, cursor, detailed or with_user value",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/spendings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all expenses where the authenticated user owes money in a specific group, with the user's owed amount per expense",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get user expenses in group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of expenses with user-specific amounts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserExpense"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get dashboard totals for the whole group: total spend (settlements excluded), the net balance of every member, the number of user pairs with an unsettled direct debt, and the biggest creditor and debtor.\nUnlike /settle it is not relative to the authenticated user. Balances within SPLIT_TOLERANCE count as settled, so a settled group returns zero balances and null top_creditor and top_debtor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Get a group-wide balance summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the group summary",
+                        "schema": {
+                            "$ref": "#/definitions/models.GroupSummary"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the webhooks of the group with their failure counts (requires group admin permission). Secrets are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "List group webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the group's webhooks, oldest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to events of the group (requires group admin permission). Events: expense.created, member.added, settlement.created. Private expenses are never delivered.\nEach event is POSTed as a models.WebhookPayload, asynchronously and with retries. The X-Qashare-Signature header holds \"sha256=\" and the hex HMAC-SHA256 of the body keyed with the secret, which is only returned here.\nA failed delivery is retried with exponential backoff (WEBHOOK_BASE_BACKOFF doubling up to WEBHOOK_MAX_BACKOFF) and dead-lettered after WEBHOOK_MAX_ATTEMPTS attempts; see the failures endpoint. A webhook that fails 20 deliveries in a row is disabled. Deliveries to loopback, private, link-local or unspecified addresses are refused, including hostnames that resolve to them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Register a group webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target URL and subscribed events",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "events": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Returns the created webhook with its signing secret",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body | BAD_WEBHOOK: URL is not an absolute http or https URL, points to an internal address, or an event is unknown",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "GROUP_NOT_FOUND: The specified group does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/webhooks/{webhook_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a webhook and drop its pending deliveries (requires group admin permission)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Delete a group webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid webhook ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "WEBHOOK_NOT_FOUND: The group has no webhook with this ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/groups/{id}/webhooks/{webhook_id}/failures": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the dead-lettered deliveries of a webhook, newest first (requires group admin permission).\nA delivery is dead-lettered once it has failed WEBHOOK_MAX_ATTEMPTS times, or when it was still pending as its webhook got disabled. Results are paginated; pass next_cursor back as cursor to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "List failed webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns a page of failed deliveries",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.WebhookFailure"
+                                    }
+                                },
+                                "next_cursor": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid webhook ID, limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "WEBHOOK_NOT_FOUND: The group has no webhook with this ID",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/invites/{token}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Join the group an invite points to, consuming one of its uses. Accepting an invite to a group the user is already a member of succeeds without consuming a use, with joined=false.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Join a group with an invite",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invite token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the joined group and whether the user was newly added",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "group_id": {
+                                    "type": "string"
+                                },
+                                "joined": {
+                                    "type": "boolean"
+                                },
+                                "message": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "INVITE_INVALID: The invite does not exist, has expired, or has no uses left",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "GROUP_FULL: The group has reached the maximum number of members",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's profile information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "Returns the authenticated user's profile information",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The authenticated user no longer exists in the database",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the authenticated user's editable details. This is a full replacement, so all required fields (name and email) must be provided. Immutable fields will be ignored if included in the request body.\ndefault_currency (ISO 4217) and locale (BCP 47) are optional; omitting them clears them.\nWith email verification enabled, a new email is not applied right away: it is returned as pending_email and a verification link is sent to it. The current email keeps working until the link is used. Submitting the current email cancels a pending change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Update current user (full replacement)",
+                "parameters": [
+                    {
+                        "description": "Updated user details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns updated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "202": {
+                        "description": "Returns updated user; the new email is pending verification",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "X-Verification-Warning": {
+                                "type": "string",
+                                "description": "Set if the verification email could not be sent; request a new one with POST /v1/auth/send-verification"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or missing required fields | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect | BAD_CURRENCY: default_currency is not a 3-letter ISO 4217 code | BAD_LOCALE: locale is not a BCP 47 language tag",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The authenticated user no longer exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Anonymize the authenticated user's account. The user's name is replaced with \"Deleted User\" and their email and password are cleared. Group memberships and expense history are preserved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Delete current user account",
+                "responses": {
+                    "200": {
+                        "description": "Returns success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The authenticated user no longer exists in the database",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "USER_OWNS_GROUPS: User owns groups and must delete the groups or transfer ownership first",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update specific fields of the authenticated user. Only provided fields are updated, others remain unchanged. Immutable fields (like user_id) will be ignored if included in the request body.\nWith email verification enabled, a new email is not applied right away: it is returned as pending_email and a verification link is sent to it. The current email keeps working until the link is used. Submitting the current email cancels a pending change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Partially update current user",
+                "parameters": [
+                    {
+                        "description": "Partial user details (name, email, default_currency and locale, all optional; an empty default_currency or locale clears it)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPatch"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns updated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "202": {
+                        "description": "Returns updated user; the new email is pending verification",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        },
+                        "headers": {
+                            "X-Verification-Warning": {
+                                "type": "string",
+                                "description": "Set if the verification email could not be sent; request a new one with POST /v1/auth/send-verification"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or validation failed | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect | BAD_CURRENCY: default_currency is not a 3-letter ISO 4217 code | BAD_LOCALE: locale is not a BCP 47 language tag",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The authenticated user no longer exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/admin": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all groups that the authenticated user created (is owner of)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "List groups user owns",
+                "responses": {
+                    "200": {
+                        "description": "Returns list of groups the user is owner of",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Group"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/balance-history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's net position across all groups over time, one point per interval.\nIntervals without activity are included with a zero change, so the series can be charted directly. A positive balance means the user is owed money overall.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get balance history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket size: day, week or month (default week)",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the balance history, oldest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.BalancePoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Unknown interval",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/contacts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the de-duplicated list of users that share at least one group with the authenticated user, ordered by name. Results are paginated; pass next_cursor back as cursor to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "List user's contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only include users whose name contains this text (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size (1-100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns a page of related users",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "items": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/models.User"
+                                    }
+                                },
+                                "next_cursor": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid limit or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/groups": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all groups the logged in user is a member of. Groups the user pinned (PUT /groups/{id}/pin) come first, then newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "List user's groups",
+                "responses": {
+                    "200": {
+                        "description": "Returns list of groups the user is a member of",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MemberGroup"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/notifications/count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the number of groups in which the authenticated user has an unsettled balance, split into groups where they owe and groups where they are owed.\nAlso counts pending drafts (incomplete expenses the user added or takes part in) and overdue splits (the user's owed splits in expenses older than OVERDUE_SPLIT_AGE).\nBalances within the split tolerance count as settled. Responses may be cached by the client for a short interval.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get notification badge counts",
+                "responses": {
+                    "200": {
+                        "description": "Returns the badge counts",
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationCounts"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the authenticated user's password. The current password must be supplied.\nBy default every other session is logged out; the session making the request stays valid. Set revoke_other_sessions to false to keep them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "current_password": {
+                                    "type": "string"
+                                },
+                                "new_password": {
+                                    "type": "string"
+                                },
+                                "revoke_other_sessions": {
+                                    "type": "boolean"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or the account has no password (guest) | BAD_PASSWORD: The new password does not meet the password policy",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid | BAD_CREDENTIALS: The current password is incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The authenticated user no longer exists in the database",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/spend-by-category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's share of expenses across all groups, grouped by category. Amounts are the user's owed splits, so only what they consumed is counted, not what they paid for others.\nSettlements are excluded. Totals are reported per group currency since groups may use different currencies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get spend by category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Created at or after (unix seconds)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Created at or before (unix seconds)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include expenses without a category under a null category (default true)",
+                        "name": "include_uncategorized",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the spend per category, largest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategorySpend"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid date or boolean value, or inverted date range",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/me/spending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get how much the authenticated user has paid and owes across all their groups, with a per-group breakdown listing pinned groups first, then by name.\ntotal_paid and total_owed include settlements, so they reflect the user's balance. net_spending excludes settlements and only counts the user's share of real expenses. Overall totals are given per group currency, sorted by currency, since amounts in different currencies are not added up.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get spending across groups",
+                "responses": {
+                    "200": {
+                        "description": "Returns the totals per currency and one entry per group",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSpending"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/settlements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get detailed information about a settlement including splits",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get settlement details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "users"
+                        ],
+                        "type": "string",
+                        "description": "Set to users to embed the name, email, guest flag and joined_at of both parties",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns settlement details; users is only present with expand=users",
+                        "schema": {
+                            "$ref": "#/definitions/models.SettlementWithUsers"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid expand value",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist, the expense is not a settlement, or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a settlement with new values (requires being the payer). The user_id and settlement direction (payer/receiver) are immutable and cannot be changed. Amount must preserve the original sign convention.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Update a settlement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated settlement details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns updated settlement",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a settlement (requires being the payer)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Delete a settlement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns success message",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update specific fields of a settlement (requires being the payer). Only provided fields are updated. The user_id and settlement direction (payer/receiver) are immutable and cannot be changed. If amount is provided, its sign must preserve the original direction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Partially update a settlement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Partial settlement details (all fields optional)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SettlementPatch"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns updated settlement",
+                        "schema": {
+                            "$ref": "#/definitions/models.Settlement"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/guest": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new guest user by email (requires authentication). Used to add non-registered users to groups. Name will be set to [name]@domain.tld",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Register a guest user",
+                "parameters": [
+                    {
+                        "description": "Guest user email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "email": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)",
+                        "name": "strict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Guest user successfully created",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid request body format or missing required fields | BAD_EMAIL: Invalid email format",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | GUESTS_DISABLED: Guest user creation is disabled",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "409": {
+                        "description": "EMAIL_EXISTS: An account with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/search/email/{email}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find a user by their email address",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Search user by email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns user profile information matching the email",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_EMAIL: Invalid email format",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: No user found with the specified email",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get user information by user ID (users must be related through a common group)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns user profile information",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "BAD_REQUEST: Invalid user ID format",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "401": {
+                        "description": "INVALID_TOKEN: Access token is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The users are not related through any common group",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "USER_NOT_FOUND: The specified user does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - unexpected database error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.AppError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apierrors.AppError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "e.g., \"BAD_NAME\", \"INVALID_EMAIL\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/apierrors.Code"
+                        }
+                    ]
+                },
+                "message": {
+                    "description": "Human-readable message",
+                    "type": "string"
+                }
+            }
+        },
+        "apierrors.Code": {
+            "type": "string",
+            "enum": [
+                "BAD_REQUEST",
+                "BAD_NAME",
+                "NAME_TOO_SHORT",
+                "NAME_TOO_LONG",
+                "EMAIL_EXISTS",
+                "BAD_EMAIL",
+                "BAD_DESCRIPTION",
+                "BAD_TITLE",
+                "BAD_CURRENCY",
+                "BAD_LOCALE",
+                "BAD_PASSWORD",
+                "BAD_CREDENTIALS",
+                "INVALID_TOKEN",
+                "EXPIRED_TOKEN",
+                "INVALID_REFRESH_TOKEN",
+                "EXPIRED_REFRESH_TOKEN",
+                "EMAIL_NOT_VERIFIED",
+                "EMAIL_VERIFICATION_TOKEN_EXPIRED",
+                "EMAIL_VERIFICATION_TOKEN_ERROR",
+                "INVALID_API_KEY",
+                "USER_NOT_FOUND",
+                "GROUP_NOT_FOUND",
+                "USER_NOT_IN_GROUP",
+                "USERS_NOT_RELATED",
+                "NO_PERMISSIONS",
+                "GUESTS_DISABLED",
+                "USER_OWNS_GROUPS",
+                "GROUP_LIMIT_REACHED",
+                "GROUP_FULL",
+                "INVITE_INVALID",
+                "OUTSTANDING_BALANCE",
+                "BAD_WEBHOOK",
+                "WEBHOOK_NOT_FOUND",
+                "BUDGET_NOT_FOUND",
+                "EXPENSE_NOT_FOUND",
+                "INVALID_AMOUNT",
+                "BAD_PAYMENT_METHOD",
+                "BAD_CATEGORY",
+                "BAD_ATTACHMENT",
+                "ATTACHMENT_NOT_FOUND",
+                "INVALID_SPLIT",
+                "EXPENSE_SETTLED",
+                "TOO_MANY_REQUESTS",
+                "REQUEST_TIMEOUT",
+                "CLIENT_CLOSED_REQUEST",
+                "INTERNAL_ERROR",
+                "SERVICE_UNAVAILABLE"
+            ],
+            "x-enum-varnames": [
+                "CodeBadRequest",
+                "CodeInvalidName",
+                "CodeNameTooShort",
+                "CodeNameTooLong",
+                "CodeEmailAlreadyExists",
+                "CodeInvalidEmail",
+                "CodeInvalidDescription",
+                "CodeInvalidTitle",
+                "CodeInvalidCurrency",
+                "CodeInvalidLocale",
+                "CodeInvalidPassword",
+                "CodeBadCredentials",
+                "CodeInvalidAccessToken",
+                "CodeExpiredAccessToken",
+                "CodeInvalidRefreshToken",
+                "CodeExpiredRefreshToken",
+                "CodeEmailNotVerified",
+                "CodeEmailVerificationTokenExpired",
+                "CodeEmailVerificationTokenError",
+                "CodeInvalidAPIKey",
+                "CodeUserNotFound",
+                "CodeGroupNotFound",
+                "CodeUserNotInGroup",
+                "CodeUsersNotRelated",
+                "CodeNoPermissions",
+                "CodeGuestsDisabled",
+                "CodeUserOwnsGroups",
+                "CodeGroupLimit",
+                "CodeGroupFull",
+                "CodeInviteInvalid",
+                "CodeOutstandingBalance",
+                "CodeInvalidWebhook",
+                "CodeWebhookNotFound",
+                "CodeBudgetNotFound",
+                "CodeExpenseNotFound",
+                "CodeInvalidAmount",
+                "CodeInvalidPaymentMethod",
+                "CodeInvalidCategory",
+                "CodeInvalidAttachment",
+                "CodeAttachmentNotFound",
+                "CodeInvalidSplit",
+                "CodeExpenseSettled",
+                "CodeTooManyRequests",
+                "CodeRequestTimeout",
+                "CodeClientClosedRequest",
+                "CodeInternalServer",
+                "CodeServiceUnavailable"
+            ]
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "created, updated, deleted, restored, added or removed",
+                    "type": "string"
+                },
+                "activity_id": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "description": "nil if the acting user was deleted",
+                    "type": "string"
+                },
+                "actor_name": {
+                    "description": "DeletedUserName if the acting user was deleted",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "summary": {
+                    "description": "Compact description of what changed",
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "description": "expense, settlement or member",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BalancePoint": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Running net position; positive means the user is owed money",
+                    "type": "number"
+                },
+                "change": {
+                    "description": "Net change during the interval",
+                    "type": "number"
+                },
+                "period_start": {
+                    "description": "Start of the interval",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "description": "The group's base currency",
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "over_budget": {
+                    "type": "boolean"
+                },
+                "percentage": {
+                    "description": "Spent as a percentage of the budget, may exceed 100",
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "week",
+                        "month",
+                        "year"
+                    ]
+                },
+                "period_end": {
+                    "description": "Unix seconds, exclusive",
+                    "type": "integer"
+                },
+                "period_start": {
+                    "description": "Unix seconds, inclusive",
+                    "type": "integer"
+                },
+                "remaining": {
+                    "description": "Negative once the budget is exceeded",
+                    "type": "number"
+                },
+                "spent": {
+                    "description": "Live expenses transacted in the period, settlements excluded",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "updated_by": {
+                    "description": "nil if the user was deleted",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BulkDeleteResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Error code if this expense blocked the batch",
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Reason this expense blocked the batch",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategorySpend": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Sum of the user's owed splits",
+                    "type": "number"
+                },
+                "category": {
+                    "description": "nil for uncategorized expenses",
+                    "type": "string"
+                },
+                "count": {
+                    "description": "Number of expenses",
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "Base currency of the groups the expenses belong to",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Counterparty": {
+            "type": "object",
+            "properties": {
+                "last_activity_at": {
+                    "description": "Creation time of the most recent shared expense or settlement",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CurrencySpending": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "net_spending": {
+                    "description": "Sum of the user's owed splits, settlements excluded",
+                    "type": "number"
+                },
+                "total_owed": {
+                    "description": "Sum of the user's owed splits, settlements included",
+                    "type": "number"
+                },
+                "total_paid": {
+                    "description": "Sum of the user's paid splits, settlements included",
+                    "type": "number"
+                }
+            }
+        },
+        "models.Expense": {
+            "type": "object",
+            "properties": {
+                "added_by": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "is_incomplete_amount": {
+                    "type": "boolean"
+                },
+                "is_incomplete_split": {
+                    "type": "boolean"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "is_settlement": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpenseAttachment": {
+            "type": "object",
+            "properties": {
+                "attachment_id": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "object_key": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "description": "nil if the uploader was deleted",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExpenseBatch": {
+            "type": "object",
+            "properties": {
+                "expenses": {
+                    "description": "Accessible expenses, in request order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseDetails"
+                    }
+                },
+                "missing": {
+                    "description": "Requested IDs that do not exist or are not accessible",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ExpenseContext": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "The group's base currency",
+                    "type": "string"
+                },
+                "expense": {
+                    "$ref": "#/definitions/models.ExpenseDetails"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "owed": {
+                    "description": "What the authenticated user owes for the expense",
+                    "type": "number"
+                },
+                "paid": {
+                    "description": "What the authenticated user paid towards the expense",
+                    "type": "number"
+                },
+                "participants": {
+                    "description": "Users with a split, payers first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Participant"
+                    }
+                }
+            }
+        },
+        "models.ExpenseCreate": {
+            "type": "object",
+            "properties": {
+                "added_by": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "is_incomplete_amount": {
+                    "type": "boolean"
+                },
+                "is_incomplete_split": {
+                    "type": "boolean"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "is_settlement": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "participants": {
+                    "description": "Users that owe a share (equal split method only)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payer_included_in_split": {
+                    "description": "Whether payers also owe a share (computed split methods only). Defaults to the server setting.",
+                    "type": "boolean"
+                },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
+                "shares": {
+                    "description": "Positive integer share each user owes, e.g. 2:1:1 (shares split method only)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "split_method": {
+                    "type": "string",
+                    "enum": [
+                        "exact",
+                        "equal",
+                        "percentage",
+                        "shares"
+                    ]
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                },
+                "weights": {
+                    "description": "Percentage of the amount each user owes, summing to 100 (percentage split method only)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "models.ExpenseCreated": {
+            "type": "object",
+            "properties": {
+                "added_by": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "is_incomplete_amount": {
+                    "type": "boolean"
+                },
+                "is_incomplete_split": {
+                    "type": "boolean"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "is_settlement": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "over_budget": {
+                    "description": "The expense counts towards the group's current budget period, which is now over budget",
+                    "type": "boolean"
+                },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpenseDetails": {
+            "type": "object",
+            "properties": {
+                "added_by": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
+                "description": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "expense_id": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "is_incomplete_amount": {
+                    "type": "boolean"
+                },
+                "is_incomplete_split": {
+                    "type": "boolean"
+                },
+                "is_private": {
+                    "type": "boolean"
+                },
+                "is_settlement": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "longitude": {
+                    "description": "pointer because nullable in db",
+                    "type": "number"
+                },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpenseDetailsPatch": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "description": "\"\" clears the category",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_incomplete_amount": {
+                    "type": "boolean"
+                },
+                "is_incomplete_split": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "description": "\"\" clears the payment method",
+                    "type": "string"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpenseRemaining": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "e.g., \"BAD_NAME\", \"INVALID_EMAIL\"",
+                "amount": {
+                    "type": "number"
+                },
+                "assigned": {
+                    "description": "Sum of owed splits",
+                    "type": "number"
+                },
+                "expense_id": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "Human-readable message",
+                "owed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ParticipantAmount"
+                    }
+                },
+                "remaining": {
+                    "description": "Amount minus assigned; negative if over-assigned",
+                    "type": "number"
+                }
+            }
+        },
+        "models.ExpenseSplit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "is_paid": {
+                    "description": "\"paid\" or \"owes\"",
+                    "type": "boolean"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.Expense": {
+        "models.ExpenseWithUsers": {
             "type": "object",
             "properties": {
                 "added_by": {
@@ -2374,9 +7031,17 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "integer"
                 },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
                 "description": {
                     "description": "pointer because nullable in db",
                     "type": "string"
@@ -2407,123 +7072,311 @@ const docTemplate = `{
                     "description": "pointer because nullable in db",
                     "type": "number"
                 },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
+                "splits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
                 "title": {
                     "type": "string"
                 },
                 "transacted_at": {
                     "type": "integer"
+                },
+                "users": {
+                    "description": "Everyone with a split, plus the user who added the expense",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupUser"
+                    }
                 }
             }
         },
-        "models.ExpenseDetails": {
+        "models.Group": {
             "type": "object",
             "properties": {
-                "added_by": {
+                "base_currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
                     "type": "string"
                 },
-                "amount": {
-                    "type": "number"
+                "description": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "private": {
+                    "type": "boolean"
+                },
+                "require_description": {
+                    "description": "Expenses must have a description",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.GroupDetails": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string",
+                    "example": "USD"
                 },
                 "created_at": {
                     "type": "integer"
                 },
-                "description": {
-                    "description": "pointer because nullable in db",
+                "created_by": {
                     "type": "string"
                 },
-                "expense_id": {
+                "description": {
                     "type": "string"
                 },
                 "group_id": {
                     "type": "string"
                 },
-                "is_incomplete_amount": {
-                    "type": "boolean"
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupUser"
+                    }
                 },
-                "is_incomplete_split": {
+                "name": {
+                    "type": "string"
+                },
+                "private": {
                     "type": "boolean"
                 },
-                "is_private": {
+                "require_description": {
+                    "description": "Expenses must have a description",
                     "type": "boolean"
+                }
+            }
+        },
+        "models.GroupInvite": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
                 },
-                "is_settlement": {
+                "created_by": {
+                    "description": "nil if the creator was deleted",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "invite_id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GroupPatch": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "require_description": {
                     "type": "boolean"
+                }
+            }
+        },
+        "models.GroupPreview": {
+            "type": "object",
+            "properties": {
+                "creator_name": {
+                    "description": "DeletedUserName if the creator no longer exists",
+                    "type": "string"
                 },
-                "latitude": {
-                    "description": "pointer because nullable in db",
+                "group_id": {
+                    "type": "string"
+                },
+                "member_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupSpending": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "net_spending": {
+                    "description": "Sum of the user's owed splits, settlements excluded",
                     "type": "number"
                 },
-                "longitude": {
-                    "description": "pointer because nullable in db",
+                "total_owed": {
+                    "description": "Sum of the user's owed splits, settlements included",
                     "type": "number"
                 },
-                "splits": {
+                "total_paid": {
+                    "description": "Sum of the user's paid splits, settlements included",
+                    "type": "number"
+                }
+            }
+        },
+        "models.GroupSummary": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "description": "Every member, highest balance first; settled members have 0",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.ExpenseSplit"
+                        "$ref": "#/definitions/models.MemberBalance"
                     }
                 },
-                "title": {
+                "currency": {
+                    "description": "The group's base currency",
                     "type": "string"
                 },
-                "transacted_at": {
+                "top_creditor": {
+                    "description": "nil if nobody is owed money",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MemberBalance"
+                        }
+                    ]
+                },
+                "top_debtor": {
+                    "description": "nil if nobody owes money",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MemberBalance"
+                        }
+                    ]
+                },
+                "total_spend": {
+                    "description": "Sum of live expense amounts, settlements excluded",
+                    "type": "number"
+                },
+                "unsettled_pairs": {
+                    "description": "Pairs of users with a direct debt beyond the split tolerance",
                     "type": "integer"
                 }
             }
         },
-        "models.ExpenseDetailsPatch": {
+        "models.GroupUser": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number"
+                "deleted": {
+                    "type": "boolean"
                 },
-                "description": {
+                "email": {
                     "type": "string"
                 },
-                "is_incomplete_amount": {
+                "guest": {
                     "type": "boolean"
                 },
-                "is_incomplete_split": {
-                    "type": "boolean"
+                "joined_at": {
+                    "type": "integer"
                 },
-                "latitude": {
-                    "type": "number"
+                "name": {
+                    "type": "string"
                 },
-                "longitude": {
-                    "type": "number"
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GuestClaimStatus": {
+            "type": "object",
+            "properties": {
+                "claim_pending": {
+                    "description": "The account is registered but awaiting email verification",
+                    "type": "boolean"
                 },
-                "splits": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.ExpenseSplit"
-                    }
+                "guest": {
+                    "description": "An unclaimed guest account exists for the email",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "app": {
+                    "type": "string",
+                    "example": "Qashare"
                 },
-                "title": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "Qashare"
                 },
-                "transacted_at": {
-                    "type": "integer"
+                "status": {
+                    "type": "string",
+                    "example": "ok"
                 }
             }
         },
-        "models.ExpenseSplit": {
+        "models.MemberBalance": {
             "type": "object",
             "properties": {
-                "amount": {
+                "balance": {
                     "type": "number"
                 },
-                "is_paid": {
-                    "description": "\"paid\" or \"owes\"",
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.Group": {
+        "models.MemberGroup": {
             "type": "object",
             "properties": {
+                "base_currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string",
+                    "example": "USD"
+                },
                 "created_at": {
                     "type": "integer"
                 },
@@ -2539,81 +7392,77 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "pinned": {
+                    "description": "The member pinned the group to the top of their list",
+                    "type": "boolean"
+                },
                 "private": {
                     "type": "boolean"
+                },
+                "require_description": {
+                    "description": "Expenses must have a description",
+                    "type": "boolean"
                 }
             }
         },
-        "models.GroupDetails": {
+        "models.NotificationCounts": {
             "type": "object",
             "properties": {
-                "created_at": {
+                "overdue_splits": {
+                    "description": "The user's owed splits in expenses older than the overdue threshold",
                     "type": "integer"
                 },
-                "created_by": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "group_id": {
-                    "type": "string"
+                "owed": {
+                    "description": "Groups in which the user is owed money",
+                    "type": "integer"
                 },
-                "members": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.GroupUser"
-                    }
+                "owing": {
+                    "description": "Groups in which the user owes money",
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "pending_drafts": {
+                    "description": "Incomplete expenses the user added or takes part in",
+                    "type": "integer"
                 },
-                "private": {
-                    "type": "boolean"
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.GroupPatch": {
+        "models.Participant": {
             "type": "object",
             "properties": {
-                "description": {
+                "name": {
                     "type": "string"
                 },
-                "name": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.GroupUser": {
+        "models.ParticipantAmount": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "guest": {
-                    "type": "boolean"
-                },
-                "joined_at": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
                 },
                 "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.HealthCheck": {
+        "models.ReadinessCheck": {
             "type": "object",
             "properties": {
-                "app": {
+                "database": {
                     "type": "string",
-                    "example": "Qashare"
+                    "example": "ok"
                 },
-                "name": {
-                    "type": "string",
-                    "example": "Qashare"
+                "pending_migrations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "status": {
                     "type": "string",
@@ -2627,6 +7476,10 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "client_ref": {
+                    "description": "Client-chosen reference that makes creating the settlement idempotent",
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "integer"
                 },
@@ -2642,6 +7495,57 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SettlementDirection": {
+            "type": "string",
+            "enum": [
+                "you_owe",
+                "owes_you",
+                "settled"
+            ],
+            "x-enum-comments": {
+                "SettlementOwesYou": "The counterparty owes the authenticated user",
+                "SettlementSettled": "Nothing is owed either way",
+                "SettlementYouOwe": "The authenticated user owes the counterparty"
+            },
+            "x-enum-descriptions": [
+                "The authenticated user owes the counterparty",
+                "The counterparty owes the authenticated user",
+                "Nothing is owed either way"
+            ],
+            "x-enum-varnames": [
+                "SettlementYouOwe",
+                "SettlementOwesYou",
+                "SettlementSettled"
+            ]
+        },
+        "models.SettlementExplanation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Always positive, zero when settled",
+                    "type": "number"
+                },
+                "counterparty": {
+                    "$ref": "#/definitions/models.GroupUser"
+                },
+                "currency": {
+                    "description": "The group's base currency",
+                    "type": "string"
+                },
+                "direction": {
+                    "enum": [
+                        "you_owe",
+                        "owes_you",
+                        "settled"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SettlementDirection"
+                        }
+                    ]
+                }
+            }
+        },
         "models.SettlementPatch": {
             "type": "object",
             "properties": {
@@ -2653,6 +7557,113 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SettlementWithUsers": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "client_ref": {
+                    "description": "Client-chosen reference that makes creating the settlement idempotent",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "transacted_at": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "The other user involved in the settlement",
+                    "type": "string"
+                },
+                "users": {
+                    "description": "The payer and the receiver",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupUser"
+                    }
+                }
+            }
+        },
+        "models.SplitPreview": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payer_included_in_split": {
+                    "type": "boolean"
+                },
+                "shares": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "split_method": {
+                    "type": "string",
+                    "enum": [
+                        "exact",
+                        "equal",
+                        "percentage",
+                        "shares"
+                    ]
+                },
+                "splits": {
+                    "description": "Paid splits, or every split for the exact method",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpenseSplit"
+                    }
+                },
+                "weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "models.TokenIntrospection": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Unix seconds",
+                    "type": "integer"
+                },
+                "expires_in": {
+                    "description": "Seconds until the token expires, to schedule a refresh",
+                    "type": "integer",
+                    "example": 840
+                },
+                "issued_at": {
+                    "description": "Unix seconds",
+                    "type": "integer"
+                },
+                "session_id": {
+                    "description": "Refresh token the access token was issued from",
+                    "type": "string"
+                },
+                "token_id": {
+                    "description": "The access token's own jti",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "models.TokenResponse": {
             "type": "object",
             "properties": {
@@ -2676,15 +7687,29 @@ const docTemplate = `{
                 "created_at": {
                     "type": "integer"
                 },
+                "default_currency": {
+                    "description": "ISO 4217 code new groups start in, nil for the server default",
+                    "type": "string",
+                    "example": "EUR"
+                },
                 "email": {
                     "type": "string"
                 },
                 "guest": {
                     "type": "boolean"
                 },
+                "locale": {
+                    "description": "BCP 47 tag amounts are formatted with, nil for no localization",
+                    "type": "string",
+                    "example": "de-DE"
+                },
                 "name": {
                     "type": "string"
                 },
+                "pending_email": {
+                    "description": "New email waiting for verification, only shown to the user themselves",
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
@@ -2699,9 +7724,17 @@ const docTemplate = `{
                 "amount": {
                     "type": "number"
                 },
+                "category": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "integer"
                 },
+                "deleted_at": {
+                    "description": "set while the expense is in the trash",
+                    "type": "integer"
+                },
                 "description": {
                     "description": "pointer because nullable in db",
                     "type": "string"
@@ -2732,6 +7765,14 @@ const docTemplate = `{
                     "description": "pointer because nullable in db",
                     "type": "number"
                 },
+                "payment_method": {
+                    "description": "pointer because nullable in db",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "per-group expense number",
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string"
                 },
@@ -2747,13 +7788,111 @@ const docTemplate = `{
         "models.UserPatch": {
             "type": "object",
             "properties": {
+                "default_currency": {
+                    "description": "\"\" clears the default currency",
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "locale": {
+                    "description": "\"\" clears the locale",
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 }
             }
+        },
+        "models.UserSpending": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupSpending"
+                    }
+                },
+                "totals": {
+                    "description": "One entry per group currency, since amounts in different currencies are not added up",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CurrencySpending"
+                    }
+                }
+            }
+        },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "description": "nil if the creator was deleted",
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "description": "Set once the webhook was disabled after repeated failed deliveries",
+                    "type": "integer"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "expense.created",
+                            "member.added",
+                            "settlement.created"
+                        ]
+                    }
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookFailure": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "delivery_id": {
+                    "description": "Matches the X-Qashare-Delivery header of the attempts",
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "integer"
+                },
+                "failure_id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
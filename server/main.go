@@ -41,7 +41,14 @@ func main() {
 	// Initialize pretty logger early so config-loading logs are formatted
 	utils.InitDefaultLogger()
 
-	if err := run(); err != nil {
+	// "qashare apikey ..." manages API keys instead of starting the server
+	var err error
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		err = runAPIKeyCommand(os.Args[2:])
+	} else {
+		err = run()
+	}
+	if err != nil {
 		slog.Error("Fatal error", "error", err)
 		os.Exit(1)
 	}
@@ -64,6 +71,17 @@ func run() error {
 	}
 	defer db.Close(pool)
 
+	// Reporting queries go to the read replica when one is configured
+	readPool := pool
+	replica, err := db.ConnectReplica(cfg.Database)
+	if err != nil {
+		return err
+	}
+	if replica != nil {
+		readPool = replica
+		defer db.Close(replica)
+	}
+
 	// Swagger url setup
 	u, err := url.Parse(cfg.API.PublicURL)
 	if err != nil {
@@ -74,14 +92,32 @@ func run() error {
 	docs.SwaggerInfo.BasePath = cfg.API.BasePath
 	docs.SwaggerInfo.Schemes = []string{u.Scheme}
 
-	// Start periodic cleanup of expired refresh tokens
+	// Start periodic cleanup of expired refresh tokens and purging of old deleted expenses
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
-	cleanupDone := db.StartTokenCleanup(cleanupCtx, pool, cfg.JWT.TokenCleanupFreq)
+	cleanupDone := db.StartTokenCleanup(cleanupCtx, pool, cfg.JWT.TokenCleanupFreq, cfg.App.ExpenseRetention)
 	defer func() {
 		cleanupCancel()
 		<-cleanupDone
 	}()
 
+	// Start the opt-in periodic audit of expense split totals
+	if cfg.App.SplitAudit {
+		auditCtx, auditCancel := context.WithCancel(context.Background())
+		auditDone := db.StartSplitAudit(auditCtx, pool, cfg.App.SplitAuditFreq, cfg.App.SplitTolerance)
+		defer func() {
+			auditCancel()
+			<-auditDone
+		}()
+	}
+
+	// Start sending queued webhook deliveries
+	webhookCtx, webhookCancel := context.WithCancel(context.Background())
+	webhookDone := db.StartWebhookDelivery(webhookCtx, pool, cfg.App.WebhookDeliveryFreq, cfg.App.WebhookRetry)
+	defer func() {
+		webhookCancel()
+		<-webhookDone
+	}()
+
 	// Setup HTTP router
 	router := gin.Default()
 	if err := router.SetTrustedProxies(cfg.API.TrustedProxies); err != nil {
@@ -89,7 +125,7 @@ func run() error {
 		return err
 	}
 	utils.InitEmail(cfg.Email, cfg.API)
-	routes.RegisterRoutes(cfg.API.BasePath, router, pool, cfg.JWT, cfg.App)
+	routes.RegisterRoutes(cfg.API.BasePath, router, pool, readPool, cfg.JWT, cfg.App, cfg.Database)
 
 	// Start server with graceful shutdown
 	return startServer(router, cfg.API)
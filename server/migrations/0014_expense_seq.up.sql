@@ -0,0 +1,20 @@
+-- Per-group expense counter, incremented under a row lock when an expense is created
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS expense_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+-- Number existing expenses in creation order within each group
+UPDATE expenses e
+SET seq = numbered.seq
+FROM (
+    SELECT expense_id, ROW_NUMBER() OVER (PARTITION BY group_id ORDER BY created_at, expense_id) AS seq
+    FROM expenses
+) numbered
+WHERE e.expense_id = numbered.expense_id;
+
+UPDATE groups g
+SET expense_seq = COALESCE((SELECT MAX(seq) FROM expenses WHERE group_id = g.group_id), 0);
+
+ALTER TABLE expenses ALTER COLUMN seq SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expenses_group_seq ON expenses (group_id, seq);
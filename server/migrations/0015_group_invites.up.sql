@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS group_invites (
+    invite_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES groups (group_id) ON DELETE CASCADE,
+    token UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    created_by UUID REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_group_invites_group_id ON group_invites (group_id);
@@ -0,0 +1,2 @@
+-- ISO 4217 currency that balances and settlements of a group are expressed in
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS base_currency TEXT NOT NULL DEFAULT 'USD';
@@ -0,0 +1,2 @@
+-- Optional note of how an expense was paid (cash, card, ...), validated by the server
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS payment_method TEXT;
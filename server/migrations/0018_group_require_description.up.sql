@@ -0,0 +1,2 @@
+-- When set, every expense in the group must have a description
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS require_description BOOLEAN NOT NULL DEFAULT FALSE;
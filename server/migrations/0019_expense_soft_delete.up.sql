@@ -0,0 +1,4 @@
+-- Deleted expenses are kept in a trash until purged; NULL means the expense is live
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_deleted_at ON expenses (deleted_at) WHERE deleted_at IS NOT NULL;
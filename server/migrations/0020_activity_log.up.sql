@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS activity_log (
+    activity_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    group_id UUID NOT NULL REFERENCES groups (group_id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users (user_id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID,
+    summary TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_activity_log_group_created ON activity_log (group_id, created_at DESC, activity_id DESC);
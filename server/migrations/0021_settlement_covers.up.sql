@@ -0,0 +1,8 @@
+-- Expenses whose debts between two users were cleared by a settlement
+CREATE TABLE IF NOT EXISTS settlement_covers (
+    settlement_id UUID NOT NULL REFERENCES expenses (expense_id) ON DELETE CASCADE,
+    expense_id UUID NOT NULL REFERENCES expenses (expense_id) ON DELETE CASCADE,
+    PRIMARY KEY (settlement_id, expense_id)
+);
+
+CREATE INDEX idx_settlement_covers_expense ON settlement_covers (expense_id);
@@ -0,0 +1,4 @@
+-- Optional free-text category used for reports and filtering; NULL means uncategorized
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS category TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_group_category ON expenses (group_id, category) WHERE category IS NOT NULL;
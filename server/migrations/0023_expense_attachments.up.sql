@@ -0,0 +1,15 @@
+-- Receipt references attached to expenses. Files live in external storage; only the reference
+-- and metadata are kept here. Rows are removed with the expense when it is purged from the trash.
+CREATE TABLE IF NOT EXISTS expense_attachments (
+    attachment_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    expense_id UUID NOT NULL REFERENCES expenses (expense_id) ON DELETE CASCADE,
+    url TEXT,
+    object_key TEXT,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    uploaded_by UUID REFERENCES users (user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (url IS NOT NULL OR object_key IS NOT NULL)
+);
+
+CREATE INDEX idx_expense_attachments_expense ON expense_attachments (expense_id, created_at);
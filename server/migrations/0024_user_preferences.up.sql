@@ -0,0 +1,4 @@
+-- Per-user defaults: the currency new groups start in and the locale amounts are formatted with.
+-- NULL means the server defaults apply.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS default_currency TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale TEXT;
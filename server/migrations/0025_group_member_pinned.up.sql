@@ -0,0 +1,2 @@
+-- Groups a member pinned are listed first in their own group list; it does not affect anyone else
+ALTER TABLE group_members ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT false;
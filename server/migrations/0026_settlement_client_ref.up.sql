@@ -0,0 +1,7 @@
+-- Client-supplied reference that makes retried settlement creates idempotent within a group.
+-- The index also covers trashed settlements, so a reference is never silently reused while its settlement can be restored.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS client_ref TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_expenses_settlement_client_ref
+    ON expenses (group_id, client_ref)
+    WHERE is_settlement AND client_ref IS NOT NULL;
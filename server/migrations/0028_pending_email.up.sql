@@ -0,0 +1,5 @@
+-- An email change waits here until the new address is verified; the current email keeps working until then.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email TEXT;
+
+-- The address a verification token confirms. NULL means the user's current email.
+ALTER TABLE email_verification_tokens ADD COLUMN IF NOT EXISTS email TEXT;
@@ -0,0 +1,12 @@
+-- Optional spending budget of a group per calendar period (week, month or year, in UTC).
+-- Spend is the sum of live expense amounts transacted in the current period, settlements excluded.
+CREATE TABLE IF NOT EXISTS group_budgets (
+    group_id UUID PRIMARY KEY REFERENCES groups (group_id) ON DELETE CASCADE,
+    amount NUMERIC(19,4) NOT NULL CHECK (amount > 0),
+    period TEXT NOT NULL DEFAULT 'month' CHECK (period IN ('week', 'month', 'year')),
+    updated_by UUID REFERENCES users (user_id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Period spend is summed by group and transaction time
+CREATE INDEX IF NOT EXISTS idx_expenses_group_transacted ON expenses (group_id, transacted_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,14 @@
+-- Dead letters: deliveries that used all their attempts, or were pending when their webhook was disabled.
+-- They are kept for inspection until the webhook is deleted.
+CREATE TABLE IF NOT EXISTS webhook_failures (
+    failure_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks (webhook_id) ON DELETE CASCADE,
+    delivery_id UUID NOT NULL,
+    event TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL,
+    last_error TEXT,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_failures_webhook ON webhook_failures (webhook_id, failed_at DESC, failure_id DESC);
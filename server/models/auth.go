@@ -1,6 +1,9 @@
 package models
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
 
 // TokenType represents the type of JWT token (access or refresh).
 type TokenType string
@@ -23,3 +26,54 @@ type TokenResponse struct {
 	RefreshToken string `json:"refresh_token,omitempty" example:"eyJhbGciOiJIUzI1NiIs..."`
 	TokenType    string `json:"token_type" example:"Bearer"`
 }
+
+// TokenIntrospection Not a part of DB schema, the decoded claims of the access token used for a request
+type TokenIntrospection struct {
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"session_id"`               // Refresh token the access token was issued from
+	TokenID   string    `json:"token_id"`                 // The access token's own jti
+	IssuedAt  int64     `json:"issued_at"`                // Unix seconds
+	ExpiresAt int64     `json:"expires_at"`               // Unix seconds
+	ExpiresIn int64     `json:"expires_in" example:"840"` // Seconds until the token expires, to schedule a refresh
+}
+
+// GuestClaimStatus tells a client whether an email can claim a guest account.
+// Guests are claimed by registering with their email; the claim stays pending until the email is verified.
+type GuestClaimStatus struct {
+	Guest        bool `json:"guest"`         // An unclaimed guest account exists for the email
+	ClaimPending bool `json:"claim_pending"` // The account is registered but awaiting email verification
+}
+
+// APIKeyScope is a capability granted to an API key.
+type APIKeyScope string
+
+const (
+	ScopeExpensesRead  APIKeyScope = "expenses:read"
+	ScopeExpensesWrite APIKeyScope = "expenses:write"
+	ScopeWebhooks      APIKeyScope = "webhooks"
+	ScopeMetrics       APIKeyScope = "metrics"
+)
+
+// APIKeyScopes lists every scope an API key can be granted.
+var APIKeyScopes = []APIKeyScope{ScopeExpensesRead, ScopeExpensesWrite, ScopeWebhooks, ScopeMetrics}
+
+// APIKey is a machine credential used for service-to-service calls.
+// Only a hash of the key is stored; the plaintext key is shown once on creation.
+type APIKey struct {
+	KeyID      uuid.UUID     `json:"key_id" db:"key_id"`
+	Name       string        `json:"name" db:"name"`
+	Scopes     []APIKeyScope `json:"scopes" db:"scopes"`
+	CreatedAt  int64         `json:"created_at" db:"created_at"`
+	ExpiresAt  *int64        `json:"expires_at" db:"expires_at"`
+	LastUsedAt *int64        `json:"last_used_at" db:"last_used_at"`
+}
+
+// HasScope reports whether the key grants the given scope.
+func (k APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -8,8 +8,10 @@ package models
 // UserPatch represents a partial update to a User.
 // Only non-nil fields will be applied to the target.
 type UserPatch struct {
-	Name  *string `json:"name,omitempty"`
-	Email *string `json:"email,omitempty"`
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty"`
+	Currency *string `json:"default_currency,omitempty"` // "" clears the default currency
+	Locale   *string `json:"locale,omitempty"`           // "" clears the locale
 }
 
 // GroupPatch represents a partial update to a Group.
@@ -17,6 +19,8 @@ type UserPatch struct {
 type GroupPatch struct {
 	Name        *string `json:"name,omitempty"`
 	Description *string `json:"description,omitempty"`
+	Currency    *string `json:"base_currency,omitempty"`
+	RequireDesc *bool   `json:"require_description,omitempty"`
 }
 
 // ExpensePatch represents a partial update to an Expense.
@@ -30,6 +34,8 @@ type ExpensePatch struct {
 	IsIncompleteSplit  *bool    `json:"is_incomplete_split,omitempty"`
 	Latitude           *float64 `json:"latitude,omitempty"`
 	Longitude          *float64 `json:"longitude,omitempty"`
+	PaymentMethod      *string  `json:"payment_method,omitempty"` // "" clears the payment method
+	Category           *string  `json:"category,omitempty"`       // "" clears the category
 }
 
 // ExpenseDetailsPatch represents a partial update to an ExpenseDetails.
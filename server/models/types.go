@@ -1,7 +1,11 @@
 // Package models defines the core data structures for the shared expenses application.
 package models
 
-import "github.com/google/uuid"
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
 
 // User represents a user in the system
 type User struct {
@@ -9,9 +13,12 @@ type User struct {
 	Name          string    `json:"name" db:"user_name"`
 	Email         string    `json:"email" db:"email"`
 	EmailVerified bool      `json:"-" db:"email_verified"`
+	PendingEmail  *string   `json:"pending_email,omitempty" db:"pending_email" immutable:"true"` // New email waiting for verification, only shown to the user themselves
 	Guest         bool      `json:"guest" db:"is_guest" immutable:"true"`
 	PasswordHash  *string   `json:"-" db:"password_hash" immutable:"true"` // excluded from JSON responses
 	CreatedAt     int64     `json:"created_at" db:"created_at" immutable:"true"`
+	Currency      *string   `json:"default_currency" db:"default_currency" example:"EUR"` // ISO 4217 code new groups start in, nil for the server default
+	Locale        *string   `json:"locale" db:"locale" example:"de-DE"`                   // BCP 47 tag amounts are formatted with, nil for no localization
 }
 
 // Group represents a group
@@ -22,6 +29,14 @@ type Group struct {
 	CreatedBy   uuid.UUID `json:"created_by" db:"created_by" immutable:"true"`
 	CreatedAt   int64     `json:"created_at" db:"created_at" immutable:"true"`
 	Private     bool      `json:"private" db:"is_private" immutable:"true"`
+	Currency    string    `json:"base_currency" db:"base_currency" example:"USD"` // ISO 4217 code
+	RequireDesc bool      `json:"require_description" db:"require_description"`   // Expenses must have a description
+}
+
+// MemberGroup Not a part of DB schema, a group as listed for one of its members
+type MemberGroup struct {
+	Group
+	Pinned bool `json:"pinned"` // The member pinned the group to the top of their list
 }
 
 // GroupDetails represents detailed information about a group including its members
@@ -37,19 +52,49 @@ type GroupMember struct {
 	JoinedAt int64     `json:"joined_at" db:"joined_at"`
 }
 
-// GroupUser Not a part of DB schema, used for responses
+// DeletedUserName is the display name used in place of a deleted user.
+// Anonymized accounts are named "Deleted User (xxxx)" with the last 4 characters of their ID,
+// and users that no longer exist at all are shown as just DeletedUserName.
+const DeletedUserName = "Deleted User"
+
+// GroupUser Not a part of DB schema, used for responses.
+// For a deleted user, Name is a "Deleted User (xxxx)" placeholder, Email is empty and Deleted is true.
 type GroupUser struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Name     string    `json:"name"`
 	Email    string    `json:"email"`
 	Guest    bool      `json:"guest"`
+	Deleted  bool      `json:"deleted"`
 	JoinedAt int64     `json:"joined_at"`
 }
 
+// GroupInvite represents a shareable invite that lets users join a group.
+// ExpiresAt and MaxUses are nil when the invite does not expire or has unlimited uses.
+type GroupInvite struct {
+	InviteID  uuid.UUID  `json:"invite_id" db:"invite_id"`
+	GroupID   uuid.UUID  `json:"group_id" db:"group_id"`
+	Token     uuid.UUID  `json:"token" db:"token"`
+	CreatedBy *uuid.UUID `json:"created_by" db:"created_by"` // nil if the creator was deleted
+	CreatedAt int64      `json:"created_at" db:"created_at"`
+	ExpiresAt *int64     `json:"expires_at" db:"expires_at"`
+	MaxUses   *int       `json:"max_uses" db:"max_uses"`
+	Uses      int        `json:"uses" db:"uses"`
+}
+
+// GroupPreview Not a part of DB schema, the reduced view of a group shown to non-members,
+// e.g. as the target of an invite. It never includes the member list or expenses.
+type GroupPreview struct {
+	GroupID     uuid.UUID `json:"group_id"`
+	Name        string    `json:"name"`
+	MemberCount int       `json:"member_count"`
+	CreatorName string    `json:"creator_name"` // DeletedUserName if the creator no longer exists
+}
+
 // Expense represents an expense in a group(ID)
 type Expense struct {
 	ExpenseID          uuid.UUID `json:"expense_id" db:"expense_id" immutable:"true"`
 	GroupID            uuid.UUID `json:"group_id" db:"group_id" immutable:"true"`
+	Seq                int64     `json:"seq" db:"seq" immutable:"true"` // per-group expense number
 	AddedBy            uuid.UUID `json:"added_by" db:"added_by" immutable:"true"`
 	Title              string    `json:"title" db:"title"`
 	Description        *string   `json:"description" db:"description"` // pointer because nullable in db
@@ -60,8 +105,12 @@ type Expense struct {
 	IsIncompleteSplit  bool      `json:"is_incomplete_split" db:"is_incomplete_split"`
 	IsSettlement       bool      `json:"is_settlement" db:"is_settlement" immutable:"true"`
 	IsPrivate          bool      `json:"is_private" db:"is_private" immutable:"true"`
-	Latitude           *float64  `json:"latitude" db:"latitude"`   // pointer because nullable in db
-	Longitude          *float64  `json:"longitude" db:"longitude"` // pointer because nullable in db
+	Latitude           *float64  `json:"latitude" db:"latitude"`                                // pointer because nullable in db
+	Longitude          *float64  `json:"longitude" db:"longitude"`                              // pointer because nullable in db
+	PaymentMethod      *string   `json:"payment_method" db:"payment_method"`                    // pointer because nullable in db
+	Category           *string   `json:"category" db:"category"`                                // pointer because nullable in db
+	DeletedAt          *int64    `json:"deleted_at,omitempty" db:"deleted_at" immutable:"true"` // set while the expense is in the trash
+	ClientRef          *string   `json:"-" db:"client_ref" immutable:"true"`                    // settlements only, surfaced through Settlement
 }
 
 // ExpenseDetails represents detailed information about an expense including its splits
@@ -70,6 +119,263 @@ type ExpenseDetails struct {
 	Splits  []ExpenseSplit `json:"splits"`
 }
 
+// ExpenseWithUsers Not a part of DB schema, an expense with the users of its splits embedded (?expand=users)
+type ExpenseWithUsers struct {
+	ExpenseDetails             // Struct embedding to include all ExpenseDetails fields
+	Users          []GroupUser `json:"users"` // Everyone with a split, plus the user who added the expense
+}
+
+// ExpenseCreate is the request body for creating an expense.
+// When SplitMethod is set to something other than "exact", the server computes the
+// owed splits from Participants or Weights and the client only supplies the paid splits.
+type ExpenseCreate struct {
+	ExpenseDetails
+	SplitMethod  string                `json:"split_method,omitempty" enums:"exact,equal,percentage,shares"`
+	Participants []uuid.UUID           `json:"participants,omitempty"` // Users that owe a share (equal split method only)
+	Weights      map[uuid.UUID]float64 `json:"weights,omitempty"`      // Percentage of the amount each user owes, summing to 100 (percentage split method only)
+	Shares       map[uuid.UUID]int     `json:"shares,omitempty"`       // Positive integer share each user owes, e.g. 2:1:1 (shares split method only)
+	// Whether payers also owe a share (computed split methods only). Defaults to the server setting.
+	PayerIncludedInSplit *bool `json:"payer_included_in_split,omitempty"`
+}
+
+// SplitPreview Not a part of DB schema, the request body for previewing computed splits.
+// The fields mean the same as in ExpenseCreate.
+type SplitPreview struct {
+	Amount               float64               `json:"amount"`
+	SplitMethod          string                `json:"split_method,omitempty" enums:"exact,equal,percentage,shares"`
+	Splits               []ExpenseSplit        `json:"splits"` // Paid splits, or every split for the exact method
+	Participants         []uuid.UUID           `json:"participants,omitempty"`
+	Weights              map[uuid.UUID]float64 `json:"weights,omitempty"`
+	Shares               map[uuid.UUID]int     `json:"shares,omitempty"`
+	PayerIncludedInSplit *bool                 `json:"payer_included_in_split,omitempty"`
+}
+
+// ExpenseAttachment represents a receipt attached to an expense.
+// Only the reference and metadata are stored: the file itself lives at URL or under ObjectKey in external storage.
+type ExpenseAttachment struct {
+	AttachmentID uuid.UUID  `json:"attachment_id" db:"attachment_id"`
+	ExpenseID    uuid.UUID  `json:"expense_id" db:"expense_id"`
+	URL          *string    `json:"url" db:"url"`               // pointer because nullable in db
+	ObjectKey    *string    `json:"object_key" db:"object_key"` // pointer because nullable in db
+	ContentType  string     `json:"content_type" db:"content_type" example:"image/jpeg"`
+	SizeBytes    int64      `json:"size_bytes" db:"size_bytes"`
+	UploadedBy   *uuid.UUID `json:"uploaded_by" db:"uploaded_by"` // nil if the uploader was deleted
+	CreatedAt    int64      `json:"created_at" db:"created_at"`
+}
+
+// ExpenseRemaining Not a part of DB schema, shows how much of an expense is still unassigned
+type ExpenseRemaining struct {
+	ExpenseID uuid.UUID           `json:"expense_id"`
+	Amount    float64             `json:"amount"`
+	Assigned  float64             `json:"assigned"`  // Sum of owed splits
+	Remaining float64             `json:"remaining"` // Amount minus assigned; negative if over-assigned
+	Owed      []ParticipantAmount `json:"owed"`
+}
+
+// Counterparty Not a part of DB schema, a group member the user has recently shared expenses or settlements with
+type Counterparty struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Name           string    `json:"name"`
+	LastActivityAt int64     `json:"last_activity_at"` // Creation time of the most recent shared expense or settlement
+}
+
+// SplitAnomaly Not a part of DB schema, an expense whose split totals do not match its amount
+type SplitAnomaly struct {
+	ExpenseID uuid.UUID `json:"expense_id"`
+	GroupID   uuid.UUID `json:"group_id"`
+	Amount    float64   `json:"amount"`
+	PaidTotal float64   `json:"paid_total"`
+	OwedTotal float64   `json:"owed_total"`
+}
+
+// Activity represents an entry in a group's activity log
+type Activity struct {
+	ActivityID uuid.UUID  `json:"activity_id" db:"activity_id"`
+	GroupID    uuid.UUID  `json:"group_id" db:"group_id"`
+	ActorID    *uuid.UUID `json:"actor_id" db:"actor_id"`       // nil if the acting user was deleted
+	ActorName  string     `json:"actor_name"`                   // DeletedUserName if the acting user was deleted
+	Action     string     `json:"action" db:"action"`           // created, updated, deleted, restored, added or removed
+	TargetType string     `json:"target_type" db:"target_type"` // expense, settlement or member
+	TargetID   *uuid.UUID `json:"target_id" db:"target_id"`
+	Summary    string     `json:"summary" db:"summary"` // Compact description of what changed
+	CreatedAt  int64      `json:"created_at" db:"created_at"`
+}
+
+// Webhook is an integration subscribed to events of a group.
+// Deliveries are signed with Secret, which is only returned when the webhook is created.
+type Webhook struct {
+	WebhookID           uuid.UUID  `json:"webhook_id" db:"webhook_id"`
+	GroupID             uuid.UUID  `json:"group_id" db:"group_id"`
+	URL                 string     `json:"url" db:"url"`
+	Events              []string   `json:"events" db:"events" enums:"expense.created,member.added,settlement.created"`
+	Secret              string     `json:"secret,omitempty" db:"secret"`
+	CreatedBy           *uuid.UUID `json:"created_by" db:"created_by"` // nil if the creator was deleted
+	CreatedAt           int64      `json:"created_at" db:"created_at"`
+	ConsecutiveFailures int        `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *int64     `json:"disabled_at" db:"disabled_at"` // Set once the webhook was disabled after repeated failed deliveries
+}
+
+// Webhook events
+const (
+	WebhookExpenseCreated    = "expense.created"
+	WebhookMemberAdded       = "member.added"
+	WebhookSettlementCreated = "settlement.created"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []string{WebhookExpenseCreated, WebhookMemberAdded, WebhookSettlementCreated}
+
+// WebhookPayload Not a part of DB schema, the JSON body POSTed to a webhook.
+// Data is the created expense or settlement (ExpenseDetails), or a WebhookMembers for member.added.
+type WebhookPayload struct {
+	Event     string    `json:"event"`
+	GroupID   uuid.UUID `json:"group_id"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	CreatedAt int64     `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// WebhookFailure is a dead-lettered delivery: it used all its attempts, or was still pending
+// when its webhook was disabled. Payload is the body that would have been POSTed.
+type WebhookFailure struct {
+	FailureID  uuid.UUID       `json:"failure_id" db:"failure_id"`
+	WebhookID  uuid.UUID       `json:"webhook_id" db:"webhook_id"`
+	DeliveryID uuid.UUID       `json:"delivery_id" db:"delivery_id"` // Matches the X-Qashare-Delivery header of the attempts
+	Event      string          `json:"event" db:"event"`
+	Payload    json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Attempts   int             `json:"attempts" db:"attempts"`
+	LastError  *string         `json:"last_error" db:"last_error"`
+	FailedAt   int64           `json:"failed_at" db:"failed_at"`
+}
+
+// WebhookMembers Not a part of DB schema, the data of a member.added webhook event
+type WebhookMembers struct {
+	UserIDs []uuid.UUID `json:"user_ids"`
+}
+
+// Budget periods. A period is a calendar week (starting Monday), month or year in UTC.
+const (
+	BudgetPeriodWeek  = "week"
+	BudgetPeriodMonth = "month"
+	BudgetPeriodYear  = "year"
+)
+
+// GroupBudget is the optional spending budget of a group per period
+type GroupBudget struct {
+	GroupID   uuid.UUID  `json:"group_id" db:"group_id" immutable:"true"`
+	Amount    float64    `json:"amount" db:"amount"`
+	Period    string     `json:"period" db:"period" enums:"week,month,year"`
+	UpdatedBy *uuid.UUID `json:"updated_by" db:"updated_by" immutable:"true"` // nil if the user was deleted
+	UpdatedAt int64      `json:"updated_at" db:"updated_at" immutable:"true"`
+}
+
+// BudgetStatus Not a part of DB schema, a group's spend in the current budget period against its budget
+type BudgetStatus struct {
+	GroupBudget
+	Currency    string  `json:"currency"`     // The group's base currency
+	PeriodStart int64   `json:"period_start"` // Unix seconds, inclusive
+	PeriodEnd   int64   `json:"period_end"`   // Unix seconds, exclusive
+	Spent       float64 `json:"spent"`        // Live expenses transacted in the period, settlements excluded
+	Remaining   float64 `json:"remaining"`    // Negative once the budget is exceeded
+	Percentage  float64 `json:"percentage"`   // Spent as a percentage of the budget, may exceed 100
+	OverBudget  bool    `json:"over_budget"`
+}
+
+// ExpenseCreated Not a part of DB schema, the response to creating an expense
+type ExpenseCreated struct {
+	ExpenseDetails
+	OverBudget bool `json:"over_budget,omitempty"` // The expense counts towards the group's current budget period, which is now over budget
+}
+
+// BalancePoint Not a part of DB schema, the user's net position at the end of one interval
+type BalancePoint struct {
+	PeriodStart int64   `json:"period_start"` // Start of the interval
+	Change      float64 `json:"change"`       // Net change during the interval
+	Balance     float64 `json:"balance"`      // Running net position; positive means the user is owed money
+}
+
+// NotificationCounts Not a part of DB schema, the numbers behind the client's notification badge
+type NotificationCounts struct {
+	Owing         int `json:"owing"`          // Groups in which the user owes money
+	Owed          int `json:"owed"`           // Groups in which the user is owed money
+	PendingDrafts int `json:"pending_drafts"` // Incomplete expenses the user added or takes part in
+	OverdueSplits int `json:"overdue_splits"` // The user's owed splits in expenses older than the overdue threshold
+	Total         int `json:"total"`
+}
+
+// SpendingTotals Not a part of DB schema, what a user paid and owes.
+// TotalPaid and TotalOwed include settlements, so they reflect the user's balance;
+// NetSpending excludes them, so it only counts the user's share of real expenses.
+type SpendingTotals struct {
+	TotalPaid   float64 `json:"total_paid"`   // Sum of the user's paid splits, settlements included
+	TotalOwed   float64 `json:"total_owed"`   // Sum of the user's owed splits, settlements included
+	NetSpending float64 `json:"net_spending"` // Sum of the user's owed splits, settlements excluded
+}
+
+// GroupSpending Not a part of DB schema, a user's spending totals within one group
+type GroupSpending struct {
+	GroupID   uuid.UUID `json:"group_id"`
+	GroupName string    `json:"group_name"`
+	Currency  string    `json:"currency"`
+	SpendingTotals
+}
+
+// CurrencySpending Not a part of DB schema, a user's spending totals across the groups in one currency
+type CurrencySpending struct {
+	Currency string `json:"currency"`
+	SpendingTotals
+}
+
+// UserSpending Not a part of DB schema, a user's spending totals across all their groups
+type UserSpending struct {
+	Totals []CurrencySpending `json:"totals"` // One entry per group currency, since amounts in different currencies are not added up
+	Groups []GroupSpending    `json:"groups"`
+}
+
+// CategorySpend Not a part of DB schema, the user's share of expenses in one category and currency
+type CategorySpend struct {
+	Category *string `json:"category"` // nil for uncategorized expenses
+	Currency string  `json:"currency"` // Base currency of the groups the expenses belong to
+	Amount   float64 `json:"amount"`   // Sum of the user's owed splits
+	Count    int     `json:"count"`    // Number of expenses
+}
+
+// BulkDeleteResult Not a part of DB schema, the outcome for one expense of a bulk delete
+type BulkDeleteResult struct {
+	ExpenseID uuid.UUID `json:"expense_id"`
+	Deleted   bool      `json:"deleted"`
+	Code      string    `json:"code,omitempty"`    // Error code if this expense blocked the batch
+	Message   string    `json:"message,omitempty"` // Reason this expense blocked the batch
+}
+
+// ExpenseBatch Not a part of DB schema, the result of fetching several expenses by ID
+type ExpenseBatch struct {
+	Expenses []ExpenseDetails `json:"expenses"` // Accessible expenses, in request order
+	Missing  []uuid.UUID      `json:"missing"`  // Requested IDs that do not exist or are not accessible
+}
+
+// ExpenseContext Not a part of DB schema, an expense with what its detail view needs about the group and the viewer
+type ExpenseContext struct {
+	Expense      ExpenseDetails `json:"expense"`
+	GroupName    string         `json:"group_name"`
+	Currency     string         `json:"currency"`     // The group's base currency
+	Participants []Participant  `json:"participants"` // Users with a split, payers first
+	Paid         float64        `json:"paid"`         // What the authenticated user paid towards the expense
+	Owed         float64        `json:"owed"`         // What the authenticated user owes for the expense
+}
+
+// Participant Not a part of DB schema, a user taking part in an expense
+type Participant struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+}
+
+// ParticipantAmount Not a part of DB schema, an amount assigned to a single user
+type ParticipantAmount struct {
+	UserID uuid.UUID `json:"user_id"`
+	Amount float64   `json:"amount"`
+}
+
 // ExpenseSplit represents how an expense is split among users
 type ExpenseSplit struct {
 	ExpenseID uuid.UUID `json:"-" db:"expense_id"`
@@ -93,6 +399,66 @@ type Settlement struct {
 	TransactedAt *int64    `json:"transacted_at"`
 	UserID       uuid.UUID `json:"user_id" immutable:"true"` // The other user involved in the settlement
 	Amount       float64   `json:"amount"`
+	ClientRef    *string   `json:"client_ref,omitempty" immutable:"true"` // Client-chosen reference that makes creating the settlement idempotent
+}
+
+// SettlementDetails Not a part of DB schema, a settlement history entry together with the splits it was derived from.
+// A settlement created by the API has two splits: the payer's (is_paid=true) and the receiver's.
+type SettlementDetails struct {
+	Settlement                // Struct embedding to include the compact, signed view of the settlement
+	ExpenseID  uuid.UUID      `json:"expense_id"`
+	Splits     []ExpenseSplit `json:"splits"`
+}
+
+// SettlementWithUsers Not a part of DB schema, a settlement with both parties embedded (?expand=users)
+type SettlementWithUsers struct {
+	Settlement             // Struct embedding to include all Settlement fields
+	Users      []GroupUser `json:"users"` // The payer and the receiver
+}
+
+// SettlementDirection states who owes whom in a SettlementExplanation.
+type SettlementDirection string
+
+const (
+	SettlementYouOwe  SettlementDirection = "you_owe"  // The authenticated user owes the counterparty
+	SettlementOwesYou SettlementDirection = "owes_you" // The counterparty owes the authenticated user
+	SettlementSettled SettlementDirection = "settled"  // Nothing is owed either way
+)
+
+// SettlementExplanation Not a part of DB schema, an unsigned restatement of a Settlement balance.
+// It spells out the direction instead of relying on the sign convention of Settlement.Amount.
+type SettlementExplanation struct {
+	Direction    SettlementDirection `json:"direction" enums:"you_owe,owes_you,settled"`
+	Counterparty GroupUser           `json:"counterparty"`
+	Amount       float64             `json:"amount"`   // Always positive, zero when settled
+	Currency     string              `json:"currency"` // The group's base currency
+}
+
+// MemberBalance Not a part of DB schema, a member's net position in a group.
+// Positive means the member is owed money, negative means the member owes money.
+type MemberBalance struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Name    string    `json:"name"`
+	Balance float64   `json:"balance"`
+}
+
+// GroupSummary Not a part of DB schema, group-wide totals for a dashboard.
+// Unlike Settlement it is not relative to the authenticated user.
+type GroupSummary struct {
+	Currency       string          `json:"currency"`        // The group's base currency
+	TotalSpend     float64         `json:"total_spend"`     // Sum of live expense amounts, settlements excluded
+	Balances       []MemberBalance `json:"balances"`        // Every member, highest balance first; settled members have 0
+	UnsettledPairs int             `json:"unsettled_pairs"` // Pairs of users with a direct debt beyond the split tolerance
+	TopCreditor    *MemberBalance  `json:"top_creditor"`    // nil if nobody is owed money
+	TopDebtor      *MemberBalance  `json:"top_debtor"`      // nil if nobody owes money
+}
+
+// SettlementTransfer represents a single payment in a group's optimized settlement plan.
+// Unlike Settlement, it is not relative to the authenticated user: FromUserID pays ToUserID.
+type SettlementTransfer struct {
+	FromUserID uuid.UUID `json:"from_user_id"`
+	ToUserID   uuid.UUID `json:"to_user_id"`
+	Amount     float64   `json:"amount"` // Always positive
 }
 
 // UserExpense extends Expense with user-specific amount
@@ -106,3 +472,10 @@ type HealthCheck struct {
 	Name   string `json:"name" example:"Qashare"`
 	App    string `json:"app" example:"Qashare"`
 }
+
+// ReadinessCheck Not a part of DB schema, used for readiness probe responses
+type ReadinessCheck struct {
+	Status            string   `json:"status" example:"ok"`
+	Database          string   `json:"database" example:"ok"`
+	PendingMigrations []string `json:"pending_migrations,omitempty"`
+}
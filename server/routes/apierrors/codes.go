@@ -0,0 +1,128 @@
+package apierrors
+
+// Code is a machine-readable error code, sent to clients in the "code" field of an AppError.
+type Code string
+
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeInvalidName        Code = "BAD_NAME"
+	CodeNameTooShort       Code = "NAME_TOO_SHORT"
+	CodeNameTooLong        Code = "NAME_TOO_LONG"
+	CodeEmailAlreadyExists Code = "EMAIL_EXISTS"
+	CodeInvalidEmail       Code = "BAD_EMAIL"
+	CodeInvalidDescription Code = "BAD_DESCRIPTION"
+	CodeInvalidTitle       Code = "BAD_TITLE"
+	CodeInvalidCurrency    Code = "BAD_CURRENCY"
+	CodeInvalidLocale      Code = "BAD_LOCALE"
+
+	// Auth codes
+	CodeInvalidPassword               Code = "BAD_PASSWORD"
+	CodeBadCredentials                Code = "BAD_CREDENTIALS"
+	CodeInvalidAccessToken            Code = "INVALID_TOKEN"
+	CodeExpiredAccessToken            Code = "EXPIRED_TOKEN"
+	CodeInvalidRefreshToken           Code = "INVALID_REFRESH_TOKEN"
+	CodeExpiredRefreshToken           Code = "EXPIRED_REFRESH_TOKEN"
+	CodeEmailNotVerified              Code = "EMAIL_NOT_VERIFIED"
+	CodeEmailVerificationTokenExpired Code = "EMAIL_VERIFICATION_TOKEN_EXPIRED"
+	CodeEmailVerificationTokenError   Code = "EMAIL_VERIFICATION_TOKEN_ERROR"
+	CodeInvalidAPIKey                 Code = "INVALID_API_KEY"
+
+	// Group codes
+	CodeUserNotFound       Code = "USER_NOT_FOUND"
+	CodeGroupNotFound      Code = "GROUP_NOT_FOUND"
+	CodeUserNotInGroup     Code = "USER_NOT_IN_GROUP"
+	CodeUsersNotRelated    Code = "USERS_NOT_RELATED"
+	CodeNoPermissions      Code = "NO_PERMISSIONS"
+	CodeGuestsDisabled     Code = "GUESTS_DISABLED"
+	CodeUserOwnsGroups     Code = "USER_OWNS_GROUPS"
+	CodeGroupLimit         Code = "GROUP_LIMIT_REACHED"
+	CodeGroupFull          Code = "GROUP_FULL"
+	CodeInviteInvalid      Code = "INVITE_INVALID"
+	CodeOutstandingBalance Code = "OUTSTANDING_BALANCE"
+	CodeInvalidWebhook     Code = "BAD_WEBHOOK"
+	CodeWebhookNotFound    Code = "WEBHOOK_NOT_FOUND"
+	CodeBudgetNotFound     Code = "BUDGET_NOT_FOUND"
+
+	// Expenses codes
+	CodeExpenseNotFound      Code = "EXPENSE_NOT_FOUND"
+	CodeInvalidAmount        Code = "INVALID_AMOUNT"
+	CodeInvalidPaymentMethod Code = "BAD_PAYMENT_METHOD"
+	CodeInvalidCategory      Code = "BAD_CATEGORY"
+	CodeInvalidAttachment    Code = "BAD_ATTACHMENT"
+	CodeAttachmentNotFound   Code = "ATTACHMENT_NOT_FOUND"
+	CodeInvalidSplit         Code = "INVALID_SPLIT"
+	CodeExpenseSettled       Code = "EXPENSE_SETTLED"
+
+	// Generic codes
+	CodeTooManyRequests     Code = "TOO_MANY_REQUESTS"
+	CodeRequestTimeout      Code = "REQUEST_TIMEOUT"
+	CodeClientClosedRequest Code = "CLIENT_CLOSED_REQUEST"
+	CodeInternalServer      Code = "INTERNAL_ERROR"
+	CodeServiceUnavailable  Code = "SERVICE_UNAVAILABLE"
+)
+
+// knownCodes is the registry of every code an AppError may use.
+var knownCodes = map[Code]struct{}{
+	CodeBadRequest:                    {},
+	CodeInvalidName:                   {},
+	CodeNameTooShort:                  {},
+	CodeNameTooLong:                   {},
+	CodeEmailAlreadyExists:            {},
+	CodeInvalidEmail:                  {},
+	CodeInvalidDescription:            {},
+	CodeInvalidTitle:                  {},
+	CodeInvalidCurrency:               {},
+	CodeInvalidLocale:                 {},
+	CodeInvalidPassword:               {},
+	CodeBadCredentials:                {},
+	CodeInvalidAccessToken:            {},
+	CodeExpiredAccessToken:            {},
+	CodeInvalidRefreshToken:           {},
+	CodeExpiredRefreshToken:           {},
+	CodeEmailNotVerified:              {},
+	CodeEmailVerificationTokenExpired: {},
+	CodeEmailVerificationTokenError:   {},
+	CodeInvalidAPIKey:                 {},
+	CodeUserNotFound:                  {},
+	CodeGroupNotFound:                 {},
+	CodeUserNotInGroup:                {},
+	CodeUsersNotRelated:               {},
+	CodeNoPermissions:                 {},
+	CodeGuestsDisabled:                {},
+	CodeUserOwnsGroups:                {},
+	CodeGroupLimit:                    {},
+	CodeGroupFull:                     {},
+	CodeInviteInvalid:                 {},
+	CodeOutstandingBalance:            {},
+	CodeInvalidWebhook:                {},
+	CodeWebhookNotFound:               {},
+	CodeBudgetNotFound:                {},
+	CodeExpenseNotFound:               {},
+	CodeInvalidAmount:                 {},
+	CodeInvalidPaymentMethod:          {},
+	CodeInvalidCategory:               {},
+	CodeInvalidAttachment:             {},
+	CodeAttachmentNotFound:            {},
+	CodeInvalidSplit:                  {},
+	CodeExpenseSettled:                {},
+	CodeTooManyRequests:               {},
+	CodeRequestTimeout:                {},
+	CodeClientClosedRequest:           {},
+	CodeInternalServer:                {},
+	CodeServiceUnavailable:            {},
+}
+
+// IsKnownCode reports whether code is a registered error code.
+func IsKnownCode(code string) bool {
+	_, ok := knownCodes[Code(code)]
+	return ok
+}
+
+// KnownCodes returns every registered error code.
+func KnownCodes() []Code {
+	codes := make([]Code, 0, len(knownCodes))
+	for code := range knownCodes {
+		codes = append(codes, code)
+	}
+	return codes
+}
@@ -0,0 +1,102 @@
+package apierrors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// packageFiles parses the non-test Go files of this package.
+func packageFiles(t *testing.T) (*token.FileSet, []*ast.File) {
+	t.Helper()
+	paths, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		files = append(files, file)
+	}
+	return fset, files
+}
+
+// codeConstants maps the name of every Code constant to its value.
+func codeConstants(files []*ast.File) map[string]Code {
+	consts := make(map[string]Code)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok || spec.Type == nil || !isIdent(spec.Type, "Code") {
+				return true
+			}
+			for i, name := range spec.Names {
+				if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					value, _ := strconv.Unquote(lit.Value)
+					consts[name.Name] = Code(value)
+				}
+			}
+			return true
+		})
+	}
+	return consts
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// New already panics on an unregistered code, so this checks the other direction:
+// every code in KnownCodes is declared as a Code constant and backs at least one AppError,
+// and no Code constant is left out of knownCodes, since such a code could only be sent by hand.
+func TestKnownCodesMatchAppErrors(t *testing.T) {
+	fset, files := packageFiles(t)
+	consts := codeConstants(files)
+
+	used := make(map[Code]bool)
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isIdent(call.Fun, "New") || len(call.Args) != 4 {
+				return true
+			}
+			ident, ok := call.Args[1].(*ast.Ident)
+			if !ok || consts[ident.Name] == "" {
+				t.Errorf("%s: New is called with a code that is not a Code constant", fset.Position(call.Pos()))
+				return true
+			}
+			used[consts[ident.Name]] = true
+			return true
+		})
+	}
+
+	declared := make(map[Code]bool, len(consts))
+	for _, code := range consts {
+		declared[code] = true
+	}
+	for _, code := range KnownCodes() {
+		if !declared[code] {
+			t.Errorf("registered code %s has no Code constant", code)
+		}
+		if !used[code] {
+			t.Errorf("registered code %s is not used by any AppError", code)
+		}
+	}
+	for name, code := range consts {
+		if !IsKnownCode(string(code)) {
+			t.Errorf("%s (%s) is not registered in knownCodes", name, code)
+		}
+	}
+}
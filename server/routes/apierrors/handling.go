@@ -6,7 +6,7 @@ import (
 
 type AppError struct {
 	HTTPCode    int    `json:"-"`       // e.g., 400, 404
-	MachineCode string `json:"code"`    // e.g., "BAD_NAME", "INVALID_EMAIL"
+	MachineCode Code   `json:"code"`    // e.g., "BAD_NAME", "INVALID_EMAIL"
 	Message     string `json:"message"` // Human-readable message
 	Err         error  `json:"-"`       // Internal error for logging (optional)
 }
@@ -38,7 +38,12 @@ func (e *AppError) Unwrap() error {
 }
 
 // New creates a new AppError.
-func New(httpCode int, machineCode string, message string, err error) *AppError {
+// It panics if machineCode is not registered in knownCodes, so an unregistered
+// code is caught as soon as the package is initialised.
+func New(httpCode int, machineCode Code, message string, err error) *AppError {
+	if !IsKnownCode(string(machineCode)) {
+		panic(fmt.Sprintf("apierrors: unregistered error code %q", machineCode))
+	}
 	return &AppError{
 		HTTPCode:    httpCode,
 		MachineCode: machineCode,
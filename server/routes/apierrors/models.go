@@ -3,37 +3,63 @@ package apierrors
 import "net/http"
 
 var (
-	ErrBadRequest         = New(http.StatusBadRequest, "BAD_REQUEST", "The request is invalid or malformed.", nil)
-	ErrInvalidName        = New(http.StatusBadRequest, "BAD_NAME", "The name provided contains invalid characters.", nil)
-	ErrEmailAlreadyExists = New(http.StatusConflict, "EMAIL_EXISTS", "An account with this email already exists.", nil)
-	ErrInvalidEmail       = New(http.StatusBadRequest, "BAD_EMAIL", "The email format is incorrect.", nil)
-	ErrInvalidDescription = New(http.StatusBadRequest, "BAD_DESCRIPTION", "The description contains invalid characters.", nil)
+	ErrBadRequest         = New(http.StatusBadRequest, CodeBadRequest, "The request is invalid or malformed.", nil)
+	ErrInvalidName        = New(http.StatusBadRequest, CodeInvalidName, "The name provided contains invalid characters.", nil)
+	ErrNameTooShort       = New(http.StatusBadRequest, CodeNameTooShort, "The name provided is too short.", nil)
+	ErrNameTooLong        = New(http.StatusBadRequest, CodeNameTooLong, "The name provided is too long.", nil)
+	ErrEmailAlreadyExists = New(http.StatusConflict, CodeEmailAlreadyExists, "An account with this email already exists.", nil)
+	ErrInvalidEmail       = New(http.StatusBadRequest, CodeInvalidEmail, "The email format is incorrect.", nil)
+	ErrInvalidDescription = New(http.StatusBadRequest, CodeInvalidDescription, "The description contains invalid characters.", nil)
+	ErrInvalidTitle       = New(http.StatusBadRequest, CodeInvalidTitle, "The title is missing or invalid.", nil)
+	ErrInvalidCurrency    = New(http.StatusBadRequest, CodeInvalidCurrency, "The currency must be a 3-letter ISO 4217 code.", nil)
+	ErrInvalidLocale      = New(http.StatusBadRequest, CodeInvalidLocale, "The locale must be a BCP 47 language tag such as en-US.", nil)
 
 	// Auth Errors
-	ErrInvalidPassword               = New(http.StatusBadRequest, "BAD_PASSWORD", "The password syntax is incorrect.", nil)
-	ErrBadCredentials                = New(http.StatusUnauthorized, "BAD_CREDENTIALS", "The provided credentials are incorrect.", nil)
-	ErrInvalidAccessToken            = New(http.StatusUnauthorized, "INVALID_TOKEN", "The access token is invalid.", nil)
-	ErrExpiredAccessToken            = New(http.StatusForbidden, "EXPIRED_TOKEN", "The access token has expired.", nil)
-	ErrInvalidRefreshToken           = New(http.StatusBadRequest, "INVALID_REFRESH_TOKEN", "The refresh token is invalid.", nil)
-	ErrExpiredRefreshToken           = New(http.StatusForbidden, "EXPIRED_REFRESH_TOKEN", "The refresh token has expired.", nil)
-	ErrEmailNotVerified              = New(http.StatusForbidden, "EMAIL_NOT_VERIFIED", "The email address has not been verified.", nil)
-	ErrEmailVerificationTokenExpired = New(http.StatusForbidden, "EMAIL_VERIFICATION_TOKEN_EXPIRED", "The email verification token has expired.", nil)
-	ErrEmailVerificationTokenError   = New(http.StatusBadRequest, "EMAIL_VERIFICATION_TOKEN_ERROR", "The email verification token is invalid or malformed.", nil)
+	ErrInvalidPassword               = New(http.StatusBadRequest, CodeInvalidPassword, "The password syntax is incorrect.", nil)
+	ErrBadCredentials                = New(http.StatusUnauthorized, CodeBadCredentials, "The provided credentials are incorrect.", nil)
+	ErrInvalidAccessToken            = New(http.StatusUnauthorized, CodeInvalidAccessToken, "The access token is invalid.", nil)
+	ErrExpiredAccessToken            = New(http.StatusForbidden, CodeExpiredAccessToken, "The access token has expired.", nil)
+	ErrInvalidRefreshToken           = New(http.StatusBadRequest, CodeInvalidRefreshToken, "The refresh token is invalid.", nil)
+	ErrExpiredRefreshToken           = New(http.StatusForbidden, CodeExpiredRefreshToken, "The refresh token has expired.", nil)
+	ErrEmailNotVerified              = New(http.StatusForbidden, CodeEmailNotVerified, "The email address has not been verified.", nil)
+	ErrEmailVerificationTokenExpired = New(http.StatusForbidden, CodeEmailVerificationTokenExpired, "The email verification token has expired.", nil)
+	ErrEmailVerificationTokenError   = New(http.StatusBadRequest, CodeEmailVerificationTokenError, "The email verification token is invalid or malformed.", nil)
+	ErrInvalidAPIKey                 = New(http.StatusUnauthorized, CodeInvalidAPIKey, "The API key is invalid, expired, or revoked.", nil)
 
 	// Group Errors
-	ErrUserNotFound    = New(http.StatusNotFound, "USER_NOT_FOUND", "The requested user does not exist.", nil)
-	ErrGroupNotFound   = New(http.StatusNotFound, "GROUP_NOT_FOUND", "The requested group does not exist.", nil)
-	ErrUserNotInGroup  = New(http.StatusForbidden, "USER_NOT_IN_GROUP", "The user is not a member of the specified group.", nil)
-	ErrUsersNotRelated = New(http.StatusForbidden, "USERS_NOT_RELATED", "The users are not related in the specified context.", nil)
-	ErrNoPermissions   = New(http.StatusForbidden, "NO_PERMISSIONS", "You do not have sufficient permissions to perform this action.", nil)
-	ErrGuestsDisabled  = New(http.StatusForbidden, "GUESTS_DISABLED", "Guest user creation is disabled.", nil)
-	ErrUserOwnsGroups  = New(http.StatusConflict, "USER_OWNS_GROUPS", "Cannot delete account while owning groups. Transfer ownership first.", nil)
+	ErrUserNotFound       = New(http.StatusNotFound, CodeUserNotFound, "The requested user does not exist.", nil)
+	ErrGroupNotFound      = New(http.StatusNotFound, CodeGroupNotFound, "The requested group does not exist.", nil)
+	ErrUserNotInGroup     = New(http.StatusForbidden, CodeUserNotInGroup, "The user is not a member of the specified group.", nil)
+	ErrUsersNotRelated    = New(http.StatusForbidden, CodeUsersNotRelated, "The users are not related in the specified context.", nil)
+	ErrNoPermissions      = New(http.StatusForbidden, CodeNoPermissions, "You do not have sufficient permissions to perform this action.", nil)
+	ErrGuestsDisabled     = New(http.StatusForbidden, CodeGuestsDisabled, "Guest user creation is disabled.", nil)
+	ErrUserOwnsGroups     = New(http.StatusConflict, CodeUserOwnsGroups, "Cannot delete account while owning groups. Transfer ownership first.", nil)
+	ErrGroupLimit         = New(http.StatusConflict, CodeGroupLimit, "You have reached the maximum number of groups you can create.", nil)
+	ErrGroupFull          = New(http.StatusConflict, CodeGroupFull, "The group has reached its maximum number of members.", nil)
+	ErrInviteInvalid      = New(http.StatusNotFound, CodeInviteInvalid, "The invite link is invalid, expired, or has no uses left.", nil)
+	ErrOutstandingBalance = New(http.StatusConflict, CodeOutstandingBalance, "The user still owes or is owed money in the group. Settle up first.", nil)
+	ErrInvalidWebhook     = New(http.StatusBadRequest, CodeInvalidWebhook, "The webhook URL or event list is invalid.", nil)
+	ErrWebhookNotFound    = New(http.StatusNotFound, CodeWebhookNotFound, "The requested webhook does not exist.", nil)
+	ErrBudgetNotFound     = New(http.StatusNotFound, CodeBudgetNotFound, "The group has no budget set.", nil)
 
 	// Expenses errors
-	ErrExpenseNotFound = New(http.StatusNotFound, "EXPENSE_NOT_FOUND", "The requested expense does not exist.", nil)
-	ErrInvalidAmount   = New(http.StatusBadRequest, "INVALID_AMOUNT", "The expense amount is invalid.", nil)
-	ErrInvalidSplit    = New(http.StatusBadRequest, "INVALID_SPLIT", "The expense splits are invalid or do not sum up correctly.", nil)
+	ErrExpenseNotFound      = New(http.StatusNotFound, CodeExpenseNotFound, "The requested expense does not exist.", nil)
+	ErrInvalidAmount        = New(http.StatusBadRequest, CodeInvalidAmount, "The expense amount is invalid.", nil)
+	ErrInvalidPaymentMethod = New(http.StatusBadRequest, CodeInvalidPaymentMethod, "The payment method is not supported.", nil)
+	ErrInvalidCategory      = New(http.StatusBadRequest, CodeInvalidCategory, "The category is too long, invalid, or not supported.", nil)
+	ErrInvalidAttachment    = New(http.StatusBadRequest, CodeInvalidAttachment, "The attachment reference, content type or size is invalid.", nil)
+	ErrAttachmentNotFound   = New(http.StatusNotFound, CodeAttachmentNotFound, "The requested attachment does not exist.", nil)
+	ErrInvalidSplit         = New(http.StatusBadRequest, CodeInvalidSplit, "The expense splits are invalid or do not sum up correctly.", nil)
+	ErrExpenseSettled       = New(http.StatusConflict, CodeExpenseSettled, "The expense is covered by a settlement and cannot be changed until the settlement is deleted.", nil)
 
 	// Generic errors
-	ErrInternalServer = New(http.StatusInternalServerError, "INTERNAL_ERROR", "Something went wrong on our end.", nil)
+	ErrTooManyRequests     = New(http.StatusTooManyRequests, CodeTooManyRequests, "Too many requests. Please try again later.", nil)
+	ErrRequestTimeout      = New(http.StatusRequestTimeout, CodeRequestTimeout, "The request took too long to process. Please try again.", nil)
+	ErrClientClosedRequest = New(StatusClientClosedRequest, CodeClientClosedRequest, "The request was cancelled by the client.", nil)
+	ErrInternalServer      = New(http.StatusInternalServerError, CodeInternalServer, "Something went wrong on our end.", nil)
+	ErrServiceUnavailable  = New(http.StatusServiceUnavailable, CodeServiceUnavailable, "The service is temporarily unavailable. Please try again.", nil)
 )
+
+// StatusClientClosedRequest is the non-standard status (popularised by nginx) used when the
+// client went away before the response was ready. The client never sees it; it only shows up in logs.
+const StatusClientClosedRequest = 499
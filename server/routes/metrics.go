@@ -0,0 +1,81 @@
+package routes
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pranaovs/qashare/routes/middleware"
+)
+
+// Metrics godoc
+// @Summary Metrics endpoint
+// @Description Report database connection pool statistics and request counts by status code in the Prometheus text exposition format.
+// @Description Requires either the METRICS_TOKEN as a bearer token in the Authorization header, or an API key with the metrics scope in the X-API-Key header. The read replica pool is only reported when one is configured.
+// @Tags health
+// @Produce plain
+// @Success 200 {string} string "Metrics in the Prometheus text format"
+// @Failure 401 {object} apierrors.AppError "INVALID_API_KEY: Neither a valid metrics token nor a valid API key was provided"
+// @Failure 403 {object} apierrors.AppError "NO_PERMISSIONS: The API key lacks the metrics scope"
+// @Router /metrics [get]
+func Metrics(c *gin.Context, pool, readPool *pgxpool.Pool, requests *middleware.RequestMetrics) {
+	pools := map[string]*pgxpool.Pool{"primary": pool}
+	if readPool != pool {
+		pools["replica"] = readPool
+	}
+	stats := make(map[string]*pgxpool.Stat, len(pools))
+	for name, p := range pools {
+		stats[name] = p.Stat()
+	}
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	poolMetrics := []struct {
+		name, help, kind string
+		value            func(*pgxpool.Stat) int64
+	}{
+		{"db_pool_acquired_conns", "Connections currently acquired from the pool.", "gauge", func(s *pgxpool.Stat) int64 { return int64(s.AcquiredConns()) }},
+		{"db_pool_idle_conns", "Idle connections in the pool.", "gauge", func(s *pgxpool.Stat) int64 { return int64(s.IdleConns()) }},
+		{"db_pool_total_conns", "Total connections in the pool.", "gauge", func(s *pgxpool.Stat) int64 { return int64(s.TotalConns()) }},
+		{"db_pool_max_conns", "Maximum size of the pool.", "gauge", func(s *pgxpool.Stat) int64 { return int64(s.MaxConns()) }},
+		{"db_pool_new_conns_total", "Connections opened since the pool was created.", "counter", func(s *pgxpool.Stat) int64 { return s.NewConnsCount() }},
+		{"db_pool_empty_acquire_total", "Acquires that had to wait for a connection because the pool was empty.", "counter", func(s *pgxpool.Stat) int64 { return s.EmptyAcquireCount() }},
+	}
+	for _, metric := range poolMetrics {
+		writeMetricHeader(c.Writer, metric.name, metric.help, metric.kind)
+		for _, name := range sortedKeys(stats) {
+			fmt.Fprintf(c.Writer, "qashare_%s{pool=%q} %d\n", metric.name, name, metric.value(stats[name]))
+		}
+	}
+
+	counts := requests.Snapshot()
+	statuses := make([]int, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	writeMetricHeader(c.Writer, "http_requests_total", "Requests handled, by response status code.", "counter")
+	for _, status := range statuses {
+		fmt.Fprintf(c.Writer, "qashare_http_requests_total{status=\"%d\"} %d\n", status, counts[status])
+	}
+}
+
+// writeMetricHeader writes the HELP and TYPE lines that precede a metric's samples.
+func writeMetricHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP qashare_%s %s\n# TYPE qashare_%s %s\n", name, help, name, kind)
+}
+
+// sortedKeys returns the pool names in a stable order.
+func sortedKeys(stats map[string]*pgxpool.Stat) []string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
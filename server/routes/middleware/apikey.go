@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"github.com/pranaovs/qashare/apperrors"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	APIKeyKey    = "apiKey"
+	APIKeyHeader = "X-API-Key"
+)
+
+// APIKeyAuth authenticates service-to-service calls using the X-API-Key header.
+// The key must be active and grant the required scope. On success the key
+// (the service principal) is stored in the context for handlers to read.
+func APIKeyAuth(pool *pgxpool.Pool, scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			utils.SendAbort(c, apierrors.ErrInvalidAPIKey.Msg("missing API key"))
+			return
+		}
+
+		apiKey, err := db.VerifyAPIKey(c.Request.Context(), pool, key)
+		if err != nil {
+			mapped := apperrors.MapError(err, map[error]*apierrors.AppError{
+				db.ErrNotFound:     apierrors.ErrInvalidAPIKey,
+				db.ErrInvalidInput: apierrors.ErrInvalidAPIKey,
+			})
+			if appErr, ok := mapped.(*apierrors.AppError); ok {
+				utils.SendAbort(c, appErr)
+				return
+			}
+			utils.SendAbort(c, apierrors.ErrInternalServer)
+			return
+		}
+
+		if !apiKey.HasScope(scope) {
+			utils.SendAbort(c, apierrors.ErrNoPermissions.Msgf("API key lacks the %s scope", scope))
+			return
+		}
+
+		c.Set(APIKeyKey, apiKey)
+		c.Next()
+	}
+}
+
+func GetAPIKey(c *gin.Context) (models.APIKey, bool) {
+	apiKey, exists := c.Get(APIKeyKey)
+	if !exists {
+		return models.APIKey{}, false
+	}
+
+	apiKeyVal, ok := apiKey.(models.APIKey)
+	if !ok {
+		return models.APIKey{}, false
+	}
+
+	return apiKeyVal, true
+}
+
+// MustGetAPIKey retrieves the authenticated API key from the context. Intended for use in handlers.
+// If the key is not found, it panics, indicating a server-side misconfiguration.
+func MustGetAPIKey(c *gin.Context) models.APIKey {
+	apiKey, ok := GetAPIKey(c)
+	if !ok {
+		panic("MustGetAPIKey: API key not found in context. Did you forget to add the APIKeyAuth middleware?")
+	}
+	return apiKey
+}
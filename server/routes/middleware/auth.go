@@ -4,15 +4,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
 	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
 	"github.com/pranaovs/qashare/routes/apierrors"
 	"github.com/pranaovs/qashare/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 const (
 	UserIDKey    = "userID"
 	SessionIDKey = "sessionID"
+	ClaimsKey    = "claims"
 )
 
 func RequireAuth(jwtConfig config.JWTConfig) gin.HandlerFunc {
@@ -43,6 +47,40 @@ func RequireAuth(jwtConfig config.JWTConfig) gin.HandlerFunc {
 
 		c.Set(UserIDKey, userID)
 		c.Set(SessionIDKey, sessionID)
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail rejects requests from users whose email address has not been verified.
+// It must run after RequireAuth. Unverified users can still log in; only the routes that
+// opt into this middleware are gated.
+// When email verification is disabled (VERIFY_EMAIL=false) every request passes, since users
+// who registered unverified would otherwise have no way to verify and would be locked out.
+func RequireVerifiedEmail(pool *pgxpool.Pool, appConfig config.AppConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !appConfig.Verification {
+			c.Next()
+			return
+		}
+
+		userID := MustGetUserID(c)
+
+		verified, err := db.EmailVerified(c.Request.Context(), pool, userID)
+		if err != nil {
+			if db.IsNotFound(err) {
+				utils.SendAbort(c, apierrors.ErrInvalidAccessToken)
+				return
+			}
+			utils.SendAbort(c, apierrors.ErrInternalServer)
+			return
+		}
+
+		if !verified {
+			utils.SendAbort(c, apierrors.ErrEmailNotVerified)
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -94,3 +132,27 @@ func MustGetSessionID(c *gin.Context) uuid.UUID {
 	}
 	return sessionID
 }
+
+func GetClaims(c *gin.Context) (*models.TokenClaims, bool) {
+	claims, exists := c.Get(ClaimsKey)
+	if !exists {
+		return nil, false
+	}
+
+	claimsVal, ok := claims.(*models.TokenClaims)
+	if !ok {
+		return nil, false
+	}
+
+	return claimsVal, true
+}
+
+// MustGetClaims retrieves the verified access token claims from the context. Intended for use in handlers.
+// If the claims are not found, it panics, indicating a server-side misconfiguration.
+func MustGetClaims(c *gin.Context) *models.TokenClaims {
+	claims, ok := GetClaims(c)
+	if !ok {
+		panic("MustGetClaims: claims not found in context. Did you forget to add the RequireAuth middleware?")
+	}
+	return claims
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -58,8 +60,9 @@ func VerifyExpenseAccess(pool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
+		// Non-members get the same response as for a missing expense, so they cannot probe for IDs
 		if !isMember {
-			utils.SendAbort(c, apierrors.ErrNoPermissions)
+			utils.SendAbort(c, apierrors.ErrExpenseNotFound)
 			return
 		}
 
@@ -126,7 +129,7 @@ func VerifyExpenseAdmin(pool *pgxpool.Pool) gin.HandlerFunc {
 
 		// If the user is not the expense creator, deny access
 		if expense.AddedBy != userID {
-			utils.SendAbort(c, apierrors.ErrNoPermissions)
+			utils.SendAbort(c, denyAccess(c.Request.Context(), pool, userID, expense.GroupID, apierrors.ErrExpenseNotFound))
 			return
 		}
 
@@ -141,6 +144,19 @@ func VerifyExpenseAdmin(pool *pgxpool.Pool) gin.HandlerFunc {
 // A user can delete an expense if they are the expense creator OR the group admin (group creator).
 // Sets expenseID, groupID, and the expense object itself in context to avoid double-fetching.
 func VerifyExpenseDeleteAccess(pool *pgxpool.Pool) gin.HandlerFunc {
+	return verifyExpenseManageAccess(pool, db.GetExpense)
+}
+
+// VerifyExpenseRestoreAccess checks if the authenticated user can restore the soft-deleted expense
+// specified in the URL parameter "id". The same users who could delete the expense can restore it.
+// Sets expenseID, groupID, and the expense object itself in context to avoid double-fetching.
+func VerifyExpenseRestoreAccess(pool *pgxpool.Pool) gin.HandlerFunc {
+	return verifyExpenseManageAccess(pool, db.GetDeletedExpense)
+}
+
+// verifyExpenseManageAccess implements the creator-or-group-admin check shared by delete and restore.
+// getExpense selects whether live or soft-deleted expenses are looked up.
+func verifyExpenseManageAccess(pool *pgxpool.Pool, getExpense func(context.Context, *pgxpool.Pool, uuid.UUID) (models.ExpenseDetails, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID := MustGetUserID(c)
 
@@ -156,7 +172,7 @@ func VerifyExpenseDeleteAccess(pool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		expense, err := db.GetExpense(c.Request.Context(), pool, expenseID)
+		expense, err := getExpense(c.Request.Context(), pool, expenseID)
 		if err != nil {
 			if db.IsNotFound(err) {
 				utils.SendAbort(c, apierrors.ErrExpenseNotFound)
@@ -166,55 +182,77 @@ func VerifyExpenseDeleteAccess(pool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
-		// Settlements must be accessed through the /settlements endpoints
-		if expense.IsSettlement {
-			utils.SendAbort(c, apierrors.ErrExpenseNotFound)
+		if appErr := CheckExpenseManageAccess(c.Request.Context(), pool, userID, expense); appErr != nil {
+			utils.SendAbort(c, appErr)
 			return
 		}
 
-		// Allow if user is the expense creator
-		isCreator := expense.AddedBy == userID
+		c.Set(ExpenseKey, expense)
+		c.Set(ExpenseIDKey, expenseID)
+		c.Set(GroupIDKey, expense.GroupID)
+		c.Next()
+	}
+}
 
-		// Allow if user is the group admin (group creator)
-		isGroupAdmin := false
-		if !isCreator {
-			creatorID, err := db.GetGroupCreator(c.Request.Context(), pool, expense.GroupID)
-			if err != nil {
-				if db.IsNotFound(err) {
-					utils.SendAbort(c, apierrors.ErrGroupNotFound)
-					return
-				}
-				utils.SendAbort(c, apierrors.ErrInternalServer)
-				return
+// CheckExpenseManageAccess reports whether userID may delete or restore the expense.
+// The expense creator and the group admin may, except that a group admin needs to take part
+// in a private expense to manage it. Settlements are reported as not found, since they are
+// managed through the /settlements endpoints. Returns nil if access is granted.
+func CheckExpenseManageAccess(ctx context.Context, pool *pgxpool.Pool, userID uuid.UUID, expense models.ExpenseDetails) *apierrors.AppError {
+	// Settlements must be accessed through the /settlements endpoints
+	if expense.IsSettlement {
+		return apierrors.ErrExpenseNotFound
+	}
+
+	// Allow if user is the expense creator
+	isCreator := expense.AddedBy == userID
+
+	// Allow if user is the group admin (group creator)
+	isGroupAdmin := false
+	if !isCreator {
+		creatorID, err := db.GetGroupCreator(ctx, pool, expense.GroupID)
+		if err != nil {
+			if db.IsNotFound(err) {
+				return apierrors.ErrGroupNotFound
 			}
-			isGroupAdmin = creatorID == userID
+			return apierrors.ErrInternalServer.WithInternal(err)
 		}
+		isGroupAdmin = creatorID == userID
+	}
 
-		if !isCreator && !isGroupAdmin {
-			utils.SendAbort(c, apierrors.ErrNoPermissions)
-			return
-		}
+	if !isCreator && !isGroupAdmin {
+		return denyAccess(ctx, pool, userID, expense.GroupID, apierrors.ErrExpenseNotFound)
+	}
 
-		// For private expenses, group admins cannot delete unless they are the creator or a split participant
-		if expense.IsPrivate && !isCreator {
-			isParticipant := false
-			for _, split := range expense.Splits {
-				if split.UserID == userID {
-					isParticipant = true
-					break
-				}
-			}
-			if !isParticipant {
-				utils.SendAbort(c, apierrors.ErrExpenseNotFound)
-				return
+	// For private expenses, group admins cannot delete unless they are the creator or a split participant
+	if expense.IsPrivate && !isCreator {
+		isParticipant := false
+		for _, split := range expense.Splits {
+			if split.UserID == userID {
+				isParticipant = true
+				break
 			}
 		}
+		if !isParticipant {
+			return apierrors.ErrExpenseNotFound
+		}
+	}
 
-		c.Set(ExpenseKey, expense)
-		c.Set(ExpenseIDKey, expenseID)
-		c.Set(GroupIDKey, expense.GroupID)
-		c.Next()
+	return nil
+}
+
+// denyAccess picks the error for a user who may not act on an expense or settlement of groupID.
+// Group members get ErrNoPermissions, since they can see the resource anyway. Everyone else gets
+// notFound, so non-members cannot tell an existing ID from a missing one.
+func denyAccess(ctx context.Context, pool *pgxpool.Pool, userID, groupID uuid.UUID, notFound *apierrors.AppError) *apierrors.AppError {
+	isMember, err := db.MemberOfGroup(ctx, pool, userID, groupID)
+	if err != nil {
+		return apierrors.ErrInternalServer.WithInternal(err)
 	}
+	if !isMember {
+		return notFound
+	}
+	return apierrors.ErrNoPermissions
 }
 
 // VerifySettlementAccess checks if the authenticated user has access to the settlement specified in the URL parameter "id".
@@ -258,8 +296,9 @@ func VerifySettlementAccess(pool *pgxpool.Pool) gin.HandlerFunc {
 			return
 		}
 
+		// Non-members get the same response as for a missing settlement, so they cannot probe for IDs
 		if !isMember {
-			utils.SendAbort(c, apierrors.ErrNoPermissions)
+			utils.SendAbort(c, apierrors.ErrExpenseNotFound.Msg("settlement not found"))
 			return
 		}
 
@@ -319,7 +358,7 @@ func VerifySettlementAdmin(pool *pgxpool.Pool) gin.HandlerFunc {
 		}
 
 		if !isPayer {
-			utils.SendAbort(c, apierrors.ErrNoPermissions)
+			utils.SendAbort(c, denyAccess(c.Request.Context(), pool, userID, groupID, apierrors.ErrExpenseNotFound.Msg("settlement not found")))
 			return
 		}
 
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/db/dbtest"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveAs runs middleware for a GET of path by userID and reports the response.
+// A request that passes the middleware is answered with 200 by a stub handler.
+func serveAs(t *testing.T, middleware gin.HandlerFunc, userID uuid.UUID, id string) (int, string) {
+	t.Helper()
+	r := gin.New()
+	r.GET("/:id", func(c *gin.Context) {
+		c.Set(UserIDKey, userID)
+	}, middleware, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+id, nil))
+	if w.Code == http.StatusOK {
+		return w.Code, ""
+	}
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error body %q: %v", w.Body.String(), err)
+	}
+	return w.Code, body.Code
+}
+
+type accessCase struct {
+	name       string
+	userID     uuid.UUID
+	id         string
+	wantStatus int
+	wantCode   string
+}
+
+func runAccessCases(t *testing.T, middleware gin.HandlerFunc, tests []accessCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := serveAs(t, middleware, tt.userID, tt.id)
+			if status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s", status, code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyExpenseAccess(t *testing.T) {
+	pool := dbtest.Pool(t)
+	owner, member, outsider := dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, owner.UserID, member.UserID)
+	expense := dbtest.Expense(t, pool, group.GroupID, owner.UserID, 10,
+		dbtest.Paid(owner.UserID, 10), dbtest.Owes(member.UserID, 10))
+	settlement := dbtest.Settlement(t, pool, group.GroupID, member.UserID, owner.UserID, 10)
+
+	runAccessCases(t, VerifyExpenseAccess(pool), []accessCase{
+		{"member", member.UserID, expense.ExpenseID.String(), http.StatusOK, ""},
+		{"non-member of an existing expense", outsider.UserID, expense.ExpenseID.String(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"missing expense", member.UserID, uuid.NewString(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"settlement", member.UserID, settlement.ExpenseID.String(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"malformed ID", member.UserID, "not-a-uuid", http.StatusBadRequest, "BAD_REQUEST"},
+	})
+}
+
+func TestVerifyExpenseDeleteAccess(t *testing.T) {
+	pool := dbtest.Pool(t)
+	owner, creator, member, outsider := dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, owner.UserID, creator.UserID, member.UserID)
+	expense := dbtest.Expense(t, pool, group.GroupID, creator.UserID, 10,
+		dbtest.Paid(creator.UserID, 10), dbtest.Owes(member.UserID, 10))
+
+	runAccessCases(t, VerifyExpenseDeleteAccess(pool), []accessCase{
+		{"expense creator", creator.UserID, expense.ExpenseID.String(), http.StatusOK, ""},
+		{"group admin", owner.UserID, expense.ExpenseID.String(), http.StatusOK, ""},
+		{"other member", member.UserID, expense.ExpenseID.String(), http.StatusForbidden, "NO_PERMISSIONS"},
+		{"non-member of an existing expense", outsider.UserID, expense.ExpenseID.String(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"missing expense", outsider.UserID, uuid.NewString(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+	})
+}
+
+func TestVerifySettlementAccess(t *testing.T) {
+	pool := dbtest.Pool(t)
+	owner, member, outsider := dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, owner.UserID, member.UserID)
+	expense := dbtest.Expense(t, pool, group.GroupID, owner.UserID, 10,
+		dbtest.Paid(owner.UserID, 10), dbtest.Owes(member.UserID, 10))
+	settlement := dbtest.Settlement(t, pool, group.GroupID, member.UserID, owner.UserID, 10)
+
+	runAccessCases(t, VerifySettlementAccess(pool), []accessCase{
+		{"member", owner.UserID, settlement.ExpenseID.String(), http.StatusOK, ""},
+		{"non-member of an existing settlement", outsider.UserID, settlement.ExpenseID.String(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"missing settlement", member.UserID, uuid.NewString(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+		{"expense that is not a settlement", member.UserID, expense.ExpenseID.String(), http.StatusNotFound, "EXPENSE_NOT_FOUND"},
+	})
+}
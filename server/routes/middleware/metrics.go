@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+	"sync"
+
+	"github.com/pranaovs/qashare/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RequestMetrics counts handled requests by response status code.
+// Counts live in process memory and reset on restart, like any Prometheus counter.
+type RequestMetrics struct {
+	mu       sync.Mutex
+	byStatus map[int]uint64
+}
+
+func NewRequestMetrics() *RequestMetrics {
+	return &RequestMetrics{byStatus: make(map[int]uint64)}
+}
+
+// Snapshot returns a copy of the request counts keyed by status code.
+func (m *RequestMetrics) Snapshot() map[int]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[int]uint64, len(m.byStatus))
+	for status, count := range m.byStatus {
+		counts[status] = count
+	}
+	return counts
+}
+
+// CountRequests records the status code of every request once its handlers have run.
+func CountRequests(metrics *RequestMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		metrics.mu.Lock()
+		metrics.byStatus[c.Writer.Status()]++
+		metrics.mu.Unlock()
+	}
+}
+
+// MetricsAuth guards the metrics endpoint. A scraper authenticates either with the static
+// METRICS_TOKEN as a bearer token, which needs no database setup, or with an API key that
+// grants the metrics scope (see APIKeyAuth). An empty token disables the bearer option.
+func MetricsAuth(pool *pgxpool.Pool, token string) gin.HandlerFunc {
+	apiKeyAuth := APIKeyAuth(pool, models.ScopeMetrics)
+	return func(c *gin.Context) {
+		if token != "" {
+			bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if ok && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1 {
+				c.Next()
+				return
+			}
+		}
+		apiKeyAuth(c)
+	}
+}
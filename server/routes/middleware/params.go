@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// ValidateUUIDParam rejects the request with ErrBadRequest if any of the named path
+// parameters is not a valid UUID, before a handler or query sees it. Without it a malformed
+// ID only fails deep in the database layer, where it is indistinguishable from a missing row.
+// Parameters that are not part of the matched route are skipped, so it can be applied to a
+// whole router group.
+func ValidateUUIDParam(names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, name := range names {
+			value, ok := c.Params.Get(name)
+			if !ok {
+				continue
+			}
+			if !db.ValidateUUID(value) {
+				utils.SendAbort(c, apierrors.ErrBadRequest.Msgf("invalid %s format", name))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// MustGetUUIDParam returns the named path parameter as a UUID. Intended for use in handlers
+// of routes where ValidateUUIDParam has already checked the parameter.
+// If the parameter is missing or malformed, it panics, indicating a server-side misconfiguration.
+func MustGetUUIDParam(c *gin.Context, name string) uuid.UUID {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		panic("MustGetUUIDParam: path parameter " + name + " is not a valid UUID. Did you forget to add it to ValidateUUIDParam?")
+	}
+	return id
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestValidateUUIDParam(t *testing.T) {
+	r := gin.New()
+	r.Use(ValidateUUIDParam("id", "attachment_id"))
+	r.GET("/expenses/:id/attachments/:attachment_id", func(c *gin.Context) {
+		MustGetUUIDParam(c, "id")
+		MustGetUUIDParam(c, "attachment_id")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/expenses/:id/seq/:seq", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	id := uuid.NewString()
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"both valid", "/expenses/" + id + "/attachments/" + uuid.NewString(), http.StatusNoContent},
+		{"malformed id", "/expenses/nope/attachments/" + uuid.NewString(), http.StatusBadRequest},
+		{"malformed second parameter", "/expenses/" + id + "/attachments/nope", http.StatusBadRequest},
+		{"unlisted parameter left alone", "/expenses/" + id + "/seq/7", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
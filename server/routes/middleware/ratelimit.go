@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// rateLimitDBTimeout bounds how long a database-backed rate limit check may take
+// before the request is let through (fail-open).
+const rateLimitDBTimeout = 250 * time.Millisecond
+
+// RateLimitStore counts requests per key within fixed time windows.
+type RateLimitStore interface {
+	// Hit records one request for key and returns the number of requests seen in
+	// the current window together with the time that window ends.
+	Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error)
+}
+
+// NewRateLimitStore returns the store selected by the application config.
+// The in-memory store is used unless the database store is configured.
+func NewRateLimitStore(pool *pgxpool.Pool, appConfig config.AppConfig) RateLimitStore {
+	if appConfig.RateLimitStore == config.RateLimitStoreDatabase {
+		return NewDBRateLimitStore(pool)
+	}
+	return NewMemoryRateLimitStore()
+}
+
+// MemoryRateLimitStore keeps rate limit windows in process memory.
+// Suitable for single-instance deployments; state is not shared between instances.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	windows map[string]*rateLimitWindow
+}
+
+type rateLimitWindow struct {
+	hits      int
+	expiresAt time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{windows: make(map[string]*rateLimitWindow)}
+}
+
+func (s *MemoryRateLimitStore) Hit(_ context.Context, key string, window time.Duration) (int, time.Time, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, exists := s.windows[key]
+	if !exists || !now.Before(w.expiresAt) {
+		// Opportunistically drop expired windows so the map doesn't grow unbounded
+		if !exists {
+			for k, other := range s.windows {
+				if !now.Before(other.expiresAt) {
+					delete(s.windows, k)
+				}
+			}
+		}
+		w = &rateLimitWindow{expiresAt: now.Add(window)}
+		s.windows[key] = w
+	}
+
+	w.hits++
+	return w.hits, w.expiresAt, nil
+}
+
+// DBRateLimitStore keeps rate limit windows in PostgreSQL so that limits are
+// enforced consistently across horizontally scaled instances.
+type DBRateLimitStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewDBRateLimitStore(pool *pgxpool.Pool) *DBRateLimitStore {
+	return &DBRateLimitStore{pool: pool}
+}
+
+func (s *DBRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, rateLimitDBTimeout)
+	defer cancel()
+
+	return db.HitRateLimit(ctx, s.pool, key, window)
+}
+
+// ClientIPKey builds a rate limit key from the matched route and the client IP.
+// gin resolves the client IP honouring the router's trusted proxies.
+func ClientIPKey(c *gin.Context) string {
+	return c.FullPath() + "|" + c.ClientIP()
+}
+
+// ClientUserKey builds a rate limit key from the matched route and the authenticated user,
+// so the limit follows the account however many addresses its requests come from.
+// Requests without a user fall back to ClientIPKey.
+func ClientUserKey(c *gin.Context) string {
+	userID, ok := GetUserID(c)
+	if !ok {
+		return ClientIPKey(c)
+	}
+	return c.FullPath() + "|user:" + userID.String()
+}
+
+// RateLimit allows at most limit requests per window for each key.
+// Exceeding requests are rejected with ErrTooManyRequests and a Retry-After header.
+// If the store fails (e.g. the database is slow or unavailable) the request is
+// allowed through and a warning is logged, so rate limiting never takes the API down.
+// A non-positive limit disables the middleware.
+func RateLimit(store RateLimitStore, key func(*gin.Context) string, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		hits, resetAt, err := store.Hit(c.Request.Context(), key(c), window)
+		if err != nil {
+			slog.Warn("Rate limit check failed, allowing request", "path", c.FullPath(), "error", err)
+			c.Next()
+			return
+		}
+
+		if hits > limit {
+			retryAfter := int(math.Ceil(time.Until(resetAt).Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			utils.SendAbort(c, apierrors.ErrTooManyRequests)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestClientUserKeyLimitsAUserAcrossAddresses(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	r := gin.New()
+	r.POST("/me/password", func(c *gin.Context) {
+		if id := c.GetHeader("X-Test-User"); id != "" {
+			c.Set(UserIDKey, uuid.MustParse(id))
+		}
+	}, RateLimit(store, ClientUserKey, 2, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	post := func(userID uuid.UUID, addr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/me/password", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-Test-User", userID.String())
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	victim, other := uuid.New(), uuid.New()
+	for i, addr := range []string{"192.0.2.1:1000", "192.0.2.2:1000"} {
+		if code := post(victim, addr); code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want 204", i+1, code)
+		}
+	}
+	if code := post(victim, "192.0.2.3:1000"); code != http.StatusTooManyRequests {
+		t.Errorf("third request from a new address: status = %d, want 429", code)
+	}
+	if code := post(other, "192.0.2.1:1000"); code != http.StatusNoContent {
+		t.Errorf("another user: status = %d, want 204", code)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/utils"
+)
+
+const (
+	RequestIDKey    = "requestID"
+	RequestIDHeader = "X-Request-ID"
+)
+
+// maxRequestIDLength bounds how long an incoming X-Request-ID may be before it is replaced.
+const maxRequestIDLength = 128
+
+// RequestID assigns every request a correlation ID. An incoming X-Request-ID header is kept if it is
+// a short printable token, so IDs from a proxy or client carry through; otherwise a UUID is generated.
+// The ID is stored in the gin context and the request context, where the logger picks it up for every
+// record, and echoed back in the X-Request-ID response header.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// validRequestID reports whether id is non-empty, not too long, and printable ASCII without spaces,
+// so it is safe to echo in a header and write to the logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
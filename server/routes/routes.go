@@ -1,27 +1,46 @@
 package routes
 
 import (
+	"log/slog"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
 	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/middleware"
 	v1 "github.com/pranaovs/qashare/routes/v1"
 	"github.com/pranaovs/qashare/utils"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func RegisterRoutes(basepath string, router *gin.Engine, pool *pgxpool.Pool, jwtConfig config.JWTConfig, appConfig config.AppConfig) {
+// readinessTimeout bounds how long the readiness probe waits on the database
+const readinessTimeout = 2 * time.Second
+
+func RegisterRoutes(basepath string, router *gin.Engine, pool, readPool *pgxpool.Pool, jwtConfig config.JWTConfig, appConfig config.AppConfig, dbConfig config.DatabaseConfig) {
 	router.RedirectTrailingSlash = true
 	router.RedirectFixedPath = true
 	router.RemoveExtraSlash = true
 
+	// Must come first: middleware only applies to routes registered after it
+	router.Use(middleware.RequestID())
+	requestMetrics := middleware.NewRequestMetrics()
+	router.Use(middleware.CountRequests(requestMetrics))
+
 	// Health check
 	router.GET(basepath+"/health", func(c *gin.Context) {
 		HealthCheck(c, appConfig)
 	})
+	router.GET(basepath+"/readyz", func(c *gin.Context) {
+		ReadinessCheck(c, pool, dbConfig)
+	})
+	router.GET(basepath+"/metrics", middleware.MetricsAuth(pool, appConfig.MetricsToken), func(c *gin.Context) {
+		Metrics(c, pool, readPool, requestMetrics)
+	})
 
 	// Swagger documentation
 	if !appConfig.DisableSwagger {
@@ -32,7 +51,7 @@ func RegisterRoutes(basepath string, router *gin.Engine, pool *pgxpool.Pool, jwt
 	}
 
 	// v1 routes
-	v1.RegisterRoutes(router.Group(basepath+"/v1"), pool, appConfig, jwtConfig)
+	v1.RegisterRoutes(router.Group(basepath+"/v1"), pool, readPool, appConfig, jwtConfig)
 }
 
 // HealthCheck godoc
@@ -49,3 +68,43 @@ func HealthCheck(c *gin.Context, appConfig config.AppConfig) {
 		App:    "Qashare",
 	})
 }
+
+// ReadinessCheck godoc
+// @Summary Readiness check endpoint
+// @Description Check if the API can serve traffic. Verifies the database is reachable.
+// @Description With deep=true, also verifies that every migration file has been applied, so instances on an old schema are taken out of rotation.
+// @Tags health
+// @Produce json
+// @Param deep query bool false "Also check for pending migrations"
+// @Success 200 {object} models.ReadinessCheck "Server is ready"
+// @Failure 503 {object} models.ReadinessCheck "Database is unreachable or migrations are pending"
+// @Router /readyz [get]
+func ReadinessCheck(c *gin.Context, pool *pgxpool.Pool, dbConfig config.DatabaseConfig) {
+	ctx, cancel := db.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := db.HealthCheck(ctx, pool); err != nil {
+		slog.Warn("Readiness check failed", "error", err)
+		utils.SendJSON(c, http.StatusServiceUnavailable, models.ReadinessCheck{Status: "unavailable", Database: "unreachable"})
+		return
+	}
+
+	if deep, _ := strconv.ParseBool(c.Query("deep")); deep {
+		status, err := db.GetMigrationStatus(ctx, pool, dbConfig.MigrationsDir)
+		if err != nil {
+			slog.Warn("Readiness migration check failed", "error", err)
+			utils.SendJSON(c, http.StatusServiceUnavailable, models.ReadinessCheck{Status: "unavailable", Database: "ok"})
+			return
+		}
+		if status.PendingMigrations > 0 {
+			utils.SendJSON(c, http.StatusServiceUnavailable, models.ReadinessCheck{
+				Status:            "migrations_pending",
+				Database:          "ok",
+				PendingMigrations: status.Pending,
+			})
+			return
+		}
+	}
+
+	utils.SendJSON(c, http.StatusOK, models.ReadinessCheck{Status: "ok", Database: "ok"})
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranaovs/qashare/apperrors"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/routes/middleware"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// ListAttachments godoc
+// @Summary List expense attachments
+// @Description Get the receipt references attached to an expense, oldest first
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Success 200 {array} models.ExpenseAttachment "Returns the expense's attachments"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/attachments [get]
+func (h *ExpensesHandler) ListAttachments(c *gin.Context) {
+	expenseID := middleware.MustGetExpenseID(c)
+
+	attachments, err := db.ListExpenseAttachments(c.Request.Context(), h.pool, expenseID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendData(c, attachments)
+}
+
+// AddAttachment godoc
+// @Summary Attach a receipt to an expense
+// @Description Record a reference to a receipt stored outside the server, either as an absolute http(s) url or a storage object_key (exactly one is required).
+// @Description The content type must be an image type or application/pdf, and size_bytes may not exceed MAX_ATTACHMENT_SIZE. The file itself is never uploaded to or stored by the server.
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Param request body object{url=string,object_key=string,content_type=string,size_bytes=int} true "Attachment reference and metadata"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 201 {object} models.ExpenseAttachment "Returns the created attachment"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body | BAD_ATTACHMENT: Missing or ambiguous reference, unsupported content type, or size too large"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/attachments [post]
+func (h *ExpensesHandler) AddAttachment(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	expenseID := middleware.MustGetExpenseID(c)
+
+	var request struct {
+		URL         *string `json:"url"`
+		ObjectKey   *string `json:"object_key"`
+		ContentType string  `json:"content_type" binding:"required"`
+		SizeBytes   int64   `json:"size_bytes"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
+		return
+	}
+
+	attachment := models.ExpenseAttachment{
+		ExpenseID:  expenseID,
+		SizeBytes:  request.SizeBytes,
+		UploadedBy: &userID,
+	}
+
+	var err error
+	attachment.URL, attachment.ObjectKey, attachment.ContentType, err = utils.ValidateAttachment(
+		request.URL, request.ObjectKey, request.ContentType, request.SizeBytes, h.appConfig.MaxAttachmentSize)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidAttachment: apierrors.ErrInvalidAttachment,
+		}))
+		return
+	}
+
+	if err := db.AddExpenseAttachment(c.Request.Context(), h.pool, &attachment); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusCreated, attachment)
+}
+
+// DeleteAttachment godoc
+// @Summary Remove an expense attachment
+// @Description Remove a receipt reference from an expense (requires being the uploader or the expense creator). The referenced file itself is not deleted.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} object{message=string} "Attachment removed"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid expense or attachment ID"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is neither the uploader nor the expense creator"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist | ATTACHMENT_NOT_FOUND: The expense has no such attachment"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/attachments/{attachment_id} [delete]
+func (h *ExpensesHandler) DeleteAttachment(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	expense := middleware.MustGetExpense(c)
+
+	attachmentID := middleware.MustGetUUIDParam(c, "attachment_id")
+
+	attachment, err := db.GetExpenseAttachment(c.Request.Context(), h.pool, expense.ExpenseID, attachmentID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrAttachmentNotFound,
+		}))
+		return
+	}
+
+	isUploader := attachment.UploadedBy != nil && *attachment.UploadedBy == userID
+	if !isUploader && expense.AddedBy != userID {
+		utils.SendError(c, apierrors.ErrNoPermissions)
+		return
+	}
+
+	if err := db.DeleteExpenseAttachment(c.Request.Context(), h.pool, expense.ExpenseID, attachmentID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrAttachmentNotFound,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "attachment removed")
+}
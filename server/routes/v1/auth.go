@@ -2,6 +2,7 @@ package v1
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
@@ -29,14 +30,17 @@ func NewAuthHandler(pool *pgxpool.Pool, appConfig config.AppConfig, jwtConfig co
 // Register godoc
 // @Summary Register a new user
 // @Description Create a new user account
+// @Description When CREATE_DEFAULT_GROUP is enabled, a personal group named by DEFAULT_GROUP_NAME is created for the new user in the same transaction.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body object{name=string,email=string,password=string} true "User registration details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 202 {object} models.User "User registered, email verification required"
 // @Success 201 {object} models.User "User successfully registered"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format, missing required fields, or JSON parsing error | BAD_NAME: Name contains invalid characters or is too short/long | BAD_EMAIL: Invalid email format | BAD_PASSWORD: Password does not meet requirements (e.g., too short, too weak)"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format, missing required fields, or JSON parsing error | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_EMAIL: Invalid email format | BAD_PASSWORD: Password does not meet requirements (e.g., too short, too weak)"
 // @Failure 409 {object} apierrors.AppError "EMAIL_EXISTS: An account with this email already exists"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database or system error"
 // @Router /v1/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -46,8 +50,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Password string `json:"password"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -57,7 +60,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	user.Name, err = utils.ValidateName(request.Name)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			utils.ErrInvalidName: apierrors.ErrInvalidName,
+			utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+			utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+			utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
 		}))
 		return
 	}
@@ -70,6 +75,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidatePassword(request.Password, h.appConfig.PasswordPolicy); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPassword: apierrors.ErrInvalidPassword,
+		}))
+		return
+	}
+
 	passwordHash, err := utils.HashPassword(request.Password)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
@@ -86,7 +98,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		user.EmailVerified = true
 	}
 
-	verificationToken, err := db.CreateUser(c.Request.Context(), h.pool, &user, h.appConfig.VerifyEmailExpiry)
+	// Give new users a personal group to log solo expenses in
+	var defaultGroup *models.Group
+	if h.appConfig.DefaultGroup {
+		defaultGroup = &models.Group{
+			Name:     h.appConfig.DefaultGroupName,
+			Currency: h.appConfig.DefaultCurrency,
+		}
+	}
+
+	verificationToken, err := db.CreateUser(c.Request.Context(), h.pool, &user, h.appConfig.VerifyEmailExpiry, defaultGroup)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrDuplicateKey: apierrors.ErrEmailAlreadyExists,
@@ -119,6 +140,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} object{message=string} "Email successfully verified"
 // @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Missing token | EMAIL_VERIFICATION_TOKEN_ERROR: Token is invalid, malformed, or not found"
 // @Failure 403 {object} apierrors.AppError "EMAIL_VERIFICATION_TOKEN_EXPIRED: Token has expired"
+// @Failure 409 {object} apierrors.AppError "EMAIL_EXISTS: The token confirms an email change, but another account now owns the new email"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/auth/verify [get]
 func (h *AuthHandler) Verify(c *gin.Context) {
@@ -128,6 +150,81 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 		return
 	}
 
+	h.consumeVerificationToken(c, tokenStr)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Consume an email verification token and mark the owning user's email as verified. A token sent for an email change also replaces the user's email with the pending one.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body object{token=string} true "Email verification token"
+// @Success 200 {object} object{message=string} "Email successfully verified"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Missing token | EMAIL_VERIFICATION_TOKEN_ERROR: Token is invalid, malformed, or not found"
+// @Failure 403 {object} apierrors.AppError "EMAIL_VERIFICATION_TOKEN_EXPIRED: Token has expired"
+// @Failure 409 {object} apierrors.AppError "EMAIL_EXISTS: The token confirms an email change, but another account now owns the new email"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
+// @Failure 500 {object} apierrors.AppError "Internal server error"
+// @Router /v1/auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var request struct {
+		Token string `json:"token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("missing token"))
+		return
+	}
+
+	h.consumeVerificationToken(c, request.Token)
+}
+
+// SendVerification godoc
+// @Summary Send verification email
+// @Description Issue a new email verification token for the authenticated user and email it to them. Any previously issued token is invalidated. If an email change is pending, the link is sent to the new email instead.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{message=string} "Verification email sent"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Email is already verified and no change is pending"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "USER_NOT_FOUND: User does not exist"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
+// @Failure 500 {object} apierrors.AppError "Internal server error"
+// @Failure 503 {object} apierrors.AppError "SERVICE_UNAVAILABLE: Email verification is not enabled on this server"
+// @Router /v1/auth/send-verification [post]
+func (h *AuthHandler) SendVerification(c *gin.Context) {
+	if !h.appConfig.Verification {
+		utils.SendError(c, apierrors.ErrServiceUnavailable.Msg("email verification is not enabled"))
+		return
+	}
+
+	userID := middleware.MustGetUserID(c)
+
+	token, email, err := db.CreateVerificationToken(c.Request.Context(), h.pool, userID, h.appConfig.VerifyEmailExpiry)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrUserNotFound,
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	err = utils.SendVerificationEmail(email, token, h.appConfig.VerifyEmailExpiry)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrEmailSendFailed: apierrors.ErrInternalServer,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "verification email sent")
+}
+
+// consumeVerificationToken verifies the email owning tokenStr and writes the response.
+func (h *AuthHandler) consumeVerificationToken(c *gin.Context, tokenStr string) {
 	token, err := uuid.Parse(tokenStr)
 	if err != nil {
 		utils.SendError(c, apierrors.ErrEmailVerificationTokenError)
@@ -139,6 +236,7 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound:     apierrors.ErrEmailVerificationTokenError,
 			db.ErrExpiredToken: apierrors.ErrEmailVerificationTokenExpired,
+			db.ErrDuplicateKey: apierrors.ErrEmailAlreadyExists,
 		}))
 		return
 	}
@@ -146,17 +244,50 @@ func (h *AuthHandler) Verify(c *gin.Context) {
 	utils.SendOK(c, "email verified")
 }
 
+// GetGuestClaimStatus godoc
+// @Summary Get guest claim status for an email
+// @Description Check whether an unclaimed guest account exists for an email, and whether a claim (a registration with that email) is waiting on email verification.
+// @Description To limit enumeration, the endpoint is rate limited and unknown emails are indistinguishable from verified accounts: both report false for every field.
+// @Tags auth
+// @Produce json
+// @Param email query string true "Email address"
+// @Success 200 {object} models.GuestClaimStatus "Claim status for the email"
+// @Failure 400 {object} apierrors.AppError "BAD_EMAIL: Missing or malformed email"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded"
+// @Failure 500 {object} apierrors.AppError "Internal server error"
+// @Router /v1/auth/guest/claim/status [get]
+func (h *AuthHandler) GetGuestClaimStatus(c *gin.Context) {
+	email, err := utils.ValidateEmail(c.Query("email"))
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidEmail: apierrors.ErrInvalidEmail,
+		}))
+		return
+	}
+
+	status, err := db.GetGuestClaimStatus(c.Request.Context(), h.pool, email)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, status)
+}
+
 // Login godoc
 // @Summary Login user
 // @Description Authenticate user and return access and refresh tokens
+// @Description Users with unverified emails can log in, but endpoints that require a verified email return EMAIL_NOT_VERIFIED
+// @Description With JWT_MAX_SESSIONS set, logging in beyond that many active sessions revokes the refresh token of the oldest one
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body object{email=string,password=string} true "User login credentials"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.TokenResponse "Returns access and refresh tokens"
 // @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format or missing required fields | BAD_EMAIL: Invalid email format"
 // @Failure 401 {object} apierrors.AppError "BAD_CREDENTIALS: Email or password is incorrect"
-// @Failure 403 {object} apierrors.AppError "EMAIL_NOT_VERIFIED: The email address has not been verified"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
@@ -165,8 +296,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		Password string `json:"password" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -180,7 +310,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	password := request.Password
 
-	userID, savedPassword, emailVerified, err := db.GetUserCredentials(c.Request.Context(), h.pool, email)
+	userID, savedPassword, _, err := db.GetUserCredentials(c.Request.Context(), h.pool, email)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound: apierrors.ErrBadCredentials,
@@ -193,11 +323,6 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	if h.appConfig.Verification && !emailVerified {
-		utils.SendError(c, apierrors.ErrEmailNotVerified)
-		return
-	}
-
 	refreshToken, tokenID, expiresAt, err := utils.GenerateRefreshToken(userID, h.jwtConfig)
 	if err != nil {
 		utils.SendError(c, err)
@@ -210,7 +335,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	err = db.StoreToken(c.Request.Context(), h.pool, tokenID, userID, expiresAt)
+	err = db.StoreToken(c.Request.Context(), h.pool, tokenID, userID, expiresAt, h.jwtConfig.MaxSessions)
 	if err != nil {
 		utils.SendError(c, err)
 		return
@@ -230,9 +355,11 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body object{refresh_token=string} true "Refresh token"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.TokenResponse "Returns new access and refresh tokens"
 // @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Missing refresh token | INVALID_REFRESH_TOKEN: Refresh token is invalid or already used"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_REFRESH_TOKEN: Refresh token has expired"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/auth/refresh [post]
 func (h *AuthHandler) Refresh(c *gin.Context) {
@@ -240,8 +367,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -340,3 +466,32 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 
 	utils.SendOK(c, "logged out from all devices")
 }
+
+// Introspect godoc
+// @Summary Introspect the access token
+// @Description Return the decoded claims of the access token used for the request, without touching the database. Clients can use expires_in to refresh the token before it expires.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TokenIntrospection "Returns the token claims"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is missing, malformed or invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Router /v1/auth/introspect [get]
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	claims := middleware.MustGetClaims(c)
+
+	introspection := models.TokenIntrospection{
+		UserID:    middleware.MustGetUserID(c),
+		SessionID: middleware.MustGetSessionID(c),
+		TokenID:   claims.ID,
+	}
+	if claims.IssuedAt != nil {
+		introspection.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if claims.ExpiresAt != nil {
+		introspection.ExpiresAt = claims.ExpiresAt.Unix()
+		introspection.ExpiresIn = max(int64(time.Until(claims.ExpiresAt.Time).Seconds()), 0)
+	}
+
+	utils.SendJSON(c, http.StatusOK, introspection)
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// bindJSON decodes the request body into obj and validates it like ShouldBindJSON.
+// In strict mode, fields that obj does not declare are rejected instead of silently dropped.
+// Strict mode is enabled by the strict query parameter, falling back to strictDefault (STRICT_JSON).
+// Sends ErrBadRequest, naming the field when it is unknown, and returns false on failure.
+func bindJSON(c *gin.Context, obj any, strictDefault bool) bool {
+	strict, ok := parseBoolQuery(c, "strict", strictDefault)
+	if !ok {
+		return false
+	}
+
+	if !strict {
+		if err := c.ShouldBindJSON(obj); err != nil {
+			utils.SendError(c, apierrors.ErrBadRequest)
+			return false
+		}
+		return true
+	}
+
+	if c.Request.Body == nil {
+		utils.SendError(c, apierrors.ErrBadRequest)
+		return false
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		// encoding/json has no typed error for unknown fields, only this message
+		if field, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+			utils.SendError(c, apierrors.ErrBadRequest.Msgf("unknown field %s", field))
+			return false
+		}
+		utils.SendError(c, apierrors.ErrBadRequest)
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest)
+		return false
+	}
+	return true
+}
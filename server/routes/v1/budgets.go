@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranaovs/qashare/apperrors"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/routes/middleware"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// GetBudget godoc
+// @Summary Get the group budget
+// @Description Get the group's budget together with the spend of the current period (calendar week, month or year in UTC). Spend is the sum of live expenses transacted in the period, settlements excluded.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} models.BudgetStatus "Returns the budget and the current period's spend"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "BUDGET_NOT_FOUND: The group has no budget set"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/budget [get]
+func (h *GroupsHandler) GetBudget(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	status, err := db.GetBudgetStatus(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrBudgetNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, status)
+}
+
+// SetBudget godoc
+// @Summary Set the group budget
+// @Description Create or replace the group's spending budget (requires group admin permission). period defaults to month.
+// @Description Expenses that leave the current period over budget are still created; their response carries over_budget=true.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body object{amount=number,period=string} true "Budget amount in the group's base currency and period (week, month or year)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} models.BudgetStatus "Returns the budget and the current period's spend"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, amount is not positive, or period is unknown"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/budget [put]
+func (h *GroupsHandler) SetBudget(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	var req struct {
+		Amount float64 `json:"amount" binding:"required"`
+		Period string  `json:"period"`
+	}
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
+		return
+	}
+	if req.Period == "" {
+		req.Period = models.BudgetPeriodMonth
+	}
+
+	budget := models.GroupBudget{GroupID: groupID, Amount: req.Amount, Period: req.Period}
+	if err := db.SetGroupBudget(c.Request.Context(), h.pool, &budget, userID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+			db.ErrNotFound:     apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	status, err := db.GetBudgetStatus(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, status)
+}
@@ -2,9 +2,11 @@ package v1
 
 import (
 	"bytes"
-	"math"
 	"net/http"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
@@ -19,291 +21,1207 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// autoBalanceFactor bounds how far split totals may drift from the expense amount
+// (as a multiple of SplitTolerance) before autobalance refuses to correct them.
+const autoBalanceFactor = 5
+
 type ExpensesHandler struct {
 	pool      *pgxpool.Pool
 	appConfig config.AppConfig
 }
 
-func NewExpensesHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *ExpensesHandler {
-	return &ExpensesHandler{pool: pool, appConfig: appConfig}
+func NewExpensesHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *ExpensesHandler {
+	return &ExpensesHandler{pool: pool, appConfig: appConfig}
+}
+
+// GetExpenses godoc
+// @Summary List group expenses
+// @Description Get all expenses of a group, newest first. By default expenses are ordered by when they were added; sort=transacted_at orders them by when they took place instead, and amount or title (case-insensitive) are also available.
+// @Description Settlements are excluded unless is_settlement=true, which lists only settlements.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param sort query string false "Order by created_at, transacted_at, amount or title (default created_at)" Enums(created_at, transacted_at, amount, title)
+// @Param order query string false "Sort direction (default desc)" Enums(asc, desc)
+// @Param added_by query string false "Only expenses added by this user ID"
+// @Param is_settlement query bool false "List settlements instead of regular expenses (default false)"
+// @Success 200 {array} models.Expense "Returns list of all expenses in the group. If an expense is is_private, only the splits related to the authenticated user will be included in the response (creator or involved in splits)"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Unknown sort key or order, invalid added_by or is_settlement value"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses [get]
+func (h *GroupsHandler) GetExpenses(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	opts := db.ExpenseListOptions{Sort: c.DefaultQuery("sort", db.ExpenseSortCreatedAt)}
+	if !db.IsExpenseSort(opts.Sort) {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("unknown sort key: %s", opts.Sort))
+		return
+	}
+
+	switch order := c.DefaultQuery("order", "desc"); order {
+	case "asc":
+		opts.Ascending = true
+	case "desc":
+	default:
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("order must be asc or desc, got %s", order))
+		return
+	}
+
+	if addedBy := c.Query("added_by"); addedBy != "" {
+		id, err := uuid.Parse(addedBy)
+		if err != nil {
+			utils.SendError(c, apierrors.ErrBadRequest.Msg("invalid added_by user ID format"))
+			return
+		}
+		opts.AddedBy = &id
+	}
+
+	var ok bool
+	opts.IsSettlement, ok = parseBoolQuery(c, "is_settlement", false)
+	if !ok {
+		return
+	}
+
+	expenses, err := db.GetExpenses(c.Request.Context(), h.readPool, groupID, userID, opts)
+	if err != nil {
+		utils.SendError(c, err) // Shouln't send any error as everything is validated in the middleware
+		return
+	}
+	utils.SendData(c, expenses)
+}
+
+// GetDeletedExpenses godoc
+// @Summary List deleted group expenses
+// @Description Get the expenses of a group that are in the trash, most recently deleted first. Private expenses are only listed for their creator and split participants.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {array} models.Expense "Returns the deleted expenses, with deleted_at set"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses/trash [get]
+func (h *GroupsHandler) GetDeletedExpenses(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+	expenses, err := db.GetDeletedExpenses(c.Request.Context(), h.pool, groupID, userID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+	utils.SendData(c, expenses)
+}
+
+// SearchExpenses godoc
+// @Summary Search group expenses
+// @Description Search the expenses of a group by text, amount and creation date. All filters are optional and combined; empty values mean no constraint.
+// @Description q matches the title or description (case-insensitive substring). Amount and date ranges are inclusive; dates are unix seconds.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param q query string false "Text to search for in title and description"
+// @Param min_amount query number false "Minimum amount (inclusive)"
+// @Param max_amount query number false "Maximum amount (inclusive)"
+// @Param from query int false "Created at or after (unix seconds)"
+// @Param to query int false "Created at or before (unix seconds)"
+// @Success 200 {array} models.Expense "Returns matching expenses, newest first"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid filter value or inverted amount/date range"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses/search [get]
+func (h *GroupsHandler) SearchExpenses(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	filter := db.ExpenseFilter{Query: strings.TrimSpace(c.Query("q"))}
+	var ok bool
+	if filter.MinAmount, ok = parseFloatQuery(c, "min_amount"); !ok {
+		return
+	}
+	if filter.MaxAmount, ok = parseFloatQuery(c, "max_amount"); !ok {
+		return
+	}
+	if filter.From, ok = parseInt64Query(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = parseInt64Query(c, "to"); !ok {
+		return
+	}
+
+	expenses, err := db.SearchExpenses(c.Request.Context(), h.readPool, groupID, userID, filter)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+	utils.SendData(c, expenses)
+}
+
+// GetCategories godoc
+// @Summary List categories in a group
+// @Description Get the distinct categories used by the group's expenses, merged with the server's EXPENSE_CATEGORIES allow-list, sorted alphabetically. Categories are lowercase. Useful for populating a category filter.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {array} string "Returns the categories"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/categories [get]
+func (h *GroupsHandler) GetCategories(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	categories, err := db.GetGroupCategories(c.Request.Context(), h.readPool, groupID, userID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	for _, allowed := range h.appConfig.ExpenseCategories {
+		category := strings.ToLower(allowed)
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	slices.Sort(categories)
+
+	utils.SendData(c, categories)
+}
+
+// GetExpenseBySeq godoc
+// @Summary Get expense by number
+// @Description Get an expense by its per-group expense number (the seq field), e.g. expense #14
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param seq path int true "Expense number within the group"
+// @Success 200 {object} models.ExpenseDetails "Returns expense details including all splits"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid expense number"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The group has no expense with this number"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses/seq/{seq} [get]
+func (h *GroupsHandler) GetExpenseBySeq(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	seq, err := strconv.ParseInt(c.Param("seq"), 10, 64)
+	if err != nil || seq < 1 {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("invalid expense number"))
+		return
+	}
+
+	expense, err := db.GetExpenseBySeq(c.Request.Context(), h.pool, groupID, seq)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	// Settlements and private expenses are hidden the same way as on /expenses/{id}
+	if expense.IsSettlement || !canViewExpense(expense, userID) {
+		utils.SendError(c, apierrors.ErrExpenseNotFound)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, expense)
+}
+
+// canViewExpense reports whether a group member can see the expense.
+// Private expenses are only visible to the creator and split participants.
+func canViewExpense(expense models.ExpenseDetails, userID uuid.UUID) bool {
+	if !expense.IsPrivate || expense.AddedBy == userID {
+		return true
+	}
+	for _, split := range expense.Splits {
+		if split.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Create godoc
+// @Summary Create a new expense
+// @Description Create a new expense with splits for a group. The logged in user will be set as the AddedBy user.
+// @Description With split_method=equal, only the paid splits are sent and the owed splits are computed by dividing the amount equally among participants (remaining cents go to the first participants).
+// @Description With split_method=percentage, only the paid splits are sent and the owed splits are computed from weights, a map of user ID to percentage of the amount that must sum to 100 (the rounding remainder goes to the largest share).
+// @Description With split_method=shares, only the paid splits are sent and the owed splits are computed from shares, a map of user ID to a positive integer share (e.g. 2:1:1); leftover cents go to the largest fractional remainders, ties by user ID.
+// @Description By default payers listed in participants owe a share like everyone else. With payer_included_in_split=false, payers are left out of the owed side: they pay but owe nothing. When omitted, the server's PAYER_INCLUDED_IN_SPLIT setting is used.
+// @Description With autobalance=true, owed totals that are off by a few cents (up to 5x the split tolerance) are corrected by adjusting the largest owed split instead of being rejected. Paid splits are never adjusted.
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param autobalance query bool false "Absorb small rounding differences into the largest owed split"
+// @Param request body models.ExpenseCreate true "Expense details with splits"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 201 {object} models.ExpenseCreated "Expense successfully created with splits; over_budget is set when the group's current budget period is now exceeded"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: No splits provided, split totals do not match expense amount, split validation failed, or splits could not be computed | BAD_REQUEST: transacted_at is more than a day in the future"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses [post]
+func (h *ExpensesHandler) Create(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	autoBalance, ok := parseBoolQuery(c, "autobalance", false)
+	if !ok {
+		return
+	}
+
+	var request models.ExpenseCreate
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
+		return
+	}
+
+	var err error
+	expense := request.ExpenseDetails
+	expense.AddedBy = userID
+	expense.IsSettlement = false
+	expense.GroupID = groupID
+
+	expense.Title, err = utils.ValidateExpenseTitle(expense.Title, h.appConfig.AllowEmptyTitle, h.appConfig.DefaultTitle)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTitle: apierrors.ErrInvalidTitle,
+		}))
+		return
+	}
+
+	expense.PaymentMethod, err = utils.ValidatePaymentMethod(expense.PaymentMethod, h.appConfig.PaymentMethods)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPaymentMethod: apierrors.ErrInvalidPaymentMethod,
+		}))
+		return
+	}
+
+	expense.Category, err = utils.ValidateCategory(expense.Category, h.appConfig.ExpenseCategories)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidCategory: apierrors.ErrInvalidCategory,
+		}))
+		return
+	}
+
+	if err := utils.ValidateTransactedAt(expense.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	if !h.checkRequiredDescription(c, groupID, expense.Description) {
+		return
+	}
+
+	includePayers := h.appConfig.PayerIncludedInSplit
+	if request.PayerIncludedInSplit != nil {
+		includePayers = *request.PayerIncludedInSplit
+	}
+
+	splits, err := utils.ComputeSplits(request.SplitMethod, expense.Amount, expense.Splits, request.Participants, request.Weights, request.Shares, includePayers)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
+		return
+	}
+	expense.Splits = splits
+
+	if autoBalance && !expense.IsIncompleteAmount && !expense.IsIncompleteSplit {
+		utils.AutoBalanceSplits(expense.Splits, expense.Amount, h.appConfig.SplitTolerance*autoBalanceFactor)
+	}
+
+	if err := utils.ValidateSplits(expense.Splits, expense.Amount, h.appConfig.SplitTolerance, expense.IsIncompleteAmount, expense.IsIncompleteSplit); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
+		return
+	}
+
+	splitUserIDs := make([]uuid.UUID, 0, len(expense.Splits))
+	for _, s := range expense.Splits {
+		splitUserIDs = append(splitUserIDs, s.UserID)
+	}
+
+	uniqueUserIDs := utils.GetUniqueUserIDs(splitUserIDs)
+
+	if err := db.AllMembersOfGroup(c.Request.Context(), h.pool, uniqueUserIDs, expense.GroupID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotInGroup,
+		}))
+		return
+	}
+
+	err = db.CreateExpense(c.Request.Context(), h.pool, &expense)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	// Sort splits to match consistent ordering (is_paid DESC, user_id ASC)
+	SortExpenseSplits(expense.Splits)
+
+	utils.SendJSON(c, http.StatusCreated, models.ExpenseCreated{
+		ExpenseDetails: expense,
+		OverBudget:     h.overBudget(c, expense),
+	})
+}
+
+// overBudget reports whether expense counts towards its group's current budget period and the
+// period is now over budget. The expense is already stored, so a failed budget lookup is only logged.
+func (h *ExpensesHandler) overBudget(c *gin.Context, expense models.ExpenseDetails) bool {
+	status, err := db.GetBudgetStatus(c.Request.Context(), h.pool, expense.GroupID)
+	if err != nil {
+		if !db.IsNotFound(err) {
+			utils.LogWarn(c.Request.Context(), "failed to check group budget", "group_id", expense.GroupID, "error", err)
+		}
+		return false
+	}
+	if !status.OverBudget || expense.TransactedAt == nil {
+		return false
+	}
+	return *expense.TransactedAt >= status.PeriodStart && *expense.TransactedAt < status.PeriodEnd
+}
+
+// Get godoc
+// @Summary Get expense details
+// @Description Get detailed information about an expense including splits
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Param expand query string false "Set to users to embed the name, email, guest flag and joined_at of the users involved" Enums(users)
+// @Success 200 {object} models.ExpenseWithUsers "Returns expense details including all splits; users is only present with expand=users"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid expand value"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id} [get]
+func (h *ExpensesHandler) Get(c *gin.Context) {
+	// Expense is already fetched and authorized by middleware
+	expense := middleware.MustGetExpense(c)
+
+	expand, ok := parseExpandUsers(c)
+	if !ok {
+		return
+	}
+	if !expand {
+		utils.SendJSON(c, http.StatusOK, expense)
+		return
+	}
+
+	userIDs := []uuid.UUID{expense.AddedBy}
+	for _, split := range expense.Splits {
+		userIDs = append(userIDs, split.UserID)
+	}
+	users, err := db.GetGroupUsersByIDs(c.Request.Context(), h.pool, expense.GroupID, utils.GetUniqueUserIDs(userIDs))
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, models.ExpenseWithUsers{ExpenseDetails: expense, Users: users})
+}
+
+// GetContext godoc
+// @Summary Get an expense with its context
+// @Description Get an expense together with the group's name and currency, the names of everyone with a split, and what the authenticated user paid and owes for it. Meant for an expense detail screen, in place of separate expense, group and user lookups.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Success 200 {object} models.ExpenseContext "Returns the expense with its group and viewer context"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group | GROUP_NOT_FOUND: The expense's group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/context [get]
+func (h *ExpensesHandler) GetContext(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	// Expense is already fetched and authorized by middleware
+	expense := middleware.MustGetExpense(c)
+
+	result := models.ExpenseContext{Expense: expense}
+	userIDs := make([]uuid.UUID, 0, len(expense.Splits))
+	for _, split := range expense.Splits {
+		if !slices.Contains(userIDs, split.UserID) {
+			userIDs = append(userIDs, split.UserID)
+		}
+		if split.UserID != userID {
+			continue
+		}
+		if split.IsPaid {
+			result.Paid += split.Amount
+		} else {
+			result.Owed += split.Amount
+		}
+	}
+	result.Paid = utils.RoundMoney(result.Paid)
+	result.Owed = utils.RoundMoney(result.Owed)
+
+	var err error
+	result.GroupName, result.Currency, result.Participants, err = db.GetGroupLabels(c.Request.Context(), h.pool, expense.GroupID, userIDs)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, result)
+}
+
+// GetRemaining godoc
+// @Summary Get unassigned amount of an expense
+// @Description Get how much of an expense is still not assigned to participants (amount minus the sum of owed splits), along with the amount assigned to each participant. Useful for finishing incomplete expenses.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Success 200 {object} models.ExpenseRemaining "Returns the assigned and remaining amounts"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/remaining [get]
+func (h *ExpensesHandler) GetRemaining(c *gin.Context) {
+	// Expense is already fetched and authorized by middleware
+	expense := middleware.MustGetExpense(c)
+	utils.SendJSON(c, http.StatusOK, utils.RemainingAmount(expense))
+}
+
+// Update godoc
+// @Summary Update an expense
+// @Description Update an existing expense (requires being the expense creator). Immutable fields will be ignored if included in the request body.
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Param request body models.ExpenseDetails true "Updated expense details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} models.ExpenseDetails "Returns updated expense with all fields"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: No splits provided or split totals do not match expense amount | BAD_REQUEST: transacted_at is more than a day in the future"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 409 {object} apierrors.AppError "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id} [put]
+func (h *ExpensesHandler) Update(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+	expense := middleware.MustGetExpense(c)
+
+	if !h.checkNotSettled(c, expense.ExpenseID) {
+		return
+	}
+
+	var payload models.ExpenseDetails
+	if !bindJSON(c, &payload, h.appConfig.StrictJSON) {
+		return
+	}
+
+	// Strip immutable fields (silently ignore if client sends them)
+	if err := utils.StripImmutableFields(&payload); err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest)
+		return
+	}
+
+	title, err := utils.ValidateExpenseTitle(payload.Title, h.appConfig.AllowEmptyTitle, h.appConfig.DefaultTitle)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTitle: apierrors.ErrInvalidTitle,
+		}))
+		return
+	}
+	payload.Title = title
+
+	payload.PaymentMethod, err = utils.ValidatePaymentMethod(payload.PaymentMethod, h.appConfig.PaymentMethods)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPaymentMethod: apierrors.ErrInvalidPaymentMethod,
+		}))
+		return
+	}
+
+	payload.Category, err = utils.ValidateCategory(payload.Category, h.appConfig.ExpenseCategories)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidCategory: apierrors.ErrInvalidCategory,
+		}))
+		return
+	}
+
+	if err := utils.ValidateTransactedAt(payload.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	if !h.checkRequiredDescription(c, groupID, payload.Description) {
+		return
+	}
+
+	if err := utils.ValidateSplits(payload.Splits, payload.Amount, h.appConfig.SplitTolerance, payload.IsIncompleteAmount, payload.IsIncompleteSplit); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
+		return
+	}
+
+	splitUserIDs := make([]uuid.UUID, 0, len(payload.Splits))
+	for _, s := range payload.Splits {
+		splitUserIDs = append(splitUserIDs, s.UserID)
+	}
+
+	if err := db.AllMembersOfGroup(c.Request.Context(), h.pool, utils.GetUniqueUserIDs(splitUserIDs), groupID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotInGroup,
+		}))
+		return
+	}
+
+	// Restore immutable fields from middleware-fetched expense (no extra DB fetch needed)
+	utils.RestoreImmutableFields(&payload.Expense, &expense.Expense)
+
+	// Preserve existing transacted_at when client omits it (nil = not provided)
+	if payload.TransactedAt == nil {
+		payload.TransactedAt = expense.TransactedAt
+	}
+
+	if err := db.UpdateExpense(c.Request.Context(), h.pool, &payload, middleware.MustGetUserID(c)); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	// Sort splits to match consistent ordering (is_paid DESC, user_id ASC)
+	SortExpenseSplits(payload.Splits)
+
+	utils.SendJSON(c, http.StatusOK, payload)
+}
+
+// Delete godoc
+// @Summary Delete an expense
+// @Description Move an expense to the trash (requires being the expense creator or group admin).
+// @Description Deleted expenses no longer count towards balances, can be restored, and are permanently removed after the configured retention period.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Success 200 {object} map[string]string "Returns success message"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 409 {object} apierrors.AppError "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id} [delete]
+func (h *ExpensesHandler) Delete(c *gin.Context) {
+	expense := middleware.MustGetExpense(c)
+
+	if !h.checkNotSettled(c, expense.ExpenseID) {
+		return
+	}
+
+	if err := db.DeleteExpense(c.Request.Context(), h.pool, expense.ExpenseID, middleware.MustGetUserID(c)); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "expense deleted")
+}
+
+// DeleteAll godoc
+// @Summary Delete all expenses of a group
+// @Description Move every expense of the group to the trash in one step (requires group admin permission). Settlements are kept, and with LOCK_SETTLED_EXPENSES so are expenses a settlement covers.
+// @Description The deleted expenses can be restored from the trash individually until the retention period ends. Requires confirm=true to guard against accidental calls.
+// @Tags expenses
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param confirm query bool true "Must be true"
+// @Success 200 {object} object{message=string,deleted=int} "Returns the number of expenses deleted"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: confirm=true is missing"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/expenses [delete]
+func (h *ExpensesHandler) DeleteAll(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	confirm, ok := parseBoolQuery(c, "confirm", false)
+	if !ok {
+		return
+	}
+	if !confirm {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("deleting all expenses requires confirm=true"))
+		return
+	}
+
+	deleted, err := db.DeleteGroupExpenses(c.Request.Context(), h.pool, groupID, userID, h.appConfig.LockSettledExpenses)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"message": "expenses deleted",
+		"deleted": deleted,
+	})
+}
+
+// UpdateSplitPaid godoc
+// @Summary Move a split to the paid or owed side
+// @Description Set whether a user's split in an expense is on the paid side (is_paid=true) or the owed side, keeping its amount, without resending the whole expense.
+// @Description Only the user the split belongs to, the expense creator or the group admin may change it. Moving a split always unbalances a complete expense, so it is only allowed on expenses flagged incomplete (is_incomplete_amount or is_incomplete_split); to record that a debtor paid their share of a complete expense, create a settlement instead.
+// @Description A user with splits on both sides cannot have one moved on its own. A split already on the requested side is returned unchanged.
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Expense ID"
+// @Param user_id path string true "User ID of the split"
+// @Param request body object{is_paid=bool} true "Side to move the split to"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} models.ExpenseDetails "Returns the updated expense"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or user ID, the user has splits on both sides, or the expense is complete | INVALID_SPLIT: Split totals would no longer match the expense amount"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the split's user, the expense creator or the group admin"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist | USER_NOT_FOUND: The user has no split in the expense"
+// @Failure 409 {object} apierrors.AppError "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES)"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/{id}/splits/{user_id} [patch]
+func (h *ExpensesHandler) UpdateSplitPaid(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	expense := middleware.MustGetExpense(c)
+
+	splitUserID := middleware.MustGetUUIDParam(c, "user_id")
+
+	var request struct {
+		IsPaid *bool `json:"is_paid" binding:"required"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
+		return
+	}
+	isPaid := *request.IsPaid
+
+	if userID != splitUserID && userID != expense.AddedBy {
+		creatorID, err := db.GetGroupCreator(c.Request.Context(), h.pool, expense.GroupID)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				db.ErrNotFound: apierrors.ErrGroupNotFound,
+			}))
+			return
+		}
+		if creatorID != userID {
+			utils.SendError(c, apierrors.ErrNoPermissions.Msg("only the split's user, the expense creator or the group admin can change it"))
+			return
+		}
+	}
+
+	// Find the user's split before deciding anything: a user with splits on both sides
+	// has nothing that can be moved on its own
+	target := -1
+	for i, split := range expense.Splits {
+		if split.UserID != splitUserID {
+			continue
+		}
+		if target != -1 {
+			utils.SendError(c, apierrors.ErrBadRequest.Msg("the user already has a split on both sides; update the expense instead"))
+			return
+		}
+		target = i
+	}
+	if target == -1 {
+		utils.SendError(c, apierrors.ErrUserNotFound.Msg("the user has no split in this expense"))
+		return
+	}
+	if expense.Splits[target].IsPaid == isPaid {
+		utils.SendJSON(c, http.StatusOK, expense)
+		return
+	}
+
+	// Moving a split adds its amount to one side and takes it from the other, so a complete
+	// expense can never stay balanced; a debtor's share is settled with a settlement instead
+	if !expense.IsIncompleteAmount && !expense.IsIncompleteSplit {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("splits of a complete expense cannot be moved; record a settlement to mark a share as paid"))
+		return
+	}
+
+	splits := slices.Clone(expense.Splits)
+	splits[target].IsPaid = isPaid
+
+	if !h.checkNotSettled(c, expense.ExpenseID) {
+		return
+	}
+
+	if err := utils.ValidateSplits(splits, expense.Amount, h.appConfig.SplitTolerance, expense.IsIncompleteAmount, expense.IsIncompleteSplit); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
+		return
+	}
+
+	if err := db.UpdateSplitPaidStatus(c.Request.Context(), h.pool, expense.ExpenseID, splitUserID, isPaid, userID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrExpenseNotFound,
+			db.ErrDuplicateKey: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	updated, err := db.GetExpense(c.Request.Context(), h.pool, expense.ExpenseID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	SortExpenseSplits(updated.Splits)
+	utils.SendJSON(c, http.StatusOK, updated)
 }
 
-// GetExpenses godoc
-// @Summary List group expenses
-// @Description Get all expenses of a group
+// Finalize godoc
+// @Summary Mark an incomplete expense as complete
+// @Description Clear the is_incomplete_amount and is_incomplete_split flags of a draft expense (requires being the expense creator or group admin).
+// @Description The expense must have a positive amount, and its paid and owed splits must each sum to it within SPLIT_TOLERANCE; otherwise nothing changes. Finalizing an expense that is already complete returns it unchanged.
 // @Tags expenses
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "Group ID"
-// @Success 200 {array} models.Expense "Returns list of all expenses in the group. If an expense is is_private, only the splits related to the authenticated user will be included in the response (creator or involved in splits)"
+// @Param id path string true "Expense ID"
+// @Success 200 {object} models.ExpenseDetails "Returns the completed expense"
+// @Failure 400 {object} apierrors.AppError "INVALID_AMOUNT: The expense has no amount yet | INVALID_SPLIT: Split totals do not match the expense amount"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
-// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
-// @Router /v1/groups/{id}/expenses [get]
-func (h *GroupsHandler) GetExpenses(c *gin.Context) {
+// @Router /v1/expenses/{id}/finalize [post]
+func (h *ExpensesHandler) Finalize(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
-	groupID := middleware.MustGetGroupID(c)
-	expenses, err := db.GetExpenses(c.Request.Context(), h.pool, groupID, userID)
+	expense := middleware.MustGetExpense(c)
+
+	err := db.FinalizeExpense(c.Request.Context(), h.pool, expense.ExpenseID, userID, h.appConfig.SplitTolerance)
 	if err != nil {
-		utils.SendError(c, err) // Shouln't send any error as everything is validated in the middleware
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:        apierrors.ErrExpenseNotFound,
+			db.ErrInvalidInput:    apierrors.ErrInvalidAmount,
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
 		return
 	}
-	utils.SendData(c, expenses)
+
+	updated, err := db.GetExpense(c.Request.Context(), h.pool, expense.ExpenseID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	SortExpenseSplits(updated.Splits)
+	utils.SendJSON(c, http.StatusOK, updated)
 }
 
-// Create godoc
-// @Summary Create a new expense
-// @Description Create a new expense with splits for a group. The logged in user will be set as the AddedBy user.
+// PreviewSplits godoc
+// @Summary Preview computed splits
+// @Description Compute the splits an expense would be stored with, without creating anything. Takes the same split fields as expense creation (amount, split_method, paid splits, participants, weights, shares, payer_included_in_split) and returns exactly the splits the server would store, including how the rounding remainder is distributed.
+// @Description The splits are checked like on creation, so a preview that succeeds will also be accepted on submit (apart from group membership, which is checked against the group on creation).
 // @Tags expenses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "Group ID"
-// @Param request body models.ExpenseDetails true "Expense details with splits"
-// @Success 201 {object} models.ExpenseDetails "Expense successfully created with splits"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, missing required fields, or no splits provided | INVALID_SPLIT: Split totals do not match expense amount or split validation failed"
+// @Param request body models.SplitPreview true "Split input"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {array} models.ExpenseSplit "Returns the computed splits, paid first, then by user ID"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body | INVALID_SPLIT: Splits could not be computed or do not add up to the amount"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group"
-// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
-// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
-// @Router /v1/groups/{id}/expenses [post]
-func (h *ExpensesHandler) Create(c *gin.Context) {
-	userID := middleware.MustGetUserID(c)
-	groupID := middleware.MustGetGroupID(c)
-
-	var expense models.ExpenseDetails
-	if err := c.ShouldBindJSON(&expense); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Router /v1/expenses/preview-splits [post]
+func (h *ExpensesHandler) PreviewSplits(c *gin.Context) {
+	var request models.SplitPreview
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
-	expense.AddedBy = userID
-	expense.IsSettlement = false
-	expense.GroupID = groupID
+	includePayers := h.appConfig.PayerIncludedInSplit
+	if request.PayerIncludedInSplit != nil {
+		includePayers = *request.PayerIncludedInSplit
+	}
 
-	if len(expense.Splits) == 0 {
-		utils.SendError(c, apierrors.ErrBadRequest.Msg("no splits provided"))
+	splits, err := utils.ComputeSplits(request.SplitMethod, request.Amount, request.Splits, request.Participants, request.Weights, request.Shares, includePayers)
+	if err == nil {
+		err = utils.ValidateSplits(splits, request.Amount, h.appConfig.SplitTolerance, false, false)
+	}
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
 		return
 	}
 
-	splitUserIDs := make([]uuid.UUID, 0, len(expense.Splits))
-	var paidTotal, owedTotal float64
-	for _, s := range expense.Splits {
-		if s.Amount <= 0 {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("split amounts must be positive"))
-			return
-		}
-		splitUserIDs = append(splitUserIDs, s.UserID)
-		if s.IsPaid {
-			paidTotal += s.Amount
-		} else {
-			owedTotal += s.Amount
-		}
-	}
+	SortExpenseSplits(splits)
+	utils.SendJSON(c, http.StatusOK, splits)
+}
 
-	uniqueUserIDs := utils.GetUniqueUserIDs(splitUserIDs)
+// maxBatchGet is the most expenses a single batch fetch may name.
+const maxBatchGet = 100
 
-	if err := db.AllMembersOfGroup(c.Request.Context(), h.pool, uniqueUserIDs, expense.GroupID); err != nil {
-		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound: apierrors.ErrUserNotInGroup,
-		}))
+// BatchGet godoc
+// @Summary Get several expenses
+// @Description Fetch up to 100 expenses by ID in one request, with their splits, instead of one GET /expenses/{id} per expense. Repeated IDs count toward the limit and are returned once.
+// @Description Only expenses the user can read are returned, in request order; settlements are excluded as on GET /expenses/{id}. IDs that do not exist or belong to a group or private expense the user cannot see are listed under missing, without telling the two apart.
+// @Tags expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object{expense_ids=[]string} true "Expense IDs to fetch"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} models.ExpenseBatch "Returns the accessible expenses and the missing IDs"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Missing, malformed or too many expense IDs"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/expenses/batch-get [post]
+func (h *ExpensesHandler) BatchGet(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	var request struct {
+		ExpenseIDs []string `json:"expense_ids" binding:"required,min=1"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
-	if !expense.IsIncompleteAmount && !expense.IsIncompleteSplit {
-		if math.Abs(paidTotal-expense.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("paid split total does not match expense amount"))
-			return
-		}
-		if math.Abs(owedTotal-expense.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("owed split total does not match expense amount"))
-			return
-		}
+	// Checked before parsing, so an oversized request is rejected without any work on it
+	if len(request.ExpenseIDs) > maxBatchGet {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("cannot fetch more than %d expenses at once", maxBatchGet))
+		return
+	}
+	expenseIDs := parseUniqueIDs(c, request.ExpenseIDs)
+	if expenseIDs == nil {
+		return
 	}
 
-	err := db.CreateExpense(c.Request.Context(), h.pool, &expense)
+	expenses, err := db.GetExpensesByIDs(c.Request.Context(), h.pool, userID, expenseIDs)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound: apierrors.ErrGroupNotFound,
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
 		}))
 		return
 	}
 
-	// Sort splits to match consistent ordering (is_paid DESC, user_id ASC)
-	SortExpenseSplits(expense.Splits)
+	batch := models.ExpenseBatch{Expenses: expenses, Missing: make([]uuid.UUID, 0)}
+	found := make(map[uuid.UUID]bool, len(expenses))
+	for i := range batch.Expenses {
+		SortExpenseSplits(batch.Expenses[i].Splits)
+		found[batch.Expenses[i].ExpenseID] = true
+	}
+	for _, expenseID := range expenseIDs {
+		if !found[expenseID] {
+			batch.Missing = append(batch.Missing, expenseID)
+		}
+	}
 
-	utils.SendJSON(c, http.StatusCreated, expense)
+	utils.SendJSON(c, http.StatusOK, batch)
 }
 
-// Get godoc
-// @Summary Get expense details
-// @Description Get detailed information about an expense including splits
-// @Tags expenses
-// @Produce json
-// @Security BearerAuth
-// @Param id path string true "Expense ID"
-// @Success 200 {object} models.ExpenseDetails "Returns expense details including all splits"
-// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: The authenticated user is not a member of the group this expense belongs to"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist"
-// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
-// @Router /v1/expenses/{id} [get]
-func (h *ExpensesHandler) Get(c *gin.Context) {
-	// Expense is already fetched and authorized by middleware
-	expense := middleware.MustGetExpense(c)
-	utils.SendJSON(c, http.StatusOK, expense)
+// parseUniqueIDs parses string UUIDs, dropping repeats while keeping the first occurrence's position.
+// Returns the parsed UUIDs or sends an error response and returns nil if parsing fails.
+func parseUniqueIDs(c *gin.Context, idStrs []string) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(idStrs))
+	ids := make([]uuid.UUID, 0, len(idStrs))
+	for _, idStr := range idStrs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid UUID format: %s", idStr))
+			return nil
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
-// Update godoc
-// @Summary Update an expense
-// @Description Update an existing expense (requires being the expense creator). Immutable fields will be ignored if included in the request body.
+// maxBulkDelete is the most expenses a single bulk delete may name.
+const maxBulkDelete = 100
+
+// BulkDelete godoc
+// @Summary Delete several expenses
+// @Description Move up to 100 expenses to the trash at once, for example to clean up after a bad import. Repeated IDs count toward the limit and are deleted once.
+// @Description The caller needs delete access to every expense (expense creator or group admin). All expenses are deleted in one transaction: if any of them is not found, not deletable or fails to delete, nothing is deleted.
+// @Description The response lists a result per expense. On failure the blocking expenses carry the error code and message, and the status is that of the first one.
 // @Tags expenses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "Expense ID"
-// @Param request body models.ExpenseDetails true "Updated expense details"
-// @Success 200 {object} models.ExpenseDetails "Returns updated expense with all fields"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | INVALID_SPLIT: No splits provided or split totals do not match expense amount"
+// @Param request body object{expense_ids=[]string} true "Expense IDs to delete"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} object{message=string,results=[]models.BulkDeleteResult} "Returns a result per expense"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Missing, malformed or too many expense IDs"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist"
+// @Failure 403 {object} object{code=string,message=string,results=[]models.BulkDeleteResult} "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the creator or group admin of an expense"
+// @Failure 404 {object} object{code=string,message=string,results=[]models.BulkDeleteResult} "EXPENSE_NOT_FOUND: An expense does not exist"
+// @Failure 409 {object} object{code=string,message=string,results=[]models.BulkDeleteResult} "EXPENSE_SETTLED: A settlement covers an expense (only with LOCK_SETTLED_EXPENSES)"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
-// @Router /v1/expenses/{id} [put]
-func (h *ExpensesHandler) Update(c *gin.Context) {
-	groupID := middleware.MustGetGroupID(c)
-	expense := middleware.MustGetExpense(c)
+// @Router /v1/expenses/bulk-delete [post]
+func (h *ExpensesHandler) BulkDelete(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
 
-	var payload models.ExpenseDetails
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	var request struct {
+		ExpenseIDs []string `json:"expense_ids" binding:"required,min=1"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
-	// Strip immutable fields (silently ignore if client sends them)
-	if err := utils.StripImmutableFields(&payload); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if len(request.ExpenseIDs) > maxBulkDelete {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("cannot delete more than %d expenses at once", maxBulkDelete))
 		return
 	}
-
-	if len(payload.Splits) == 0 {
-		utils.SendError(c, apierrors.ErrInvalidSplit.Msg("no splits provided"))
+	expenseIDs := parseUniqueIDs(c, request.ExpenseIDs)
+	if expenseIDs == nil {
 		return
 	}
 
-	splitUserIDs := make([]uuid.UUID, 0, len(payload.Splits))
-	var paidTotal, owedTotal float64
-	for _, s := range payload.Splits {
-		if s.Amount <= 0 {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("split amounts must be positive"))
+	// Check every expense up front so nothing is deleted unless the whole batch can be
+	results := make([]models.BulkDeleteResult, len(expenseIDs))
+	var firstErr *apierrors.AppError
+	for i, expenseID := range expenseIDs {
+		results[i].ExpenseID = expenseID
+		appErr := h.checkBulkDeletable(c, userID, expenseID)
+		if appErr == nil {
+			continue
+		}
+		if appErr.HTTPCode >= http.StatusInternalServerError {
+			utils.SendError(c, appErr)
 			return
 		}
-		splitUserIDs = append(splitUserIDs, s.UserID)
-		if s.IsPaid {
-			paidTotal += s.Amount
-		} else {
-			owedTotal += s.Amount
+		results[i].Code, results[i].Message = string(appErr.MachineCode), appErr.Message
+		if firstErr == nil {
+			firstErr = appErr
 		}
 	}
-
-	if err := db.AllMembersOfGroup(c.Request.Context(), h.pool, splitUserIDs, groupID); err != nil {
-		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound: apierrors.ErrUserNotInGroup,
-		}))
+	if firstErr != nil {
+		sendBulkDeleteFailure(c, firstErr, results)
 		return
 	}
 
-	if !payload.IsIncompleteAmount && !payload.IsIncompleteSplit {
-		if math.Abs(paidTotal-payload.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("paid split total does not match expense amount"))
-			return
-		}
-		if math.Abs(owedTotal-payload.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("owed split total does not match expense amount"))
+	failedID, err := db.DeleteExpenses(c.Request.Context(), h.pool, expenseIDs, userID)
+	if err != nil {
+		// An expense deleted concurrently rolls back the whole batch
+		if db.IsNotFound(err) {
+			for i := range results {
+				if results[i].ExpenseID == failedID {
+					results[i].Code, results[i].Message = string(apierrors.CodeExpenseNotFound), apierrors.ErrExpenseNotFound.Message
+				}
+			}
+			sendBulkDeleteFailure(c, apierrors.ErrExpenseNotFound, results)
 			return
 		}
+		utils.SendError(c, err)
+		return
 	}
 
-	// Restore immutable fields from middleware-fetched expense (no extra DB fetch needed)
-	utils.RestoreImmutableFields(&payload.Expense, &expense.Expense)
+	for i := range results {
+		results[i].Deleted = true
+	}
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"message": "expenses deleted",
+		"results": results,
+	})
+}
 
-	// Preserve existing transacted_at when client omits it (nil = not provided)
-	if payload.TransactedAt == nil {
-		payload.TransactedAt = expense.TransactedAt
+// checkBulkDeletable applies the checks of a single delete to one expense of a bulk delete.
+// Returns nil if the expense may be deleted.
+func (h *ExpensesHandler) checkBulkDeletable(c *gin.Context, userID, expenseID uuid.UUID) *apierrors.AppError {
+	expense, err := db.GetExpense(c.Request.Context(), h.pool, expenseID)
+	if err != nil {
+		if db.IsNotFound(err) {
+			return apierrors.ErrExpenseNotFound
+		}
+		return apierrors.ErrInternalServer.WithInternal(err)
 	}
 
-	if err := db.UpdateExpense(c.Request.Context(), h.pool, &payload); err != nil {
-		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound: apierrors.ErrExpenseNotFound,
-		}))
-		return
+	if appErr := middleware.CheckExpenseManageAccess(c.Request.Context(), h.pool, userID, expense); appErr != nil {
+		return appErr
 	}
 
-	// Sort splits to match consistent ordering (is_paid DESC, user_id ASC)
-	SortExpenseSplits(payload.Splits)
+	if h.appConfig.LockSettledExpenses {
+		settlementID, err := db.GetCoveringSettlement(c.Request.Context(), h.pool, expenseID)
+		if err != nil {
+			return apierrors.ErrInternalServer.WithInternal(err)
+		}
+		if settlementID != uuid.Nil {
+			return apierrors.ErrExpenseSettled.Msgf("expense is covered by settlement %s; delete the settlement before changing it", settlementID)
+		}
+	}
 
-	utils.SendJSON(c, http.StatusOK, payload)
+	return nil
 }
 
-// Delete godoc
-// @Summary Delete an expense
-// @Description Delete an expense (requires being the expense creator or group admin)
+// sendBulkDeleteFailure reports a bulk delete that deleted nothing, with the status and code of appErr
+// and the per-expense results.
+func sendBulkDeleteFailure(c *gin.Context, appErr *apierrors.AppError, results []models.BulkDeleteResult) {
+	utils.SendJSON(c, appErr.HTTPCode, gin.H{
+		"code":    appErr.MachineCode,
+		"message": "no expenses were deleted: " + appErr.Message,
+		"results": results,
+	})
+}
+
+// Restore godoc
+// @Summary Restore a deleted expense
+// @Description Take an expense out of the trash so it counts towards balances again (requires being the expense creator or group admin)
 // @Tags expenses
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Expense ID"
-// @Success 200 {object} map[string]string "Returns success message"
+// @Success 200 {object} models.ExpenseDetails "Returns the restored expense"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator or group admin"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense is not in the trash or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
-// @Router /v1/expenses/{id} [delete]
-func (h *ExpensesHandler) Delete(c *gin.Context) {
+// @Router /v1/expenses/{id}/restore [post]
+func (h *ExpensesHandler) Restore(c *gin.Context) {
 	expense := middleware.MustGetExpense(c)
 
-	if err := db.DeleteExpense(c.Request.Context(), h.pool, expense.ExpenseID); err != nil {
+	if err := db.RestoreExpense(c.Request.Context(), h.pool, expense.ExpenseID, middleware.MustGetUserID(c)); err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound: apierrors.ErrExpenseNotFound,
 		}))
 		return
 	}
 
-	utils.SendOK(c, "expense deleted")
+	restored, err := db.GetExpense(c.Request.Context(), h.pool, expense.ExpenseID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrExpenseNotFound,
+		}))
+		return
+	}
+
+	utils.SendData(c, restored)
 }
 
 // Patch godoc
 // @Summary Partially update an expense
 // @Description Update specific fields of an expense (requires being the expense creator). Only provided fields are updated, others remain unchanged. Immutable fields are automatically protected.
+// @Description Omitting splits leaves the existing splits unchanged; sending an empty splits list is rejected, since an expense always needs at least one split.
 // @Tags expenses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Expense ID"
 // @Param request body models.ExpenseDetailsPatch true "Partial expense details (all fields optional except where validation requires)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.ExpenseDetails "Returns updated expense with all fields"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or validation failed | INVALID_SPLIT: Split totals do not match expense amount"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or validation failed | BAD_TITLE: Title is missing | BAD_PAYMENT_METHOD: Payment method is not in the allowed list | BAD_CATEGORY: Category is too long, spans multiple lines, or is not in the allowed list | BAD_REQUEST: The group requires a description | INVALID_SPLIT: Empty splits list or split totals do not match expense amount | BAD_REQUEST: transacted_at is more than a day in the future"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the expense creator | USER_NOT_IN_GROUP: One or more users in the splits are not members of the group"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The specified expense does not exist or the user is not a member of its group"
+// @Failure 409 {object} apierrors.AppError "EXPENSE_SETTLED: A settlement covers the expense (only with LOCK_SETTLED_EXPENSES); the message names the settlement to delete first"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
 // @Router /v1/expenses/{id} [patch]
 func (h *ExpensesHandler) Patch(c *gin.Context) {
 	expense := middleware.MustGetExpense(c)
 	groupID := middleware.MustGetGroupID(c)
 
+	if !h.checkNotSettled(c, expense.ExpenseID) {
+		return
+	}
+
 	var patch models.ExpenseDetailsPatch
-	if err := c.ShouldBindJSON(&patch); err != nil {
+	if !bindJSON(c, &patch, h.appConfig.StrictJSON) {
+		return
+	}
+
+	// Apply patch to expense (only non-nil fields are applied)
+	if err := utils.Patch(&expense, &patch); err != nil {
 		utils.SendError(c, apierrors.ErrBadRequest)
 		return
 	}
 
-	// Validate splits members are in group (if splits provided in patch)
+	title, err := utils.ValidateExpenseTitle(expense.Title, h.appConfig.AllowEmptyTitle, h.appConfig.DefaultTitle)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTitle: apierrors.ErrInvalidTitle,
+		}))
+		return
+	}
+	expense.Title = title
+
+	expense.PaymentMethod, err = utils.ValidatePaymentMethod(expense.PaymentMethod, h.appConfig.PaymentMethods)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPaymentMethod: apierrors.ErrInvalidPaymentMethod,
+		}))
+		return
+	}
+
+	expense.Category, err = utils.ValidateCategory(expense.Category, h.appConfig.ExpenseCategories)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidCategory: apierrors.ErrInvalidCategory,
+		}))
+		return
+	}
+
+	if err := utils.ValidateTransactedAt(expense.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	if !h.checkRequiredDescription(c, groupID, expense.Description) {
+		return
+	}
+
+	// Validate splits AFTER applying patch, so amount changes are checked against
+	// the existing splits too. Omitted splits stay unchanged; an explicit [] is rejected.
+	if err := utils.ValidateSplits(expense.Splits, expense.Amount, h.appConfig.SplitTolerance, expense.IsIncompleteAmount, expense.IsIncompleteSplit); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidSplit: apierrors.ErrInvalidSplit,
+		}))
+		return
+	}
+
+	// Validate split members are in group (if splits provided in patch)
 	if patch.Splits != nil {
-		if len(*patch.Splits) == 0 {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("no splits provided"))
-			return
-		}
 		splitUserIDs := make([]uuid.UUID, 0, len(*patch.Splits))
 		for _, s := range *patch.Splits {
 			splitUserIDs = append(splitUserIDs, s.UserID)
@@ -318,42 +1236,7 @@ func (h *ExpensesHandler) Patch(c *gin.Context) {
 		}
 	}
 
-	// Apply patch to expense (only non-nil fields are applied)
-	if err := utils.Patch(&expense, &patch); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
-		return
-	}
-
-	// Validate split totals AFTER applying patch
-	if len(expense.Splits) > 0 {
-		for _, s := range expense.Splits {
-			if s.Amount <= 0 {
-				utils.SendError(c, apierrors.ErrInvalidSplit.Msg("split amounts must be positive"))
-				return
-			}
-		}
-	}
-	if len(expense.Splits) > 0 && !expense.IsIncompleteAmount && !expense.IsIncompleteSplit {
-		var paidTotal, owedTotal float64
-		for _, s := range expense.Splits {
-			if s.IsPaid {
-				paidTotal += s.Amount
-			} else {
-				owedTotal += s.Amount
-			}
-		}
-
-		if math.Abs(paidTotal-expense.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("paid split total does not match expense amount"))
-			return
-		}
-		if math.Abs(owedTotal-expense.Amount) > h.appConfig.SplitTolerance {
-			utils.SendError(c, apierrors.ErrInvalidSplit.Msg("owed split total does not match expense amount"))
-			return
-		}
-	}
-
-	err := db.UpdateExpense(c.Request.Context(), h.pool, &expense)
+	err = db.UpdateExpense(c.Request.Context(), h.pool, &expense, middleware.MustGetUserID(c))
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound:     apierrors.ErrExpenseNotFound,
@@ -375,3 +1258,40 @@ func SortExpenseSplits(splits []models.ExpenseSplit) {
 		return bytes.Compare(splits[i].UserID[:], splits[j].UserID[:]) < 0
 	})
 }
+
+// checkNotSettled enforces the LOCK_SETTLED_EXPENSES setting, under which any settlement between
+// two members locks the expenses they share as payer and debtor (see coverSettledExpenses).
+// Sends an error naming the blocking settlement and returns false if a settlement covers the expense.
+func (h *ExpensesHandler) checkNotSettled(c *gin.Context, expenseID uuid.UUID) bool {
+	if !h.appConfig.LockSettledExpenses {
+		return true
+	}
+
+	settlementID, err := db.GetCoveringSettlement(c.Request.Context(), h.pool, expenseID)
+	if err != nil {
+		utils.SendError(c, err)
+		return false
+	}
+	if settlementID != uuid.Nil {
+		utils.SendError(c, apierrors.ErrExpenseSettled.Msgf("expense is covered by settlement %s; delete the settlement before changing it", settlementID))
+		return false
+	}
+	return true
+}
+
+// checkRequiredDescription enforces the group's require_description setting.
+// Sends an error and returns false if the group requires a description and none is given.
+func (h *ExpensesHandler) checkRequiredDescription(c *gin.Context, groupID uuid.UUID, description *string) bool {
+	required, err := db.GroupRequiresDescription(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return false
+	}
+	if required && (description == nil || strings.TrimSpace(*description) == "") {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("this group requires a description for every expense"))
+		return false
+	}
+	return true
+}
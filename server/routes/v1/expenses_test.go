@@ -0,0 +1,255 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/db/dbtest"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/middleware"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serveWithExpense runs handler for a request made by userID, with expense in the context
+// as VerifyExpenseAccess would have left it.
+func serveWithExpense(handler gin.HandlerFunc, userID uuid.UUID, expense models.ExpenseDetails, method, path, route, body string) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Handle(method, route, func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, userID)
+		c.Set(middleware.ExpenseKey, expense)
+		c.Set(middleware.ExpenseIDKey, expense.ExpenseID)
+		c.Set(middleware.GroupIDKey, expense.GroupID)
+	}, handler)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func patchSplitPaid(h *ExpensesHandler, userID uuid.UUID, expense models.ExpenseDetails, splitUserID uuid.UUID, isPaid bool) *httptest.ResponseRecorder {
+	body := `{"is_paid":false}`
+	if isPaid {
+		body = `{"is_paid":true}`
+	}
+	path := "/expenses/" + expense.ExpenseID.String() + "/splits/" + splitUserID.String()
+	return serveWithExpense(h.UpdateSplitPaid, userID, expense, http.MethodPatch, path, "/expenses/:id/splits/:user_id", body)
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error body %q: %v", w.Body.String(), err)
+	}
+	return body.Code
+}
+
+func TestUpdateSplitPaidRejectedBeforeReachingTheDatabase(t *testing.T) {
+	payer, debtor, outsider := uuid.New(), uuid.New(), uuid.New()
+	expense := models.ExpenseDetails{
+		Expense: models.Expense{ExpenseID: uuid.New(), GroupID: uuid.New(), AddedBy: payer, Amount: 30},
+		// Sorted paid-first, as GetExpense returns them; the payer also owes a share
+		Splits: []models.ExpenseSplit{
+			{UserID: payer, Amount: 30, IsPaid: true},
+			{UserID: payer, Amount: 15},
+			{UserID: debtor, Amount: 15},
+		},
+	}
+	incomplete := expense
+	incomplete.IsIncompleteSplit = true
+
+	// No pool: every case below must be answered without a query
+	h := NewExpensesHandler(nil, config.AppConfig{SplitTolerance: 0.01})
+
+	tests := []struct {
+		name        string
+		expense     models.ExpenseDetails
+		splitUserID uuid.UUID
+		isPaid      bool
+		wantStatus  int
+		wantCode    string
+	}{
+		{"payer who also owes, to owed", expense, payer, false, http.StatusBadRequest, "BAD_REQUEST"},
+		{"payer who also owes, to paid", expense, payer, true, http.StatusBadRequest, "BAD_REQUEST"},
+		{"payer who also owes, incomplete expense", incomplete, payer, false, http.StatusBadRequest, "BAD_REQUEST"},
+		{"debtor on a complete expense", expense, debtor, true, http.StatusBadRequest, "BAD_REQUEST"},
+		{"user without a split", expense, outsider, true, http.StatusNotFound, "USER_NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := patchSplitPaid(h, payer, tt.expense, tt.splitUserID, tt.isPaid)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if code := errorCode(t, w); code != tt.wantCode {
+				t.Errorf("code = %s, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUpdateSplitPaidAlreadyOnSideReturnsExpenseUnchanged(t *testing.T) {
+	payer, debtor := uuid.New(), uuid.New()
+	expense := models.ExpenseDetails{
+		Expense: models.Expense{ExpenseID: uuid.New(), GroupID: uuid.New(), AddedBy: payer, Amount: 10},
+		Splits: []models.ExpenseSplit{
+			{UserID: payer, Amount: 10, IsPaid: true},
+			{UserID: debtor, Amount: 10},
+		},
+	}
+	h := NewExpensesHandler(nil, config.AppConfig{SplitTolerance: 0.01})
+
+	w := patchSplitPaid(h, debtor, expense, debtor, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body.String())
+	}
+	var got models.ExpenseDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Splits) != 2 || got.Splits[1].IsPaid {
+		t.Errorf("splits = %+v, want the debtor's split still owed", got.Splits)
+	}
+}
+
+func TestUpdateSplitPaidMovesSplitOfIncompleteExpense(t *testing.T) {
+	pool := dbtest.Pool(t)
+	ctx := context.Background()
+	payer, debtor := dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, payer.UserID, debtor.UserID)
+
+	expense := models.ExpenseDetails{
+		Expense: models.Expense{
+			GroupID:           group.GroupID,
+			AddedBy:           payer.UserID,
+			Title:             "Draft",
+			Amount:            20,
+			IsIncompleteSplit: true,
+		},
+		Splits: []models.ExpenseSplit{dbtest.Paid(payer.UserID, 20), dbtest.Owes(debtor.UserID, 10)},
+	}
+	if err := db.CreateExpense(ctx, pool, &expense); err != nil {
+		t.Fatalf("create expense: %v", err)
+	}
+	expense, err := db.GetExpense(ctx, pool, expense.ExpenseID)
+	if err != nil {
+		t.Fatalf("get expense: %v", err)
+	}
+
+	h := NewExpensesHandler(pool, config.AppConfig{SplitTolerance: 0.01})
+	w := patchSplitPaid(h, debtor.UserID, expense, debtor.UserID, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", w.Code, w.Body.String())
+	}
+
+	splits, err := db.GetExpenseSplits(ctx, pool, expense.ExpenseID)
+	if err != nil {
+		t.Fatalf("get splits: %v", err)
+	}
+	for _, split := range splits {
+		if split.UserID == debtor.UserID && !split.IsPaid {
+			t.Errorf("debtor's split is still owed: %+v", splits)
+		}
+	}
+}
+
+// newSplitExpense stores a complete 20.00 expense paid by payer and owed equally by payer and debtor,
+// and returns it as GetExpense does.
+func newSplitExpense(t *testing.T, h *ExpensesHandler) (models.ExpenseDetails, uuid.UUID) {
+	t.Helper()
+	payer, debtor := dbtest.User(t, h.pool), dbtest.User(t, h.pool)
+	group := dbtest.Group(t, h.pool, payer.UserID, debtor.UserID)
+	created := dbtest.Expense(t, h.pool, group.GroupID, payer.UserID, 20,
+		dbtest.Paid(payer.UserID, 20), dbtest.Owes(payer.UserID, 10), dbtest.Owes(debtor.UserID, 10))
+
+	expense, err := db.GetExpense(context.Background(), h.pool, created.ExpenseID)
+	if err != nil {
+		t.Fatalf("get expense: %v", err)
+	}
+	return expense, payer.UserID
+}
+
+func assertSplitCount(t *testing.T, h *ExpensesHandler, expenseID uuid.UUID, want int) {
+	t.Helper()
+	splits, err := db.GetExpenseSplits(context.Background(), h.pool, expenseID)
+	if err != nil {
+		t.Fatalf("get splits: %v", err)
+	}
+	if len(splits) != want {
+		t.Errorf("expense has %d splits, want %d", len(splits), want)
+	}
+}
+
+func TestUpdateAndPatchEmptySplits(t *testing.T) {
+	h := NewExpensesHandler(dbtest.Pool(t), config.AppConfig{SplitTolerance: 0.01})
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		// PUT replaces the whole expense, so splits are always required
+		{"update with empty splits", http.MethodPut, `{"title":"Dinner","amount":20,"splits":[]}`, http.StatusBadRequest},
+		{"update without splits", http.MethodPut, `{"title":"Dinner","amount":20}`, http.StatusBadRequest},
+		// PATCH leaves omitted splits alone, but [] is an attempt to empty them
+		{"patch with empty splits", http.MethodPatch, `{"splits":[]}`, http.StatusBadRequest},
+		{"patch without splits", http.MethodPatch, `{"title":"Renamed"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expense, userID := newSplitExpense(t, h)
+			handler := h.Update
+			if tt.method == http.MethodPatch {
+				handler = h.Patch
+			}
+
+			w := serveWithExpense(handler, userID, expense, tt.method,
+				"/expenses/"+expense.ExpenseID.String(), "/expenses/:id", tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusBadRequest {
+				if code := errorCode(t, w); code != "INVALID_SPLIT" {
+					t.Errorf("code = %s, want INVALID_SPLIT", code)
+				}
+			}
+			assertSplitCount(t, h, expense.ExpenseID, len(expense.Splits))
+		})
+	}
+}
+
+func TestBatchGetAndBulkDeleteCountRepeatedIDsTowardTheLimit(t *testing.T) {
+	id := `"` + uuid.NewString() + `"`
+	ids := strings.Repeat(id+",", maxBatchGet) + id
+
+	// No pool: the requests are rejected before any query
+	h := NewExpensesHandler(nil, config.AppConfig{})
+	for path, handler := range map[string]gin.HandlerFunc{
+		"/expenses/batch-get":   h.BatchGet,
+		"/expenses/bulk-delete": h.BulkDelete,
+	} {
+		t.Run(path, func(t *testing.T) {
+			w := serveWithExpense(handler, uuid.New(), models.ExpenseDetails{}, http.MethodPost,
+				path, path, `{"expense_ids":[`+ids+`]}`)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400 (body %s)", w.Code, w.Body.String())
+			}
+		})
+	}
+}
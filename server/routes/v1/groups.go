@@ -19,25 +19,30 @@ import (
 
 type GroupsHandler struct {
 	pool      *pgxpool.Pool
+	readPool  *pgxpool.Pool // Read replica for reporting queries; the primary if none is configured
 	appConfig config.AppConfig
 }
 
-func NewGroupsHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *GroupsHandler {
-	return &GroupsHandler{pool: pool, appConfig: appConfig}
+func NewGroupsHandler(pool, readPool *pgxpool.Pool, appConfig config.AppConfig) *GroupsHandler {
+	return &GroupsHandler{pool: pool, readPool: readPool, appConfig: appConfig}
 }
 
 // Create godoc
 // @Summary Create a new group
 // @Description Create a new group with the logged in user as the creator. A is_private group means all expenses are forced is_private.
+// @Description With require_description=true, expenses in the group cannot be created or updated without a description.
+// @Description base_currency is an ISO 4217 code; when omitted the user's default_currency is used, or the server's default currency if they have none.
 // @Tags groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body object{name=string,description=string,private=bool} true "Group details"
+// @Param request body object{name=string,description=string,private=bool,base_currency=string,require_description=bool} true "Group details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 201 {object} models.GroupDetails "Group successfully created"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format or missing required fields | BAD_NAME: Name contains invalid characters or is too short/long"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format, missing required fields, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | EMAIL_NOT_VERIFIED: The user's email address has not been verified"
+// @Failure 409 {object} apierrors.AppError "GROUP_LIMIT_REACHED: The user already owns the maximum number of groups"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
 // @Router /v1/groups/ [post]
 func (h *GroupsHandler) Create(c *gin.Context) {
@@ -51,23 +56,68 @@ func (h *GroupsHandler) Create(c *gin.Context) {
 		Name        string `json:"name" binding:"required"`
 		Description string `json:"description"`
 		Private     bool   `json:"private"`
+		Currency    string `json:"base_currency"`
+		RequireDesc bool   `json:"require_description"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
 	group.Name, err = utils.ValidateName(request.Name)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			utils.ErrInvalidName: apierrors.ErrInvalidName,
+			utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+			utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+			utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
+		}))
+		return
+	}
+
+	group.Currency = h.appConfig.DefaultCurrency
+	if request.Currency != "" {
+		group.Currency, err = utils.ValidateCurrency(request.Currency)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				utils.ErrInvalidCurrency: apierrors.ErrInvalidCurrency,
+			}))
+			return
+		}
+	} else {
+		user, err := db.GetUser(c.Request.Context(), h.pool, userID)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				db.ErrNotFound: apierrors.ErrUserNotFound,
+			}))
+			return
+		}
+		if user.Currency != nil {
+			group.Currency = *user.Currency
+		}
+	}
+
+	if h.appConfig.MaxGroupsPerUser > 0 {
+		owned, err := db.CountOwnedGroups(c.Request.Context(), h.pool, userID)
+		if err != nil {
+			utils.SendError(c, err)
+			return
+		}
+		if owned >= h.appConfig.MaxGroupsPerUser {
+			utils.SendError(c, apierrors.ErrGroupLimit.Msgf("cannot create more than %d groups", h.appConfig.MaxGroupsPerUser))
+			return
+		}
+	}
+
+	group.Description, err = utils.ValidateDescription(request.Description, h.appConfig.MaxGroupDescLength)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrDescriptionTooLong: apierrors.ErrBadRequest,
 		}))
 		return
 	}
 
-	group.Description = request.Description
 	group.Private = request.Private
+	group.RequireDesc = request.RequireDesc
 	err = db.CreateGroup(c.Request.Context(), h.pool, &group)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
@@ -117,15 +167,16 @@ func (h *GroupsHandler) Get(c *gin.Context) {
 
 // Update godoc
 // @Summary Update a group (full replacement)
-// @Description Update group name and description (requires group admin permission). Immutable fields will be ignored if included in the request body.
+// @Description Update group name, description, base currency and require_description setting (requires group admin permission). Immutable fields will be ignored if included in the request body. An omitted base_currency keeps the current one.
 // @Tags groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body models.Group true "Updated group details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.GroupDetails "Returns updated group"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | BAD_NAME: Name contains invalid characters or is too short/long"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, missing required fields, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
 // @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
@@ -135,8 +186,7 @@ func (h *GroupsHandler) Update(c *gin.Context) {
 	groupID := middleware.MustGetGroupID(c)
 
 	var payload models.Group
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &payload, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -150,12 +200,32 @@ func (h *GroupsHandler) Update(c *gin.Context) {
 	validatedName, err := utils.ValidateName(payload.Name)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			utils.ErrInvalidName: apierrors.ErrInvalidName,
+			utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+			utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+			utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
 		}))
 		return
 	}
 	payload.Name = validatedName
 
+	payload.Description, err = utils.ValidateDescription(payload.Description, h.appConfig.MaxGroupDescLength)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrDescriptionTooLong: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	if payload.Currency != "" {
+		payload.Currency, err = utils.ValidateCurrency(payload.Currency)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				utils.ErrInvalidCurrency: apierrors.ErrInvalidCurrency,
+			}))
+			return
+		}
+	}
+
 	// Set immutable fields from authenticated context (no DB fetch needed)
 	payload.GroupID = groupID
 
@@ -187,9 +257,10 @@ func (h *GroupsHandler) Update(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
-// @Param request body models.GroupPatch true "Partial group details (name and/or description, all optional)"
+// @Param request body models.GroupPatch true "Partial group details (name, description, base_currency and/or require_description, all optional)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.GroupDetails "Returns updated group with all fields"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or validation failed | BAD_NAME: Name contains invalid characters or is too short/long"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, validation failed, or description too long | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: Name contains invalid characters | BAD_CURRENCY: Currency is not a 3-letter ISO 4217 code"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
 // @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
@@ -199,8 +270,7 @@ func (h *GroupsHandler) Patch(c *gin.Context) {
 	groupID := middleware.MustGetGroupID(c)
 
 	var patch models.GroupPatch
-	if err := c.ShouldBindJSON(&patch); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &patch, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -218,13 +288,39 @@ func (h *GroupsHandler) Patch(c *gin.Context) {
 		validatedName, err := utils.ValidateName(*patch.Name)
 		if err != nil {
 			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-				utils.ErrInvalidName: apierrors.ErrInvalidName,
+				utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+				utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+				utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
 			}))
 			return
 		}
 		patch.Name = &validatedName
 	}
 
+	// Validate description if provided
+	if patch.Description != nil {
+		description, err := utils.ValidateDescription(*patch.Description, h.appConfig.MaxGroupDescLength)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				utils.ErrDescriptionTooLong: apierrors.ErrBadRequest,
+			}))
+			return
+		}
+		patch.Description = &description
+	}
+
+	// Validate currency if provided
+	if patch.Currency != nil {
+		currency, err := utils.ValidateCurrency(*patch.Currency)
+		if err != nil {
+			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+				utils.ErrInvalidCurrency: apierrors.ErrInvalidCurrency,
+			}))
+			return
+		}
+		patch.Currency = &currency
+	}
+
 	// Apply patch to group (only non-nil fields are applied)
 	if err := utils.Patch(&current.Group, &patch); err != nil {
 		utils.SendError(c, apierrors.ErrBadRequest)
@@ -251,18 +347,21 @@ func (h *GroupsHandler) Patch(c *gin.Context) {
 
 // AddMembers godoc
 // @Summary Add members to group
-// @Description Add one or more users to a group (requires group admin permission)
+// @Description Add one or more users to a group (requires group admin permission).
+// @Description The operation is idempotent: users that are already members are not an error and are reported under already_members.
 // @Tags groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body object{user_ids=[]string} true "User IDs to add"
-// @Success 200 {object} map[string]interface{} "Returns success message and list of added member IDs"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} map[string]interface{} "Returns success message, list of newly added member IDs (added_members) and IDs that were already members (already_members)"
 // @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, missing required fields, or constraint violation"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
-// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist | USER_NOT_FOUND: One or more specified users do not exist or no valid user IDs provided"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist | USER_NOT_FOUND: One or more specified users do not exist (all are listed in the message)"
+// @Failure 409 {object} apierrors.AppError "GROUP_FULL: Adding the users would exceed the maximum group size"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
 // @Router /v1/groups/{id}/members [post]
 func (h *GroupsHandler) AddMembers(c *gin.Context) {
@@ -273,8 +372,7 @@ func (h *GroupsHandler) AddMembers(c *gin.Context) {
 	}
 
 	var req request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -286,14 +384,38 @@ func (h *GroupsHandler) AddMembers(c *gin.Context) {
 		return
 	}
 
-	if err := db.UsersExist(c.Request.Context(), h.pool, userIDs); err != nil {
-		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound: apierrors.ErrUserNotFound,
-		}))
+	_, missing, err := db.GetUsersByIDs(c.Request.Context(), h.pool, userIDs)
+	if err != nil {
+		utils.SendError(c, err)
 		return
 	}
+	if len(missing) > 0 {
+		utils.SendError(c, apierrors.ErrUserNotFound.Msgf("users not found: %s", utils.JoinUserIDs(missing)))
+		return
+	}
+
+	if h.appConfig.MaxGroupSize > 0 {
+		userIDs = utils.GetUniqueUserIDs(userIDs)
+
+		count, err := db.CountGroupMembers(c.Request.Context(), h.pool, groupID)
+		if err != nil {
+			utils.SendError(c, err)
+			return
+		}
+		existingCount, err := db.CountExistingGroupMembers(c.Request.Context(), h.pool, groupID, userIDs)
+		if err != nil {
+			utils.SendError(c, err)
+			return
+		}
+
+		// Users that are already members do not take up additional slots
+		if count+len(userIDs)-existingCount > h.appConfig.MaxGroupSize {
+			utils.SendError(c, apierrors.ErrGroupFull.Msgf("group cannot have more than %d members", h.appConfig.MaxGroupSize))
+			return
+		}
+	}
 
-	err := db.AddGroupMembers(c.Request.Context(), h.pool, groupID, userIDs)
+	added, existing, err := db.AddGroupMembers(c.Request.Context(), h.pool, groupID, middleware.MustGetUserID(c), userIDs)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound:            apierrors.ErrGroupNotFound,
@@ -303,24 +425,179 @@ func (h *GroupsHandler) AddMembers(c *gin.Context) {
 	}
 
 	utils.SendJSON(c, http.StatusOK, gin.H{
-		"message":       "members added successfully",
-		"added_members": req.UserIDs,
+		"message":         "members added successfully",
+		"added_members":   added,
+		"already_members": existing,
+	})
+}
+
+// GetMembers godoc
+// @Summary Get group members
+// @Description Get the members of a group, oldest first, without the group metadata. Lighter than the group details for member pickers.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {array} models.GroupUser "Returns the group members"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/members [get]
+func (h *GroupsHandler) GetMembers(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	members, err := db.GetGroupMembers(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, members)
+}
+
+// GetMemberCount godoc
+// @Summary Get group member count
+// @Description Get the number of members in a group without loading the member list
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} map[string]interface{} "Returns the group ID (group_id), its member count (member_count) and the configured maximum (max_members, 0 for unlimited)"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/members/count [get]
+func (h *GroupsHandler) GetMemberCount(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	count, err := db.CountGroupMembers(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"group_id":     groupID,
+		"member_count": count,
+		"max_members":  h.appConfig.MaxGroupSize,
+	})
+}
+
+// GetPublic godoc
+// @Summary Get the public view of a group
+// @Description Get a reduced view of a group that does not require membership: its name, member count and creator name. The member list, expenses and balances are never included; use GET /groups/{id} as a member for those.
+// @Description Meant for showing a teaser of a group before joining it. Knowing the group ID is enough to see it.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} models.GroupPreview "Returns the public view of the group"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid group ID"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/public [get]
+func (h *GroupsHandler) GetPublic(c *gin.Context) {
+	groupID := middleware.MustGetUUIDParam(c, "id")
+
+	preview, err := db.GetGroupPreview(c.Request.Context(), h.readPool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, preview)
+}
+
+// Pin godoc
+// @Summary Pin or unpin a group
+// @Description Pin the group to the top of the authenticated user's own group list (GET /me/groups and GET /me/spending), or unpin it. Only affects the order of the user's own lists, not other members or permissions.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body object{pinned=bool} true "Whether the group is pinned"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} object{group_id=string,pinned=bool} "Returns the new pinned state"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/pin [put]
+func (h *GroupsHandler) Pin(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	var request struct {
+		Pinned *bool `json:"pinned" binding:"required"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
+		return
+	}
+
+	if err := db.SetGroupPinned(c.Request.Context(), h.pool, middleware.MustGetUserID(c), groupID, *request.Pinned); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotInGroup,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"group_id": groupID,
+		"pinned":   *request.Pinned,
+	})
+}
+
+// GetOwedTotal godoc
+// @Summary Get what the user owes in a group
+// @Description Get the authenticated user's net balance in the group as one number: what they owe across all expenses and settlements minus what they paid. Positive means they owe the group, negative means the group owes them.
+// @Description Computed with a single sum over the user's own splits, so it is cheap enough to poll for a badge. Use GET /groups/{id}/settle for who to pay.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} object{group_id=string,owed_total=number} "Returns the user's net owed total in the group"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/me/owed-total [get]
+func (h *GroupsHandler) GetOwedTotal(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	owed, err := db.GetUserNetOwed(c.Request.Context(), h.readPool, middleware.MustGetUserID(c), groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"group_id":   groupID,
+		"owed_total": utils.RoundMoney(owed),
 	})
 }
 
 // RemoveMembers godoc
 // @Summary Remove members from group
 // @Description Remove one or more users from a group (requires group admin permission)
+// @Description Members who still owe or are owed money in the group are not removed unless force=true, since their debts would be orphaned. Forced removals note the balance left behind in the activity log.
 // @Tags groups
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body object{user_ids=[]string} true "User IDs to remove"
+// @Param force query bool false "Remove members even if they have an outstanding balance (default false)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} map[string]interface{} "Returns success message and list of removed member IDs"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, missing required fields, or attempting to remove self from group"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, missing required fields, invalid force value, or attempting to remove self from group"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin | USER_NOT_IN_GROUP: One or more specified users are not members of the group"
+// @Failure 409 {object} apierrors.AppError "OUTSTANDING_BALANCE: One or more members have a non-zero balance; the message lists their IDs"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
 // @Router /v1/groups/{id}/members [delete]
 func (h *GroupsHandler) RemoveMembers(c *gin.Context) {
@@ -329,8 +606,7 @@ func (h *GroupsHandler) RemoveMembers(c *gin.Context) {
 	}
 
 	var req request
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -348,7 +624,22 @@ func (h *GroupsHandler) RemoveMembers(c *gin.Context) {
 		return
 	}
 
-	err := db.RemoveGroupMembers(c.Request.Context(), h.pool, groupID, userIDs)
+	force, ok := parseBoolQuery(c, "force", false)
+	if !ok {
+		return
+	}
+
+	outstanding, err := db.RemoveGroupMembers(c.Request.Context(), h.pool, groupID, userID, userIDs, force, h.appConfig.SplitTolerance)
+	if db.IsOutstandingBalance(err) {
+		owing := make([]uuid.UUID, 0, len(outstanding))
+		for _, id := range userIDs {
+			if _, ok := outstanding[id]; ok {
+				owing = append(owing, id)
+			}
+		}
+		utils.SendError(c, apierrors.ErrOutstandingBalance.Msgf("members with an outstanding balance: %s", utils.JoinUserIDs(owing)))
+		return
+	}
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound: apierrors.ErrUserNotInGroup,
@@ -362,6 +653,87 @@ func (h *GroupsHandler) RemoveMembers(c *gin.Context) {
 	})
 }
 
+// Leave godoc
+// @Summary Leave a group
+// @Description Remove the authenticated user from a group. The group admin cannot leave and must transfer ownership first.
+// @Description Leaving is refused while the user still owes or is owed money in the group, so settle up first.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} object{message=string,group_id=string,user_id=string,member=bool} "Returns the user's membership state after leaving"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: The user is the group admin"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 409 {object} apierrors.AppError "OUTSTANDING_BALANCE: The user has a non-zero balance in the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/leave [post]
+func (h *GroupsHandler) Leave(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	creatorID, err := db.GetGroupCreator(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+	if creatorID == userID {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("owner must transfer ownership before leaving"))
+		return
+	}
+
+	// Logged like an admin removal, with the user as the actor
+	outstanding, err := db.RemoveGroupMembers(c.Request.Context(), h.pool, groupID, userID, []uuid.UUID{userID}, false, h.appConfig.SplitTolerance)
+	if db.IsOutstandingBalance(err) {
+		utils.SendError(c, apierrors.ErrOutstandingBalance.Msgf("you have an outstanding balance of %.2f in the group", outstanding[userID]))
+		return
+	}
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotInGroup,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"message":  "left group",
+		"group_id": groupID,
+		"user_id":  userID,
+		"member":   false,
+	})
+}
+
+// GetSummary godoc
+// @Summary Get a group-wide balance summary
+// @Description Get dashboard totals for the whole group: total spend (settlements excluded), the net balance of every member, the number of user pairs with an unsettled direct debt, and the biggest creditor and debtor.
+// @Description Unlike /settle it is not relative to the authenticated user. Balances within SPLIT_TOLERANCE count as settled, so a settled group returns zero balances and null top_creditor and top_debtor.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {object} models.GroupSummary "Returns the group summary"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/summary [get]
+func (h *GroupsHandler) GetSummary(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	summary, err := db.GetGroupSummary(c.Request.Context(), h.readPool, groupID, h.appConfig.SplitTolerance)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, summary)
+}
+
 // GetSpendings godoc
 // @Summary Get user expenses in group
 // @Description Get all expenses where the authenticated user owes money in a specific group, with the user's owed amount per expense
@@ -379,7 +751,7 @@ func (h *GroupsHandler) GetSpendings(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 	groupID := middleware.MustGetGroupID(c)
 
-	expenses, err := db.GetUserSpending(c.Request.Context(), h.pool, userID, groupID)
+	expenses, err := db.GetUserSpending(c.Request.Context(), h.readPool, userID, groupID)
 	if err != nil {
 		utils.SendError(c, err)
 		return
@@ -428,3 +800,38 @@ func parseUserIDs(c *gin.Context, userIDStrs []string) []uuid.UUID {
 	}
 	return userIDs
 }
+
+// GetActivity godoc
+// @Summary Get group activity log
+// @Description Get who changed what in the group, newest first: expenses and settlements created, updated, deleted or restored, and members added or removed.
+// @Description Each entry carries the actor, the action, the target and a compact summary of the change. Summaries of private expenses are left empty. Results are paginated; pass next_cursor back as cursor to get the next page.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} object{items=[]models.Activity,next_cursor=string} "Returns a page of activity entries"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid limit or cursor"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/activity [get]
+func (h *GroupsHandler) GetActivity(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	limit, cursor, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	entries, nextCursor, err := db.GetActivity(c.Request.Context(), h.readPool, groupID, limit, cursor)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendPaginated(c, entries, nextCursor)
+}
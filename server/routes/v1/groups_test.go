@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/config"
+	"github.com/pranaovs/qashare/db/dbtest"
+	"github.com/pranaovs/qashare/routes/middleware"
+)
+
+// page is the pagination envelope written by utils.SendPaginated.
+type page struct {
+	Items      []json.RawMessage `json:"items"`
+	NextCursor *string           `json:"next_cursor"`
+}
+
+// collectPages follows next_cursor through handler one item at a time, as userID in groupID,
+// and returns every page it was served.
+func collectPages(t *testing.T, handler gin.HandlerFunc, userID, groupID uuid.UUID) []page {
+	t.Helper()
+	r := gin.New()
+	r.GET("/groups/:id/list", func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, userID)
+		c.Set(middleware.GroupIDKey, groupID)
+	}, handler)
+
+	var pages []page
+	cursor := ""
+	for {
+		if len(pages) > 10 {
+			t.Fatalf("still paginating after %d pages", len(pages))
+		}
+		query := url.Values{"limit": {"1"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/groups/"+groupID.String()+"/list?"+query.Encode(), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("page %d: status = %d (body %s)", len(pages)+1, w.Code, w.Body.String())
+		}
+
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if _, ok := envelope["items"]; !ok {
+			t.Fatalf("page %d has no items key: %s", len(pages)+1, w.Body.String())
+		}
+		if _, ok := envelope["next_cursor"]; !ok {
+			t.Fatalf("page %d has no next_cursor key: %s", len(pages)+1, w.Body.String())
+		}
+
+		var p page
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		pages = append(pages, p)
+		if p.NextCursor == nil {
+			return pages
+		}
+		cursor = *p.NextCursor
+	}
+}
+
+// assertPaged checks that pages holds want items, one per page, with next_cursor null only on the last page.
+func assertPaged(t *testing.T, pages []page, want int) {
+	t.Helper()
+	if len(pages) != want {
+		t.Fatalf("got %d pages, want %d", len(pages), want)
+	}
+	for i, p := range pages {
+		if len(p.Items) != 1 {
+			t.Errorf("page %d has %d items, want 1", i+1, len(p.Items))
+		}
+		if last := i == len(pages)-1; last != (p.NextCursor == nil) {
+			t.Errorf("page %d: next_cursor = %v, want null only on the last page", i+1, p.NextCursor)
+		}
+	}
+}
+
+func TestGetSettlementsPaginates(t *testing.T) {
+	pool := dbtest.Pool(t)
+	owner, member := dbtest.User(t, pool), dbtest.User(t, pool)
+	group := dbtest.Group(t, pool, owner.UserID, member.UserID)
+	dbtest.Settlement(t, pool, group.GroupID, member.UserID, owner.UserID, 10)
+	dbtest.Settlement(t, pool, group.GroupID, owner.UserID, member.UserID, 5)
+
+	h := NewGroupsHandler(pool, pool, config.AppConfig{})
+	assertPaged(t, collectPages(t, h.GetSettlements, owner.UserID, group.GroupID), 2)
+}
+
+func TestGetActivityPaginates(t *testing.T) {
+	pool := dbtest.Pool(t)
+	owner, first, second := dbtest.User(t, pool), dbtest.User(t, pool), dbtest.User(t, pool)
+	// Adding the members logs one entry each
+	group := dbtest.Group(t, pool, owner.UserID, first.UserID, second.UserID)
+
+	h := NewGroupsHandler(pool, pool, config.AppConfig{})
+	assertPaged(t, collectPages(t, h.GetActivity, owner.UserID, group.GroupID), 2)
+}
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/apperrors"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/routes/middleware"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// PeekInvite godoc
+// @Summary Preview a group invite
+// @Description Get the name, member count and creator name of the group an invite points to, without consuming a use of the invite
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invite token"
+// @Success 200 {object} models.GroupPreview "Returns the invite's target group"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "INVITE_INVALID: The invite does not exist, has expired, or has no uses left"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/invites/{token} [get]
+func (h *GroupsHandler) PeekInvite(c *gin.Context) {
+	token, err := db.ParseUUID(c.Param("token"))
+	if err != nil {
+		utils.SendError(c, apierrors.ErrInviteInvalid)
+		return
+	}
+
+	preview, err := db.PeekGroupInvite(c.Request.Context(), h.pool, token)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrInviteInvalid,
+			db.ErrExpiredToken: apierrors.ErrInviteInvalid,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, preview)
+}
+
+// CreateInvite godoc
+// @Summary Create a group invite
+// @Description Create a shareable invite link to the group (requires group admin permission). Anyone with the token can join the group by accepting it.
+// @Description expires_at (unix seconds) and max_uses are optional; without them the invite never expires and has unlimited uses.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body object{expires_at=int,max_uses=int} false "Optional expiry and use limit"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 201 {object} models.GroupInvite "Returns the created invite with its token"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body, expires_at not in the future, or max_uses below 1"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/invites [post]
+func (h *GroupsHandler) CreateInvite(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	var req struct {
+		ExpiresAt *int64 `json:"expires_at"`
+		MaxUses   *int   `json:"max_uses"`
+	}
+	// The body is optional: an empty body creates an invite without limits
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req, h.appConfig.StrictJSON) {
+		return
+	}
+
+	if req.ExpiresAt != nil && *req.ExpiresAt <= time.Now().Unix() {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("expires_at must be in the future"))
+		return
+	}
+
+	invite, err := db.CreateGroupInvite(c.Request.Context(), h.pool, groupID, userID, req.ExpiresAt, req.MaxUses)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+			db.ErrNotFound:     apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusCreated, invite)
+}
+
+// DeleteInvite godoc
+// @Summary Revoke a group invite
+// @Description Delete an invite so its token can no longer be used to join the group (requires group admin permission). Members who already joined through it stay in the group.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param invite_id path string true "Invite ID"
+// @Success 200 {object} map[string]string "Returns success message"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid invite ID"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "INVITE_INVALID: The group has no invite with this ID"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/invites/{invite_id} [delete]
+func (h *GroupsHandler) DeleteInvite(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	inviteID := middleware.MustGetUUIDParam(c, "invite_id")
+
+	if err := db.DeleteGroupInvite(c.Request.Context(), h.pool, groupID, inviteID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrInviteInvalid,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "invite deleted")
+}
+
+// AcceptInvite godoc
+// @Summary Join a group with an invite
+// @Description Join the group an invite points to, consuming one of its uses. Accepting an invite to a group the user is already a member of succeeds without consuming a use, with joined=false.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invite token"
+// @Success 200 {object} object{message=string,group_id=string,joined=bool} "Returns the joined group and whether the user was newly added"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "INVITE_INVALID: The invite does not exist, has expired, or has no uses left"
+// @Failure 409 {object} apierrors.AppError "GROUP_FULL: The group has reached the maximum number of members"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/invites/{token}/accept [post]
+func (h *GroupsHandler) AcceptInvite(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	token, err := db.ParseUUID(c.Param("token"))
+	if err != nil {
+		utils.SendError(c, apierrors.ErrInviteInvalid)
+		return
+	}
+
+	if h.appConfig.MaxGroupSize > 0 && !h.checkInviteCapacity(c, token, userID) {
+		return
+	}
+
+	groupID, joined, err := db.RedeemGroupInvite(c.Request.Context(), h.pool, token, userID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrInviteInvalid,
+			db.ErrExpiredToken: apierrors.ErrInviteInvalid,
+		}))
+		return
+	}
+
+	message := "joined group"
+	if !joined {
+		message = "already a member"
+	}
+	utils.SendJSON(c, http.StatusOK, gin.H{
+		"message":  message,
+		"group_id": groupID,
+		"joined":   joined,
+	})
+}
+
+// checkInviteCapacity rejects accepting an invite to a full group, unless the user is already a member.
+// Returns false if a response was sent.
+func (h *GroupsHandler) checkInviteCapacity(c *gin.Context, token, userID uuid.UUID) bool {
+	groupID, err := db.GetGroupInviteGroup(c.Request.Context(), h.pool, token)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrInviteInvalid,
+		}))
+		return false
+	}
+
+	isMember, err := db.MemberOfGroup(c.Request.Context(), h.pool, userID, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return false
+	}
+	if isMember {
+		return true
+	}
+
+	count, err := db.CountGroupMembers(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return false
+	}
+	if count >= h.appConfig.MaxGroupSize {
+		utils.SendError(c, apierrors.ErrGroupFull.Msgf("group cannot have more than %d members", h.appConfig.MaxGroupSize))
+		return false
+	}
+	return true
+}
@@ -1,9 +1,14 @@
 package v1
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
 	"github.com/pranaovs/qashare/config"
 	"github.com/pranaovs/qashare/db"
@@ -17,11 +22,12 @@ import (
 
 type MeHandler struct {
 	pool      *pgxpool.Pool
+	readPool  *pgxpool.Pool // Read replica for reporting queries; the primary if none is configured
 	appConfig config.AppConfig
 }
 
-func NewMeHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *MeHandler {
-	return &MeHandler{pool: pool, appConfig: appConfig}
+func NewMeHandler(pool, readPool *pgxpool.Pool, appConfig config.AppConfig) *MeHandler {
+	return &MeHandler{pool: pool, readPool: readPool, appConfig: appConfig}
 }
 
 // Me godoc
@@ -54,11 +60,11 @@ func (h *MeHandler) Me(c *gin.Context) {
 
 // GetGroups godoc
 // @Summary List user's groups
-// @Description Get all groups the logged in user is a member of
+// @Description Get all groups the logged in user is a member of. Groups the user pinned (PUT /groups/{id}/pin) come first, then newest first.
 // @Tags me
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.Group "Returns list of groups the user is a member of"
+// @Success 200 {array} models.MemberGroup "Returns list of groups the user is a member of"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
@@ -74,6 +80,164 @@ func (h *MeHandler) GetGroups(c *gin.Context) {
 	utils.SendJSON(c, http.StatusOK, groups)
 }
 
+// GetContacts godoc
+// @Summary List user's contacts
+// @Description Get the de-duplicated list of users that share at least one group with the authenticated user, ordered by name. Results are paginated; pass next_cursor back as cursor to get the next page.
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Param name query string false "Only include users whose name contains this text (case-insensitive)"
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} object{items=[]models.User,next_cursor=string} "Returns a page of related users"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid limit or cursor"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/contacts [get]
+func (h *MeHandler) GetContacts(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	limit, cursor, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	contacts, nextCursor, err := db.GetRelatedUsers(c.Request.Context(), h.readPool, userID, strings.TrimSpace(c.Query("name")), limit, cursor)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendPaginated(c, contacts, nextCursor)
+}
+
+// notificationsCacheMaxAge is how long clients may reuse a notification count before polling again.
+const notificationsCacheMaxAge = 30
+
+// GetNotificationCount godoc
+// @Summary Get notification badge counts
+// @Description Get the number of groups in which the authenticated user has an unsettled balance, split into groups where they owe and groups where they are owed.
+// @Description Also counts pending drafts (incomplete expenses the user added or takes part in) and overdue splits (the user's owed splits in expenses older than OVERDUE_SPLIT_AGE).
+// @Description Balances within the split tolerance count as settled. Responses may be cached by the client for a short interval.
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationCounts "Returns the badge counts"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/notifications/count [get]
+func (h *MeHandler) GetNotificationCount(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	counts, err := db.GetNotificationCounts(c.Request.Context(), h.readPool, userID, h.appConfig.SplitTolerance, h.appConfig.OverdueSplitAge)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", notificationsCacheMaxAge))
+	utils.SendData(c, counts)
+}
+
+// GetBalanceHistory godoc
+// @Summary Get balance history
+// @Description Get the authenticated user's net position across all groups over time, one point per interval.
+// @Description Intervals without activity are included with a zero change, so the series can be charted directly. A positive balance means the user is owed money overall.
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Param interval query string false "Bucket size: day, week or month (default week)"
+// @Success 200 {array} models.BalancePoint "Returns the balance history, oldest first"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Unknown interval"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/balance-history [get]
+func (h *MeHandler) GetBalanceHistory(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	history, err := db.GetUserBalanceHistory(c.Request.Context(), h.readPool, userID, c.DefaultQuery("interval", db.IntervalWeek))
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendData(c, history)
+}
+
+// GetSpending godoc
+// @Summary Get spending across groups
+// @Description Get how much the authenticated user has paid and owes across all their groups, with a per-group breakdown listing pinned groups first, then by name.
+// @Description total_paid and total_owed include settlements, so they reflect the user's balance. net_spending excludes settlements and only counts the user's share of real expenses. Overall totals are given per group currency, sorted by currency, since amounts in different currencies are not added up.
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.UserSpending "Returns the totals per currency and one entry per group"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/spending [get]
+func (h *MeHandler) GetSpending(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	spending, err := db.GetUserTotalSpending(c.Request.Context(), h.readPool, userID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendData(c, spending)
+}
+
+// GetSpendByCategory godoc
+// @Summary Get spend by category
+// @Description Get the authenticated user's share of expenses across all groups, grouped by category. Amounts are the user's owed splits, so only what they consumed is counted, not what they paid for others.
+// @Description Settlements are excluded. Totals are reported per group currency since groups may use different currencies.
+// @Tags me
+// @Produce json
+// @Security BearerAuth
+// @Param from query int false "Created at or after (unix seconds)"
+// @Param to query int false "Created at or before (unix seconds)"
+// @Param include_uncategorized query bool false "Include expenses without a category under a null category (default true)"
+// @Success 200 {array} models.CategorySpend "Returns the spend per category, largest first"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid date or boolean value, or inverted date range"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/spend-by-category [get]
+func (h *MeHandler) GetSpendByCategory(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+
+	from, ok := parseInt64Query(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseInt64Query(c, "to")
+	if !ok {
+		return
+	}
+	includeUncategorized, ok := parseBoolQuery(c, "include_uncategorized", true)
+	if !ok {
+		return
+	}
+
+	spend, err := db.GetUserSpendByCategory(c.Request.Context(), h.readPool, userID, from, to, includeUncategorized)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendData(c, spend)
+}
+
 // GetOwner godoc
 // @Summary List groups user owns
 // @Description Get all groups that the authenticated user created (is owner of)
@@ -100,13 +264,18 @@ func (h *MeHandler) GetOwner(c *gin.Context) {
 // Update godoc
 // @Summary Update current user (full replacement)
 // @Description Update the authenticated user's editable details. This is a full replacement, so all required fields (name and email) must be provided. Immutable fields will be ignored if included in the request body.
+// @Description default_currency (ISO 4217) and locale (BCP 47) are optional; omitting them clears them.
+// @Description With email verification enabled, a new email is not applied right away: it is returned as pending_email and a verification link is sent to it. The current email keeps working until the link is used. Submitting the current email cancels a pending change.
 // @Tags me
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body models.User true "Updated user details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.User "Returns updated user"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect"
+// @Success 202 {object} models.User "Returns updated user; the new email is pending verification"
+// @Header 202 {string} X-Verification-Warning "Set if the verification email could not be sent; request a new one with POST /v1/auth/send-verification"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or missing required fields | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect | BAD_CURRENCY: default_currency is not a 3-letter ISO 4217 code | BAD_LOCALE: locale is not a BCP 47 language tag"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
 // @Failure 404 {object} apierrors.AppError "USER_NOT_FOUND: The authenticated user no longer exists"
@@ -117,8 +286,7 @@ func (h *MeHandler) Update(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 
 	var payload models.User
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &payload, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -131,7 +299,9 @@ func (h *MeHandler) Update(c *gin.Context) {
 	validatedName, err := utils.ValidateName(payload.Name)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			utils.ErrInvalidName: apierrors.ErrInvalidName,
+			utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+			utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+			utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
 		}))
 		return
 	}
@@ -146,6 +316,10 @@ func (h *MeHandler) Update(c *gin.Context) {
 	}
 	payload.Email = validatedEmail
 
+	if !validateUserPreferences(c, &payload) {
+		return
+	}
+
 	// Fetch current user to restore immutable fields in the response
 	current, err := db.GetUser(c.Request.Context(), h.pool, userID)
 	if err != nil {
@@ -158,8 +332,25 @@ func (h *MeHandler) Update(c *gin.Context) {
 
 	// Restore immutable fields from the current user
 	utils.RestoreImmutableFields(&payload, &current)
+	// The submitted email replaces any pending change; resubmitting the current one cancels it
+	payload.PendingEmail = nil
+
+	h.saveUser(c, &payload)
+}
 
-	err = db.UpdateUser(c.Request.Context(), h.pool, &payload)
+// verificationWarningHeader is set on a 202 response when the verification email for a
+// new address could not be sent.
+const verificationWarningHeader = "X-Verification-Warning"
+
+// saveUser stores an updated user and writes the response. With email verification enabled,
+// a changed email is held as pending and a verification email is sent to the new address.
+func (h *MeHandler) saveUser(c *gin.Context, user *models.User) {
+	var verificationExpiry time.Duration
+	if h.appConfig.Verification {
+		verificationExpiry = h.appConfig.VerifyEmailExpiry
+	}
+
+	token, err := db.UpdateUser(c.Request.Context(), h.pool, user, verificationExpiry)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound:     apierrors.ErrUserNotFound,
@@ -169,19 +360,36 @@ func (h *MeHandler) Update(c *gin.Context) {
 		return
 	}
 
-	utils.SendJSON(c, http.StatusOK, payload)
+	if token != uuid.Nil {
+		// The pending email is already stored, so a failed send is reported rather than
+		// failing the update; the user can ask for a new link.
+		err = utils.SendVerificationEmail(*user.PendingEmail, token, h.appConfig.VerifyEmailExpiry)
+		if err != nil {
+			slog.Error("Failed to send verification email for email change",
+				"userID", user.UserID, "to", *user.PendingEmail, "error", err)
+			c.Header(verificationWarningHeader, "verification email could not be sent; resend it with POST /v1/auth/send-verification")
+		}
+		utils.SendJSON(c, http.StatusAccepted, user)
+		return
+	}
+
+	utils.SendJSON(c, http.StatusOK, user)
 }
 
 // Patch godoc
 // @Summary Partially update current user
 // @Description Update specific fields of the authenticated user. Only provided fields are updated, others remain unchanged. Immutable fields (like user_id) will be ignored if included in the request body.
+// @Description With email verification enabled, a new email is not applied right away: it is returned as pending_email and a verification link is sent to it. The current email keeps working until the link is used. Submitting the current email cancels a pending change.
 // @Tags me
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param request body models.UserPatch true "Partial user details (name and/or email, all optional)"
+// @Param request body models.UserPatch true "Partial user details (name, email, default_currency and locale, all optional; an empty default_currency or locale clears it)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.User "Returns updated user"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or validation failed | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect"
+// @Success 202 {object} models.User "Returns updated user; the new email is pending verification"
+// @Header 202 {string} X-Verification-Warning "Set if the verification email could not be sent; request a new one with POST /v1/auth/send-verification"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or validation failed | NAME_TOO_SHORT: Name is empty or too short | NAME_TOO_LONG: Name is too long | BAD_NAME: The name provided contains invalid characters | BAD_EMAIL: The email format is incorrect | BAD_CURRENCY: default_currency is not a 3-letter ISO 4217 code | BAD_LOCALE: locale is not a BCP 47 language tag"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
 // @Failure 404 {object} apierrors.AppError "USER_NOT_FOUND: The authenticated user no longer exists"
@@ -192,8 +400,7 @@ func (h *MeHandler) Patch(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 
 	var patch models.UserPatch
-	if err := c.ShouldBindJSON(&patch); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &patch, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -202,7 +409,9 @@ func (h *MeHandler) Patch(c *gin.Context) {
 		validatedName, err := utils.ValidateName(*patch.Name)
 		if err != nil {
 			utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-				utils.ErrInvalidName: apierrors.ErrInvalidName,
+				utils.ErrNameTooShort:     apierrors.ErrNameTooShort,
+				utils.ErrNameTooLong:      apierrors.ErrNameTooLong,
+				utils.ErrNameInvalidChars: apierrors.ErrInvalidName,
 			}))
 			return
 		}
@@ -235,18 +444,16 @@ func (h *MeHandler) Patch(c *gin.Context) {
 		utils.SendError(c, apierrors.ErrBadRequest)
 		return
 	}
+	// A submitted email replaces any pending change; resubmitting the current one cancels it
+	if patch.Email != nil {
+		current.PendingEmail = nil
+	}
 
-	err = db.UpdateUser(c.Request.Context(), h.pool, &current)
-	if err != nil {
-		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
-			db.ErrNotFound:     apierrors.ErrUserNotFound,
-			db.ErrInvalidInput: apierrors.ErrBadRequest,
-			db.ErrDuplicateKey: apierrors.ErrEmailAlreadyExists,
-		}))
+	if !validateUserPreferences(c, &current) {
 		return
 	}
 
-	utils.SendJSON(c, http.StatusOK, current)
+	h.saveUser(c, &current)
 }
 
 // Delete godoc
@@ -285,3 +492,102 @@ func (h *MeHandler) Delete(c *gin.Context) {
 
 	utils.SendOK(c, "account deleted")
 }
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the authenticated user's password. The current password must be supplied.
+// @Description By default every other session is logged out; the session making the request stays valid. Set revoke_other_sessions to false to keep them.
+// @Tags me
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object{current_password=string,new_password=string,revoke_other_sessions=bool} true "Current and new password"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} map[string]string "Returns success message"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or the account has no password (guest) | BAD_PASSWORD: The new password does not meet the password policy"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid | BAD_CREDENTIALS: The current password is incorrect"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "USER_NOT_FOUND: The authenticated user no longer exists in the database"
+// @Failure 429 {object} apierrors.AppError "TOO_MANY_REQUESTS: Rate limit exceeded, retry after the number of seconds in the Retry-After header"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/me/password [post]
+func (h *MeHandler) ChangePassword(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	sessionID := middleware.MustGetSessionID(c)
+
+	var request struct {
+		CurrentPassword     string `json:"current_password" binding:"required"`
+		NewPassword         string `json:"new_password" binding:"required"`
+		RevokeOtherSessions *bool  `json:"revoke_other_sessions"`
+	}
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
+		return
+	}
+
+	savedPassword, err := db.GetUserPasswordHash(c.Request.Context(), h.pool, userID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrUserNotFound,
+			db.ErrInvalidInput: apierrors.ErrBadRequest.Msg("guest accounts have no password to change"),
+		}))
+		return
+	}
+
+	if !utils.CheckPassword(request.CurrentPassword, savedPassword) {
+		utils.SendError(c, apierrors.ErrBadCredentials)
+		return
+	}
+
+	if err := utils.ValidatePassword(request.NewPassword, h.appConfig.PasswordPolicy); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPassword: apierrors.ErrInvalidPassword,
+		}))
+		return
+	}
+
+	passwordHash, err := utils.HashPassword(request.NewPassword)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidPassword: apierrors.ErrInvalidPassword,
+			utils.ErrHashingFailed:   apierrors.ErrInternalServer,
+		}))
+		return
+	}
+
+	var keepSessionID *uuid.UUID
+	if request.RevokeOtherSessions == nil || *request.RevokeOtherSessions {
+		keepSessionID = &sessionID
+	}
+
+	if err := db.UpdateUserPassword(c.Request.Context(), h.pool, userID, passwordHash, keepSessionID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotFound,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "password changed")
+}
+
+// validateUserPreferences normalizes the user's default currency and locale, clearing empty ones.
+// Sends an error and returns false if either is invalid.
+func validateUserPreferences(c *gin.Context, user *models.User) bool {
+	var err error
+	user.Currency, err = utils.ValidateOptionalCurrency(user.Currency)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidCurrency: apierrors.ErrInvalidCurrency,
+		}))
+		return false
+	}
+
+	user.Locale, err = utils.ValidateLocale(user.Locale)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidLocale: apierrors.ErrInvalidLocale,
+		}))
+		return false
+	}
+
+	return true
+}
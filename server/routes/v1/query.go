@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// Pagination limits for list endpoints
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// parsePagination reads the limit and cursor query parameters.
+// limit defaults to defaultPageLimit and must be between 1 and maxPageLimit.
+// Sends ErrBadRequest and returns ok=false if limit is invalid.
+func parsePagination(c *gin.Context) (limit int, cursor string, ok bool) {
+	limit = defaultPageLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxPageLimit {
+			utils.SendError(c, apierrors.ErrBadRequest.Msgf("limit must be between 1 and %d", maxPageLimit))
+			return 0, "", false
+		}
+		limit = parsed
+	}
+	return limit, c.Query("cursor"), true
+}
+
+// parseBoolQuery reads an optional boolean query parameter.
+// Returns fallback when the parameter is absent.
+// Sends ErrBadRequest and returns ok=false if the value is not a valid boolean.
+func parseBoolQuery(c *gin.Context, key string, fallback bool) (value bool, ok bool) {
+	raw, exists := c.GetQuery(key)
+	if !exists || raw == "" {
+		return fallback, true
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid value for %s: must be true or false", key))
+		return false, false
+	}
+	return value, true
+}
+
+// parseExpandUsers reads the expand query parameter of endpoints that can embed user details.
+// The only supported value is "users".
+// Sends ErrBadRequest and returns ok=false for any other value.
+func parseExpandUsers(c *gin.Context) (expand bool, ok bool) {
+	switch raw := c.Query("expand"); raw {
+	case "":
+		return false, true
+	case "users":
+		return true, true
+	default:
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid value for expand: %q, must be users", raw))
+		return false, false
+	}
+}
+
+// parseUUIDQuery reads an optional UUID query parameter.
+// Returns nil when the parameter is absent or empty.
+// Sends ErrBadRequest and returns ok=false if the value is not a valid UUID.
+func parseUUIDQuery(c *gin.Context, key string) (value *uuid.UUID, ok bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, true
+	}
+
+	parsed, err := uuid.Parse(raw)
+	if err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid value for %s: must be a UUID", key))
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// parseFloatQuery reads an optional float query parameter.
+// Returns nil when the parameter is absent or empty.
+// Sends ErrBadRequest and returns ok=false if the value is not a valid number.
+func parseFloatQuery(c *gin.Context, key string) (value *float64, ok bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, true
+	}
+
+	parsed, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid value for %s: must be a number", key))
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// parseInt64Query reads an optional integer query parameter.
+// Returns nil when the parameter is absent or empty.
+// Sends ErrBadRequest and returns ok=false if the value is not a valid integer.
+func parseInt64Query(c *gin.Context, key string) (value *int64, ok bool) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, true
+	}
+
+	parsed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid value for %s: must be an integer", key))
+		return nil, false
+	}
+	return &parsed, true
+}
@@ -8,22 +8,31 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, pool *pgxpool.Pool, appConfig config.AppConfig, jwtConfig config.JWTConfig) {
+func RegisterRoutes(router *gin.RouterGroup, pool, readPool *pgxpool.Pool, appConfig config.AppConfig, jwtConfig config.JWTConfig) {
 	authHandler := NewAuthHandler(pool, appConfig, jwtConfig)
-	meHandler := NewMeHandler(pool, appConfig)
+	meHandler := NewMeHandler(pool, readPool, appConfig)
 	usersHandler := NewUsersHandler(pool, appConfig)
-	groupsHandler := NewGroupsHandler(pool, appConfig)
+	groupsHandler := NewGroupsHandler(pool, readPool, appConfig)
 	expensesHandler := NewExpensesHandler(pool, appConfig)
 	settlementsHandler := NewSettlementsHandler(pool, appConfig)
 
+	rateLimitStore := middleware.NewRateLimitStore(pool, appConfig)
+	authRateLimit := middleware.RateLimit(rateLimitStore, middleware.ClientIPKey, appConfig.RateLimitRequests, appConfig.RateLimitWindow)
+	// Limits guessing from a stolen session that spreads its requests over many addresses
+	userRateLimit := middleware.RateLimit(rateLimitStore, middleware.ClientUserKey, appConfig.RateLimitRequests, appConfig.RateLimitWindow)
+
 	// Auth (no auth middleware on most routes)
 	auth := router.Group("/auth")
-	auth.POST("/register", authHandler.Register)
+	auth.POST("/register", authRateLimit, authHandler.Register)
 	auth.GET("/verify", authHandler.Verify)
-	auth.POST("/login", authHandler.Login)
-	auth.POST("/refresh", authHandler.Refresh)
+	auth.POST("/verify-email", authRateLimit, authHandler.VerifyEmail)
+	auth.POST("/send-verification", middleware.RequireAuth(jwtConfig), authRateLimit, authHandler.SendVerification)
+	auth.POST("/login", authRateLimit, authHandler.Login)
+	auth.GET("/guest/claim/status", authRateLimit, authHandler.GetGuestClaimStatus)
+	auth.POST("/refresh", authRateLimit, authHandler.Refresh)
 	auth.POST("/logout", middleware.RequireAuth(jwtConfig), authHandler.Logout)
 	auth.POST("/logout-all", middleware.RequireAuth(jwtConfig), authHandler.LogoutAll)
+	auth.GET("/introspect", middleware.RequireAuth(jwtConfig), authHandler.Introspect)
 
 	// Me
 	me := router.Group("/me")
@@ -32,44 +41,93 @@ func RegisterRoutes(router *gin.RouterGroup, pool *pgxpool.Pool, appConfig confi
 	me.PUT("/", meHandler.Update)
 	me.PATCH("/", meHandler.Patch)
 	me.DELETE("/", meHandler.Delete)
+	me.POST("/password", authRateLimit, userRateLimit, meHandler.ChangePassword)
 	me.GET("/groups", meHandler.GetGroups)
 	me.GET("/admin", meHandler.GetOwner)
+	me.GET("/contacts", meHandler.GetContacts)
+	me.GET("/notifications/count", meHandler.GetNotificationCount)
+	me.GET("/balance-history", meHandler.GetBalanceHistory)
+	me.GET("/spending", meHandler.GetSpending)
+	me.GET("/spend-by-category", meHandler.GetSpendByCategory)
 
 	// Users
 	users := router.Group("/users")
-	users.Use(middleware.RequireAuth(jwtConfig))
+	users.Use(middleware.RequireAuth(jwtConfig), middleware.ValidateUUIDParam("id"))
 	users.GET("/:id", usersHandler.Get)
 	users.GET("/search/email/:email", usersHandler.SearchByEmail)
 	users.POST("/guest", usersHandler.RegisterGuest)
 
 	// Groups
 	groups := router.Group("/groups")
-	groups.Use(middleware.RequireAuth(jwtConfig))
-	groups.POST("/", groupsHandler.Create)
+	groups.Use(middleware.RequireAuth(jwtConfig), middleware.ValidateUUIDParam("id", "user_id", "invite_id", "webhook_id"))
+	groups.POST("/", middleware.RequireVerifiedEmail(pool, appConfig), groupsHandler.Create)
+	groups.GET("/invites/:token", groupsHandler.PeekInvite)
 	groups.GET("/:id", middleware.RequireGroupMember(pool), groupsHandler.Get)
+	groups.GET("/:id/public", groupsHandler.GetPublic)
 	groups.PUT("/:id", middleware.RequireGroupAdmin(pool), groupsHandler.Update)
 	groups.PATCH("/:id", middleware.RequireGroupAdmin(pool), groupsHandler.Patch)
 	groups.DELETE("/:id", middleware.RequireGroupAdmin(pool), groupsHandler.Delete)
+	groups.GET("/:id/members", middleware.RequireGroupMember(pool), groupsHandler.GetMembers)
 	groups.POST("/:id/members", middleware.RequireGroupAdmin(pool), groupsHandler.AddMembers)
 	groups.DELETE("/:id/members", middleware.RequireGroupAdmin(pool), groupsHandler.RemoveMembers)
+	groups.POST("/:id/invites", middleware.RequireGroupAdmin(pool), groupsHandler.CreateInvite)
+	groups.DELETE("/:id/invites/:invite_id", middleware.RequireGroupAdmin(pool), groupsHandler.DeleteInvite)
+	groups.GET("/:id/webhooks", middleware.RequireGroupAdmin(pool), groupsHandler.GetWebhooks)
+	groups.POST("/:id/webhooks", middleware.RequireGroupAdmin(pool), groupsHandler.CreateWebhook)
+	groups.DELETE("/:id/webhooks/:webhook_id", middleware.RequireGroupAdmin(pool), groupsHandler.DeleteWebhook)
+	groups.GET("/:id/webhooks/:webhook_id/failures", middleware.RequireGroupAdmin(pool), groupsHandler.GetWebhookFailures)
+	groups.POST("/:id/leave", middleware.RequireGroupMember(pool), groupsHandler.Leave)
+	groups.GET("/:id/members/count", middleware.RequireGroupMember(pool), groupsHandler.GetMemberCount)
+	groups.PUT("/:id/pin", middleware.RequireGroupMember(pool), groupsHandler.Pin)
+	groups.GET("/:id/me/owed-total", middleware.RequireGroupMember(pool), groupsHandler.GetOwedTotal)
+	groups.GET("/:id/activity", middleware.RequireGroupMember(pool), groupsHandler.GetActivity)
 	groups.GET("/:id/expenses", middleware.RequireGroupMember(pool), groupsHandler.GetExpenses)
 	groups.POST("/:id/expenses", middleware.RequireGroupMember(pool), expensesHandler.Create)
+	groups.DELETE("/:id/expenses", middleware.RequireGroupAdmin(pool), expensesHandler.DeleteAll)
+	groups.GET("/:id/expenses/search", middleware.RequireGroupMember(pool), groupsHandler.SearchExpenses)
+	groups.GET("/:id/expenses/trash", middleware.RequireGroupMember(pool), groupsHandler.GetDeletedExpenses)
+	groups.GET("/:id/categories", middleware.RequireGroupMember(pool), groupsHandler.GetCategories)
+	groups.GET("/:id/expenses/seq/:seq", middleware.RequireGroupMember(pool), groupsHandler.GetExpenseBySeq)
 	groups.GET("/:id/settle", middleware.RequireGroupMember(pool), groupsHandler.GetSettle)
 	groups.POST("/:id/settle", middleware.RequireGroupMember(pool), settlementsHandler.Create)
+	groups.POST("/:id/settle/partial", middleware.RequireGroupMember(pool), settlementsHandler.CreatePartial)
+	groups.GET("/:id/settle/:user_id/explain", middleware.RequireGroupMember(pool), groupsHandler.ExplainSettle)
+	groups.GET("/:id/settle/recent", middleware.RequireGroupMember(pool), groupsHandler.GetRecentCounterparties)
+	groups.GET("/:id/settle/export", middleware.RequireGroupMember(pool), groupsHandler.ExportSettle)
+	groups.GET("/:id/settle/ical", middleware.RequireGroupMember(pool), groupsHandler.ExportSettleICal)
 	groups.GET("/:id/settlements", middleware.RequireGroupMember(pool), groupsHandler.GetSettlements)
+	groups.GET("/:id/budget", middleware.RequireGroupMember(pool), groupsHandler.GetBudget)
+	groups.PUT("/:id/budget", middleware.RequireGroupAdmin(pool), groupsHandler.SetBudget)
+	groups.GET("/:id/summary", middleware.RequireGroupMember(pool), groupsHandler.GetSummary)
 	groups.GET("/:id/spendings", middleware.RequireGroupMember(pool), groupsHandler.GetSpendings)
 
+	// Invites
+	invites := router.Group("/invites")
+	invites.Use(middleware.RequireAuth(jwtConfig))
+	invites.POST("/:token/accept", groupsHandler.AcceptInvite)
+
 	// Expenses (individual)
 	expenses := router.Group("/expenses")
-	expenses.Use(middleware.RequireAuth(jwtConfig))
+	expenses.Use(middleware.RequireAuth(jwtConfig), middleware.ValidateUUIDParam("id", "user_id", "attachment_id"))
+	expenses.POST("/batch-get", expensesHandler.BatchGet)
+	expenses.POST("/bulk-delete", expensesHandler.BulkDelete)
+	expenses.POST("/preview-splits", expensesHandler.PreviewSplits)
 	expenses.GET("/:id", middleware.VerifyExpenseAccess(pool), expensesHandler.Get)
+	expenses.GET("/:id/context", middleware.VerifyExpenseAccess(pool), expensesHandler.GetContext)
+	expenses.GET("/:id/remaining", middleware.VerifyExpenseAccess(pool), expensesHandler.GetRemaining)
 	expenses.PUT("/:id", middleware.VerifyExpenseAdmin(pool), expensesHandler.Update)
 	expenses.PATCH("/:id", middleware.VerifyExpenseAdmin(pool), expensesHandler.Patch)
 	expenses.DELETE("/:id", middleware.VerifyExpenseDeleteAccess(pool), expensesHandler.Delete)
+	expenses.POST("/:id/finalize", middleware.VerifyExpenseAdmin(pool), expensesHandler.Finalize)
+	expenses.POST("/:id/restore", middleware.VerifyExpenseRestoreAccess(pool), expensesHandler.Restore)
+	expenses.PATCH("/:id/splits/:user_id", middleware.VerifyExpenseAccess(pool), expensesHandler.UpdateSplitPaid)
+	expenses.GET("/:id/attachments", middleware.VerifyExpenseAccess(pool), expensesHandler.ListAttachments)
+	expenses.POST("/:id/attachments", middleware.VerifyExpenseAccess(pool), expensesHandler.AddAttachment)
+	expenses.DELETE("/:id/attachments/:attachment_id", middleware.VerifyExpenseAccess(pool), expensesHandler.DeleteAttachment)
 
 	// Settlements (individual)
 	settlements := router.Group("/settlements")
-	settlements.Use(middleware.RequireAuth(jwtConfig))
+	settlements.Use(middleware.RequireAuth(jwtConfig), middleware.ValidateUUIDParam("id"))
 	settlements.GET("/:id", middleware.VerifySettlementAccess(pool), settlementsHandler.Get)
 	settlements.PUT("/:id", middleware.VerifySettlementAdmin(pool), settlementsHandler.Update)
 	settlements.PATCH("/:id", middleware.VerifySettlementAdmin(pool), settlementsHandler.Patch)
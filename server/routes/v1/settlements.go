@@ -1,8 +1,13 @@
 package v1
 
 import (
+	"encoding/csv"
+	"fmt"
 	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
@@ -29,11 +34,14 @@ func NewSettlementsHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *Sett
 // GetSettle godoc
 // @Summary Get payment settlements for a group
 // @Description Get the payment balances between the authenticated user and all other members in a group. Positive amount means other user owes you, negative means you owe them.
+// @Description With simplify=true (default), debts are minimized across the whole group, so you may be asked to pay someone you never shared an expense with. With simplify=false, the direct net balance with each member is returned instead. The sign convention is the same in both modes.
 // @Tags settlements
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
+// @Param simplify query bool false "Minimize transactions across the group (default true)"
 // @Success 200 {array} models.Settlement "List of non-zero settlement balances"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid simplify value"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group"
 // @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
@@ -43,8 +51,13 @@ func (h *GroupsHandler) GetSettle(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 	groupID := middleware.MustGetGroupID(c)
 
+	simplify, ok := parseBoolQuery(c, "simplify", true)
+	if !ok {
+		return
+	}
+
 	// Get settlements
-	settlements, err := db.GetSettlement(c.Request.Context(), h.pool, userID, groupID, h.appConfig.SplitTolerance)
+	settlements, err := db.GetSettlement(c.Request.Context(), h.readPool, userID, groupID, h.appConfig.SplitTolerance, simplify)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrInvalidInput: apierrors.ErrBadRequest,
@@ -55,14 +68,288 @@ func (h *GroupsHandler) GetSettle(c *gin.Context) {
 	utils.SendData(c, settlements)
 }
 
+// ExplainSettle godoc
+// @Summary Explain the settlement with one member
+// @Description Restate the authenticated user's balance with one group member as an explicit direction and a positive amount, so clients don't have to interpret the sign of GET /groups/{id}/settle.
+// @Description Uses the same balances as GET /groups/{id}/settle, including the simplify parameter. A member with no balance is reported as settled.
+// @Tags settlements
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param user_id path string true "User ID of the counterparty"
+// @Param simplify query bool false "Minimize transactions across the group (default true)"
+// @Success 200 {object} models.SettlementExplanation "Direction and amount of the balance with the member"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid user ID or simplify value, or the user is the authenticated user"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group | USER_NOT_IN_GROUP: The counterparty is not a member of the group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/settle/{user_id}/explain [get]
+func (h *GroupsHandler) ExplainSettle(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	counterpartyID := middleware.MustGetUUIDParam(c, "user_id")
+	if counterpartyID == userID {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("cannot explain a settlement with yourself"))
+		return
+	}
+
+	simplify, ok := parseBoolQuery(c, "simplify", true)
+	if !ok {
+		return
+	}
+
+	group, err := db.GetGroup(c.Request.Context(), h.readPool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	explanation := models.SettlementExplanation{Direction: models.SettlementSettled, Currency: group.Currency}
+	found := false
+	for _, member := range group.Members {
+		if member.UserID == counterpartyID {
+			explanation.Counterparty = member
+			found = true
+			break
+		}
+	}
+	if !found {
+		utils.SendError(c, apierrors.ErrUserNotInGroup)
+		return
+	}
+
+	settlements, err := db.GetSettlement(c.Request.Context(), h.readPool, userID, groupID, h.appConfig.SplitTolerance, simplify)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	// Positive amounts are owed to the user, negative amounts are owed by them
+	for _, settlement := range settlements {
+		if settlement.UserID != counterpartyID {
+			continue
+		}
+		switch {
+		case settlement.Amount > 0:
+			explanation.Direction = models.SettlementOwesYou
+		case settlement.Amount < 0:
+			explanation.Direction = models.SettlementYouOwe
+		}
+		explanation.Amount = utils.RoundMoney(math.Abs(settlement.Amount))
+		break
+	}
+
+	utils.SendJSON(c, http.StatusOK, explanation)
+}
+
+// ExportSettle godoc
+// @Summary Export the group's settlement plan
+// @Description Download the optimized "who pays whom" plan for the whole group as a CSV file with a header row. The filename is derived from the group name.
+// @Tags settlements
+// @Produce text/csv
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param format query string false "Export format (only csv is supported)" default(csv)
+// @Success 200 {file} file "CSV with columns payer_id, payer_name, receiver_id, receiver_name, amount, currency (the group base currency)"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Unsupported export format"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/settle/export [get]
+func (h *GroupsHandler) ExportSettle(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" {
+		utils.SendError(c, apierrors.ErrBadRequest.Msgf("unsupported export format: %s", format))
+		return
+	}
+
+	group, err := db.GetGroup(c.Request.Context(), h.readPool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	plan, err := db.GetSettlementPlan(c.Request.Context(), h.readPool, groupID, h.appConfig.SplitTolerance)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(group.Members))
+	for _, member := range group.Members {
+		names[member.UserID] = member.Name
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-settlements.csv"`, exportFilename(group.Name)))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	rows := [][]string{{"payer_id", "payer_name", "receiver_id", "receiver_name", "amount", "currency"}}
+	for _, transfer := range plan {
+		rows = append(rows, []string{
+			transfer.FromUserID.String(),
+			names[transfer.FromUserID],
+			transfer.ToUserID.String(),
+			names[transfer.ToUserID],
+			strconv.FormatFloat(utils.RoundMoney(transfer.Amount), 'f', 2, 64),
+			group.Currency,
+		})
+	}
+	if err := w.WriteAll(rows); err != nil {
+		// Headers are already sent, so the error can only be logged
+		utils.LogError(c.Request.Context(), "failed to write settlement export", err)
+	}
+}
+
+// ExportSettleICal godoc
+// @Summary Download settlement reminders as a calendar
+// @Description Download an iCalendar (.ics) file with one all-day event and reminder for each member the authenticated user owes in the group, using the same balances as GET /groups/{id}/settle.
+// @Description Settlements have no due date, so events are dated on the day of the download. Amounts are formatted in the user's locale, if set. Event UIDs are stable per group and member, so re-importing updates the existing events.
+// @Tags settlements
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {file} file "iCalendar file with one VEVENT per debt"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/settle/ical [get]
+func (h *GroupsHandler) ExportSettleICal(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	group, err := db.GetGroup(c.Request.Context(), h.readPool, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	settlements, err := db.GetSettlement(c.Request.Context(), h.readPool, userID, groupID, h.appConfig.SplitTolerance, true)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	// Amounts are formatted in the user's locale, if they set one
+	user, err := db.GetUser(c.Request.Context(), h.readPool, userID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrUserNotFound,
+		}))
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(group.Members))
+	for _, member := range group.Members {
+		names[member.UserID] = member.Name
+	}
+
+	today := time.Now()
+	events := make([]utils.ICalEvent, 0, len(settlements))
+	for _, settlement := range settlements {
+		// Only debts of the user, positive amounts are owed to them
+		if settlement.Amount >= 0 {
+			continue
+		}
+		amount := utils.FormatMoney(-settlement.Amount, group.Currency, user.Locale)
+		events = append(events, utils.ICalEvent{
+			UID:         fmt.Sprintf("settle-%s-%s-%s@%s", groupID, userID, settlement.UserID, exportFilename(h.appConfig.CustomName)),
+			Date:        today,
+			Summary:     fmt.Sprintf("Pay %s %s", names[settlement.UserID], amount),
+			Description: fmt.Sprintf("You owe %s %s in %s.", names[settlement.UserID], amount, group.Name),
+		})
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-settlements.ics"`, exportFilename(group.Name)))
+	c.Status(http.StatusOK)
+
+	if err := utils.WriteICal(c.Writer, "-//"+h.appConfig.CustomName+"//Settlements//EN", events); err != nil {
+		// Headers are already sent, so the error can only be logged
+		utils.LogError(c.Request.Context(), "failed to write settlement calendar", err)
+	}
+}
+
+// exportFilename turns a group name into a safe filename stem.
+func exportFilename(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '_':
+			b.WriteRune('-')
+		}
+	}
+	if b.Len() == 0 {
+		return "group"
+	}
+	return b.String()
+}
+
+// recentCounterpartiesLimit caps how many members the recent counterparties endpoint returns
+const recentCounterpartiesLimit = 5
+
+// GetRecentCounterparties godoc
+// @Summary Get recent counterparties in a group
+// @Description Get the group members the authenticated user most recently shared an expense or settlement with, most recent first. Intended to suggest who to settle with; at most 5 members are returned.
+// @Tags settlements
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {array} models.Counterparty "Members ordered by most recent shared activity"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/settle/recent [get]
+func (h *GroupsHandler) GetRecentCounterparties(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	counterparties, err := db.GetRecentCounterparties(c.Request.Context(), h.readPool, userID, groupID, recentCounterpartiesLimit)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendData(c, counterparties)
+}
+
 // GetSettlements godoc
 // @Summary Get settlement history for the current user in the group
-// @Description Get all settlement transactions where the authenticated user is a participant (payer or receiver)
+// @Description Get settlement transactions where the authenticated user is a participant (payer or receiver), newest first. Results are paginated; pass next_cursor back as cursor to get the next page.
+// @Description With detailed=true each entry is a models.SettlementDetails, which adds the expense ID and the raw splits the signed amount was derived from.
 // @Tags settlements
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
-// @Success 200 {array} models.Settlement "List of settlement history entries"
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Param detailed query bool false "Include the expense ID and splits of each settlement" default(false)
+// @Param with_user query string false "Only return settlements with this counterparty"
+// @Success 200 {object} object{items=[]models.Settlement,next_cursor=string} "Returns a page of settlement history entries"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid limit, cursor, detailed or with_user value"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user is not a member of the specified group"
 // @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
@@ -72,7 +359,20 @@ func (h *GroupsHandler) GetSettlements(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 	groupID := middleware.MustGetGroupID(c)
 
-	history, err := db.GetSettlements(c.Request.Context(), h.pool, userID, groupID)
+	limit, cursor, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+	detailed, ok := parseBoolQuery(c, "detailed", false)
+	if !ok {
+		return
+	}
+	withUser, ok := parseUUIDQuery(c, "with_user")
+	if !ok {
+		return
+	}
+
+	history, nextCursor, err := db.GetSettlements(c.Request.Context(), h.readPool, userID, groupID, withUser, limit, cursor)
 	if err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrInvalidInput: apierrors.ErrBadRequest,
@@ -80,25 +380,41 @@ func (h *GroupsHandler) GetSettlements(c *gin.Context) {
 		return
 	}
 
+	if detailed {
+		settlements := make([]models.SettlementDetails, len(history))
+		for i, exp := range history {
+			settlements[i] = models.SettlementDetails{
+				Settlement: ExpenseToSettlement(exp, userID),
+				ExpenseID:  exp.ExpenseID,
+				Splits:     exp.Splits,
+			}
+		}
+		utils.SendPaginated(c, settlements, nextCursor)
+		return
+	}
+
 	settlements := make([]models.Settlement, len(history))
 	for i, exp := range history {
 		settlements[i] = ExpenseToSettlement(exp, userID)
 	}
 
-	utils.SendData(c, settlements)
+	utils.SendPaginated(c, settlements, nextCursor)
 }
 
 // Create godoc
 // @Summary Settle a payment with another user in a group
 // @Description Settle a payment with another user in a group by specifying the user_id and amount. Positive amount means you are paying them, negative means they are paying you. The settlement is stored as an expense with is_settlement=true.
+// @Description Set client_ref (up to 64 characters, unique per group) to make retries safe: if a settlement with the same client_ref already exists in the group, it is returned with status 200 and nothing is created. The rest of the request is not compared against it.
 // @Tags settlements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Group ID"
 // @Param request body models.Settlement true "Settle payment request"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 200 {object} models.Settlement "Existing settlement with the same client_ref"
 // @Success 201 {object} models.Settlement "Created settlement expense with splits"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Cannot settle with yourself or missing group_id | INVALID_AMOUNT: Settlement amount cannot be zero"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Cannot settle with yourself, missing group_id, invalid client_ref, or client_ref belongs to a deleted settlement | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user or the other user is not a member of the specified group"
 // @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
@@ -109,11 +425,20 @@ func (h *SettlementsHandler) Create(c *gin.Context) {
 	groupID := middleware.MustGetGroupID(c)
 
 	var req models.Settlement
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
 		return
 	}
 
+	if req.ClientRef != nil {
+		if *req.ClientRef == "" || len(*req.ClientRef) > maxClientRefLength {
+			utils.SendError(c, apierrors.ErrBadRequest.Msgf("client_ref must be 1 to %d characters", maxClientRefLength))
+			return
+		}
+		if h.replaySettlement(c, groupID, userID, *req.ClientRef) {
+			return
+		}
+	}
+
 	if req.Amount == 0 {
 		utils.SendError(c, apierrors.ErrInvalidAmount.Msg("settlement amount cannot be zero"))
 		return
@@ -124,6 +449,13 @@ func (h *SettlementsHandler) Create(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidateTransactedAt(req.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
 	// Verify other user is a member of the group
 	isMember, err := db.MemberOfGroup(c.Request.Context(), h.pool, req.UserID, groupID)
 	if err != nil {
@@ -148,21 +480,138 @@ func (h *SettlementsHandler) Create(c *gin.Context) {
 		receiverID = userID
 	}
 
-	expense := models.ExpenseDetails{
-		Expense: models.Expense{
-			Title:        "Settlement",
-			GroupID:      groupID,
-			AddedBy:      userID,
-			Amount:       absAmount,
-			IsSettlement: true,
-			TransactedAt: req.TransactedAt,
-		},
-		Splits: []models.ExpenseSplit{
-			{UserID: payerID, Amount: absAmount, IsPaid: true},
-			{UserID: receiverID, Amount: absAmount, IsPaid: false},
-		},
+	expense := settlementExpense(groupID, userID, payerID, receiverID, absAmount, req.TransactedAt)
+	expense.ClientRef = req.ClientRef
+	if err := db.CreateExpense(c.Request.Context(), h.pool, &expense); err != nil {
+		// A concurrent retry created the settlement first
+		if db.IsDuplicate(err) && req.ClientRef != nil && h.replaySettlement(c, groupID, userID, *req.ClientRef) {
+			return
+		}
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusCreated, ExpenseToSettlement(expense, userID))
+}
+
+// maxClientRefLength bounds the client-supplied reference of a settlement.
+const maxClientRefLength = 64
+
+// replaySettlement responds with the group's settlement created with clientRef, if there is one.
+// Returns true if a response was sent, either the existing settlement or an error.
+func (h *SettlementsHandler) replaySettlement(c *gin.Context, groupID, userID uuid.UUID, clientRef string) bool {
+	expenseID, deleted, err := db.GetSettlementByClientRef(c.Request.Context(), h.pool, groupID, clientRef)
+	if db.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		utils.SendError(c, err)
+		return true
+	}
+	if deleted {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("client_ref belongs to a deleted settlement"))
+		return true
 	}
 
+	expense, err := db.GetExpense(c.Request.Context(), h.pool, expenseID)
+	if err != nil {
+		utils.SendError(c, err)
+		return true
+	}
+	utils.SendJSON(c, http.StatusOK, ExpenseToSettlement(expense, userID))
+	return true
+}
+
+// CreatePartial godoc
+// @Summary Settle part of a balance with another user
+// @Description Pay off part of the direct balance between the authenticated user and another member, e.g. half of what you owe them.
+// @Description The amount is always positive; the settlement follows the direction of the debt, so whoever owes pays. It is capped at the current direct balance (as returned by GET /groups/{id}/settle?simplify=false), so a balance is never overpaid.
+// @Description Returns the requested amount, the amount actually settled, and the created settlement.
+// @Tags settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body object{user_id=string,amount=number,transacted_at=int} true "Member to settle with, amount to settle and optional transaction time"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 201 {object} object{requested_amount=number,applied_amount=number,settlement=models.Settlement} "Created settlement with the requested and applied amounts"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Cannot settle with yourself, or there is nothing to settle with the user | INVALID_AMOUNT: Amount is not positive | BAD_REQUEST: transacted_at is more than a day in the future"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | USERS_NOT_RELATED: The authenticated user or the other user is not a member of the specified group"
+// @Failure 500 {object} apierrors.AppError "Internal server error"
+// @Router /v1/groups/{id}/settle/partial [post]
+func (h *SettlementsHandler) CreatePartial(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	var req struct {
+		UserID       uuid.UUID `json:"user_id" binding:"required"`
+		Amount       float64   `json:"amount" binding:"required"`
+		TransactedAt *int64    `json:"transacted_at"`
+	}
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
+		return
+	}
+
+	if req.Amount <= 0 {
+		utils.SendError(c, apierrors.ErrInvalidAmount.Msg("amount must be positive"))
+		return
+	}
+
+	if req.UserID == userID {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("cannot settle with yourself"))
+		return
+	}
+
+	if err := utils.ValidateTransactedAt(req.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	isMember, err := db.MemberOfGroup(c.Request.Context(), h.pool, req.UserID, groupID)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+	if !isMember {
+		utils.SendError(c, apierrors.ErrUsersNotRelated.Msg("the other user is not a member of the group"))
+		return
+	}
+
+	// The direct balance with the other user; positive means they owe the authenticated user
+	balances, err := db.GetSettlement(c.Request.Context(), h.pool, userID, groupID, h.appConfig.SplitTolerance, false)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+	var balance float64
+	for _, b := range balances {
+		if b.UserID == req.UserID {
+			balance = b.Amount
+			break
+		}
+	}
+	if math.Abs(balance) <= h.appConfig.SplitTolerance {
+		utils.SendError(c, apierrors.ErrBadRequest.Msg("nothing to settle"))
+		return
+	}
+
+	applied := utils.RoundMoney(math.Min(req.Amount, math.Abs(balance)))
+
+	payerID, receiverID := userID, req.UserID
+	if balance > 0 {
+		payerID, receiverID = req.UserID, userID
+	}
+
+	expense := settlementExpense(groupID, userID, payerID, receiverID, applied, req.TransactedAt)
 	if err := db.CreateExpense(c.Request.Context(), h.pool, &expense); err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrInvalidInput: apierrors.ErrBadRequest,
@@ -170,7 +619,29 @@ func (h *SettlementsHandler) Create(c *gin.Context) {
 		return
 	}
 
-	utils.SendJSON(c, http.StatusCreated, ExpenseToSettlement(expense, userID))
+	utils.SendJSON(c, http.StatusCreated, gin.H{
+		"requested_amount": req.Amount,
+		"applied_amount":   applied,
+		"settlement":       ExpenseToSettlement(expense, userID),
+	})
+}
+
+// settlementExpense builds the expense that stores a settlement of amount from payerID to receiverID.
+func settlementExpense(groupID, addedBy, payerID, receiverID uuid.UUID, amount float64, transactedAt *int64) models.ExpenseDetails {
+	return models.ExpenseDetails{
+		Expense: models.Expense{
+			Title:        "Settlement",
+			GroupID:      groupID,
+			AddedBy:      addedBy,
+			Amount:       amount,
+			IsSettlement: true,
+			TransactedAt: transactedAt,
+		},
+		Splits: []models.ExpenseSplit{
+			{UserID: payerID, Amount: amount, IsPaid: true},
+			{UserID: receiverID, Amount: amount, IsPaid: false},
+		},
+	}
 }
 
 // ExpenseToSettlement converts an ExpenseDetails to a Settlement response.
@@ -183,6 +654,7 @@ func ExpenseToSettlement(expense models.ExpenseDetails, userID uuid.UUID) models
 			CreatedAt:    expense.CreatedAt,
 			TransactedAt: expense.TransactedAt,
 			GroupID:      expense.GroupID,
+			ClientRef:    expense.ClientRef,
 		}
 	}
 
@@ -210,6 +682,7 @@ func ExpenseToSettlement(expense models.ExpenseDetails, userID uuid.UUID) models
 		GroupID:      expense.GroupID,
 		UserID:       otherUserID,
 		Amount:       amount,
+		ClientRef:    expense.ClientRef,
 	}
 }
 
@@ -220,16 +693,39 @@ func ExpenseToSettlement(expense models.ExpenseDetails, userID uuid.UUID) models
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Settlement ID"
-// @Success 200 {object} models.Settlement "Returns settlement details"
+// @Param expand query string false "Set to users to embed the name, email, guest flag and joined_at of both parties" Enums(users)
+// @Success 200 {object} models.SettlementWithUsers "Returns settlement details; users is only present with expand=users"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid expand value"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
-// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not a member of the settlement's group"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist, the expense is not a settlement, or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/settlements/{id} [get]
 func (h *SettlementsHandler) Get(c *gin.Context) {
 	userID := middleware.MustGetUserID(c)
 	expense := middleware.MustGetExpense(c)
-	utils.SendData(c, ExpenseToSettlement(expense, userID))
+	settlement := ExpenseToSettlement(expense, userID)
+
+	expand, ok := parseExpandUsers(c)
+	if !ok {
+		return
+	}
+	if !expand {
+		utils.SendData(c, settlement)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(expense.Splits))
+	for _, split := range expense.Splits {
+		userIDs = append(userIDs, split.UserID)
+	}
+	users, err := db.GetGroupUsersByIDs(c.Request.Context(), h.pool, expense.GroupID, utils.GetUniqueUserIDs(userIDs))
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendData(c, models.SettlementWithUsers{Settlement: settlement, Users: users})
 }
 
 // Update godoc
@@ -241,11 +737,12 @@ func (h *SettlementsHandler) Get(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Settlement ID"
 // @Param request body models.Settlement true "Updated settlement details"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.Settlement "Returns updated settlement"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/settlements/{id} [put]
 func (h *SettlementsHandler) Update(c *gin.Context) {
@@ -254,8 +751,7 @@ func (h *SettlementsHandler) Update(c *gin.Context) {
 	expense := middleware.MustGetExpense(c)
 
 	var req models.Settlement
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -264,6 +760,13 @@ func (h *SettlementsHandler) Update(c *gin.Context) {
 		return
 	}
 
+	if err := utils.ValidateTransactedAt(req.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
 	// Extract current participants from existing splits
 	var currentPayerID, currentReceiverID uuid.UUID
 	for _, split := range expense.Splits {
@@ -321,7 +824,7 @@ func (h *SettlementsHandler) Update(c *gin.Context) {
 
 	utils.RestoreImmutableFields(&updated.Expense, &expense.Expense)
 
-	if err := db.UpdateExpense(c.Request.Context(), h.pool, &updated); err != nil {
+	if err := db.UpdateExpense(c.Request.Context(), h.pool, &updated, userID); err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound: apierrors.ErrExpenseNotFound,
 		}))
@@ -340,11 +843,12 @@ func (h *SettlementsHandler) Update(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Settlement ID"
 // @Param request body models.SettlementPatch true "Partial settlement details (all fields optional)"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 200 {object} models.Settlement "Returns updated settlement"
-// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body or cannot settle with yourself | INVALID_AMOUNT: Settlement amount cannot be zero | BAD_REQUEST: transacted_at is more than a day in the future"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/settlements/{id} [patch]
 func (h *SettlementsHandler) Patch(c *gin.Context) {
@@ -352,8 +856,7 @@ func (h *SettlementsHandler) Patch(c *gin.Context) {
 	expense := middleware.MustGetExpense(c)
 
 	var patch models.SettlementPatch
-	if err := c.ShouldBindJSON(&patch); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &patch, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -386,6 +889,13 @@ func (h *SettlementsHandler) Patch(c *gin.Context) {
 		patch.Amount = &absAmount
 	}
 
+	if err := utils.ValidateTransactedAt(patch.TransactedAt); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidTransactedAt: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
 	// Apply patch to expense (only non-nil fields are applied)
 	if err := utils.Patch(&expense.Expense, &patch); err != nil {
 		utils.SendError(c, apierrors.ErrBadRequest)
@@ -398,7 +908,7 @@ func (h *SettlementsHandler) Patch(c *gin.Context) {
 		{UserID: currentReceiverID, Amount: expense.Amount, IsPaid: false},
 	}
 
-	if err := db.UpdateExpense(c.Request.Context(), h.pool, &expense); err != nil {
+	if err := db.UpdateExpense(c.Request.Context(), h.pool, &expense, userID); err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound:     apierrors.ErrExpenseNotFound,
 			db.ErrInvalidInput: apierrors.ErrBadRequest,
@@ -419,13 +929,13 @@ func (h *SettlementsHandler) Patch(c *gin.Context) {
 // @Success 200 {object} map[string]string "Returns success message"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
 // @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the settlement payer"
-// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement"
+// @Failure 404 {object} apierrors.AppError "EXPENSE_NOT_FOUND: The settlement does not exist or the expense is not a settlement, or the user is not a member of its group"
 // @Failure 500 {object} apierrors.AppError "Internal server error"
 // @Router /v1/settlements/{id} [delete]
 func (h *SettlementsHandler) Delete(c *gin.Context) {
 	expense := middleware.MustGetExpense(c)
 
-	if err := db.DeleteExpense(c.Request.Context(), h.pool, expense.ExpenseID); err != nil {
+	if err := db.DeleteExpense(c.Request.Context(), h.pool, expense.ExpenseID, middleware.MustGetUserID(c)); err != nil {
 		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
 			db.ErrNotFound: apierrors.ErrExpenseNotFound,
 		}))
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/mail"
 
-	"github.com/google/uuid"
 	"github.com/pranaovs/qashare/apperrors"
 	"github.com/pranaovs/qashare/config"
 	"github.com/pranaovs/qashare/db"
@@ -42,13 +41,7 @@ func NewUsersHandler(pool *pgxpool.Pool, appConfig config.AppConfig) *UsersHandl
 // @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
 // @Router /v1/users/{id} [get]
 func (h *UsersHandler) Get(c *gin.Context) {
-	qUserIDStr := c.Param("id")
-
-	qUserID, err := uuid.Parse(qUserIDStr)
-	if err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest.Msgf("invalid UUID format: %s", qUserIDStr))
-		return
-	}
+	qUserID := middleware.MustGetUUIDParam(c, "id")
 
 	userID := middleware.MustGetUserID(c)
 
@@ -72,6 +65,8 @@ func (h *UsersHandler) Get(c *gin.Context) {
 		}))
 		return
 	}
+	// A pending email change is private to the user
+	result.PendingEmail = nil
 
 	utils.SendJSON(c, http.StatusOK, result)
 }
@@ -119,6 +114,7 @@ func (h *UsersHandler) SearchByEmail(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body object{email=string} true "Guest user email"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
 // @Success 201 {object} models.User "Guest user successfully created"
 // @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body format or missing required fields | BAD_EMAIL: Invalid email format"
 // @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
@@ -138,8 +134,7 @@ func (h *UsersHandler) RegisterGuest(c *gin.Context) {
 		Email string `json:"email" binding:"required,email"`
 	}
 
-	if err := c.ShouldBindJSON(&request); err != nil {
-		utils.SendError(c, apierrors.ErrBadRequest)
+	if !bindJSON(c, &request, h.appConfig.StrictJSON) {
 		return
 	}
 
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranaovs/qashare/apperrors"
+	"github.com/pranaovs/qashare/db"
+	"github.com/pranaovs/qashare/models"
+	"github.com/pranaovs/qashare/routes/apierrors"
+	"github.com/pranaovs/qashare/routes/middleware"
+	"github.com/pranaovs/qashare/utils"
+)
+
+// CreateWebhook godoc
+// @Summary Register a group webhook
+// @Description Subscribe a URL to events of the group (requires group admin permission). Events: expense.created, member.added, settlement.created. Private expenses are never delivered.
+// @Description Each event is POSTed as a models.WebhookPayload, asynchronously and with retries. The X-Qashare-Signature header holds "sha256=" and the hex HMAC-SHA256 of the body keyed with the secret, which is only returned here.
+// @Description A failed delivery is retried with exponential backoff (WEBHOOK_BASE_BACKOFF doubling up to WEBHOOK_MAX_BACKOFF) and dead-lettered after WEBHOOK_MAX_ATTEMPTS attempts; see the failures endpoint. A webhook that fails 20 deliveries in a row is disabled. Deliveries to loopback, private, link-local or unspecified addresses are refused, including hostnames that resolve to them.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param request body object{url=string,events=[]string} true "Target URL and subscribed events"
+// @Param strict query bool false "Reject fields not in the request schema instead of ignoring them (default STRICT_JSON)"
+// @Success 201 {object} models.Webhook "Returns the created webhook with its signing secret"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid request body | BAD_WEBHOOK: URL is not an absolute http or https URL, points to an internal address, or an event is unknown"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/webhooks [post]
+func (h *GroupsHandler) CreateWebhook(c *gin.Context) {
+	userID := middleware.MustGetUserID(c)
+	groupID := middleware.MustGetGroupID(c)
+
+	var req struct {
+		URL    string   `json:"url" binding:"required"`
+		Events []string `json:"events" binding:"required"`
+	}
+	if !bindJSON(c, &req, h.appConfig.StrictJSON) {
+		return
+	}
+
+	url, events, err := utils.ValidateWebhook(req.URL, req.Events, models.WebhookEvents)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			utils.ErrInvalidWebhook: apierrors.ErrInvalidWebhook,
+		}))
+		return
+	}
+
+	secret, err := utils.GenerateWebhookSecret()
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	webhook := models.Webhook{GroupID: groupID, URL: url, Events: events, Secret: secret}
+	if err := db.CreateWebhook(c.Request.Context(), h.pool, &webhook, userID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrGroupNotFound,
+		}))
+		return
+	}
+
+	utils.SendJSON(c, http.StatusCreated, webhook)
+}
+
+// GetWebhooks godoc
+// @Summary List group webhooks
+// @Description List the webhooks of the group with their failure counts (requires group admin permission). Secrets are not included.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Success 200 {array} models.Webhook "Returns the group's webhooks, oldest first"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "GROUP_NOT_FOUND: The specified group does not exist"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/webhooks [get]
+func (h *GroupsHandler) GetWebhooks(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	webhooks, err := db.GetWebhooks(c.Request.Context(), h.pool, groupID)
+	if err != nil {
+		utils.SendError(c, err)
+		return
+	}
+
+	utils.SendData(c, webhooks)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a group webhook
+// @Description Remove a webhook and drop its pending deliveries (requires group admin permission)
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} map[string]string "Returns success message"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid webhook ID"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "WEBHOOK_NOT_FOUND: The group has no webhook with this ID"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/webhooks/{webhook_id} [delete]
+func (h *GroupsHandler) DeleteWebhook(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	webhookID := middleware.MustGetUUIDParam(c, "webhook_id")
+
+	if err := db.DeleteWebhook(c.Request.Context(), h.pool, groupID, webhookID); err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound: apierrors.ErrWebhookNotFound,
+		}))
+		return
+	}
+
+	utils.SendOK(c, "webhook deleted")
+}
+
+// GetWebhookFailures godoc
+// @Summary List failed webhook deliveries
+// @Description List the dead-lettered deliveries of a webhook, newest first (requires group admin permission).
+// @Description A delivery is dead-lettered once it has failed WEBHOOK_MAX_ATTEMPTS times, or when it was still pending as its webhook got disabled. Results are paginated; pass next_cursor back as cursor to get the next page.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Group ID"
+// @Param webhook_id path string true "Webhook ID"
+// @Param limit query int false "Page size (1-100)" default(50)
+// @Param cursor query string false "Cursor from a previous page's next_cursor"
+// @Success 200 {object} object{items=[]models.WebhookFailure,next_cursor=string} "Returns a page of failed deliveries"
+// @Failure 400 {object} apierrors.AppError "BAD_REQUEST: Invalid webhook ID, limit or cursor"
+// @Failure 401 {object} apierrors.AppError "INVALID_TOKEN: Access token is invalid"
+// @Failure 403 {object} apierrors.AppError "EXPIRED_TOKEN: Access token has expired | NO_PERMISSIONS: User is not the group admin"
+// @Failure 404 {object} apierrors.AppError "WEBHOOK_NOT_FOUND: The group has no webhook with this ID"
+// @Failure 500 {object} apierrors.AppError "Internal server error - unexpected database error"
+// @Router /v1/groups/{id}/webhooks/{webhook_id}/failures [get]
+func (h *GroupsHandler) GetWebhookFailures(c *gin.Context) {
+	groupID := middleware.MustGetGroupID(c)
+
+	webhookID := middleware.MustGetUUIDParam(c, "webhook_id")
+
+	limit, cursor, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	failures, nextCursor, err := db.GetWebhookFailures(c.Request.Context(), h.pool, groupID, webhookID, limit, cursor)
+	if err != nil {
+		utils.SendError(c, apperrors.MapError(err, map[error]*apierrors.AppError{
+			db.ErrNotFound:     apierrors.ErrWebhookNotFound,
+			db.ErrInvalidInput: apierrors.ErrBadRequest,
+		}))
+		return
+	}
+
+	utils.SendPaginated(c, failures, nextCursor)
+}
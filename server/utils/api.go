@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -11,7 +13,10 @@ import (
 // SendError inspects the provided error and sends an appropriate JSON response.
 // This function differentiates between known application errors and unexpected errors.
 // Application errors are sent with their specific HTTP status codes and messages,
-// Generic errors result in a 500 Internal Server Error response.
+// a cancelled request context results in 499 and an expired one in 408, logged below error level,
+// database connection errors result in a 503 Service Unavailable response,
+// Generic errors result in a 500 Internal Server Error response, which carries the request ID
+// (if any) so it can be quoted when reporting the problem.
 func SendError(c *gin.Context, err error) {
 	// Check if the error is our custom AppError
 	if appErr, ok := err.(*apierrors.AppError); ok {
@@ -27,13 +32,48 @@ func SendError(c *gin.Context, err error) {
 		return
 	}
 
+	// A cancelled or timed out request context is not a server fault, so keep it out of the error logs.
+	// Genuine database errors never wrap these, so they still fall through below.
+	if errors.Is(err, context.Canceled) {
+		LogDebug(c.Request.Context(), "request cancelled by client", "error", err)
+		c.JSON(apierrors.ErrClientClosedRequest.HTTPCode, gin.H{
+			"code":    apierrors.ErrClientClosedRequest.MachineCode,
+			"message": apierrors.ErrClientClosedRequest.Message,
+		})
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		LogWarn(c.Request.Context(), "request deadline exceeded", "error", err)
+		c.JSON(apierrors.ErrRequestTimeout.HTTPCode, gin.H{
+			"code":    apierrors.ErrRequestTimeout.MachineCode,
+			"message": apierrors.ErrRequestTimeout.Message,
+		})
+		return
+	}
+
+	// Lost database connections and timed out queries are transient; tell the client to retry
+	if IsConnectionError(err) || IsStatementTimeout(err) {
+		LogWarn(c.Request.Context(), "database unavailable", "error", err)
+		c.JSON(apierrors.ErrServiceUnavailable.HTTPCode, gin.H{
+			"code":    apierrors.ErrServiceUnavailable.MachineCode,
+			"message": apierrors.ErrServiceUnavailable.Message,
+		})
+		return
+	}
+
 	// Handle unexpected/unknown errors (Panic recovery or generic errors)
+	// The logger tags the record with the request ID from the context
+	requestID := RequestIDFromContext(c.Request.Context())
 	LogError(c.Request.Context(), "internal server error", err)
 
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"code":    "INTERNAL_ERROR",
+	response := gin.H{
+		"code":    apierrors.CodeInternalServer,
 		"message": "Something went wrong on our end. Please report this.",
-	})
+	}
+	if requestID != "" {
+		response["request_id"] = requestID
+	}
+	c.JSON(http.StatusInternalServerError, response)
 }
 
 // SendAbort aborts the request and sends a JSON error response using the same
@@ -60,3 +100,16 @@ func SendOK(c *gin.Context, message string) {
 func SendData(c *gin.Context, data any) {
 	c.JSON(http.StatusOK, data)
 }
+
+// SendPaginated sends a page of results in the standard pagination envelope:
+// {"items": [...], "next_cursor": "..."}. next_cursor is null on the last page.
+func SendPaginated(c *gin.Context, items any, nextCursor string) {
+	var cursor *string
+	if nextCursor != "" {
+		cursor = &nextCursor
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"next_cursor": cursor,
+	})
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pranaovs/qashare/routes/apierrors"
+)
+
+func TestSendErrorMapsStatementTimeoutTo503(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// What pgx returns when Postgres cancels a query for exceeding statement_timeout, wrapped by a caller
+	err := fmt.Errorf("failed to get expenses: %w", &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"})
+	SendError(c, err)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+	if body.Code != string(apierrors.ErrServiceUnavailable.MachineCode) {
+		t.Fatalf("code = %q, want %q", body.Code, apierrors.ErrServiceUnavailable.MachineCode)
+	}
+}
+
+func TestSendPaginatedEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		items      any
+		nextCursor string
+		want       string
+	}{
+		{"more pages", []int{1, 2}, "abc", `{"items":[1,2],"next_cursor":"abc"}`},
+		{"last page", []int{3}, "", `{"items":[3],"next_cursor":null}`},
+		{"empty page", []int{}, "", `{"items":[],"next_cursor":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			SendPaginated(c, tt.items, tt.nextCursor)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -15,6 +21,51 @@ import (
 
 // Passwords
 
+// passwordMaxBytes is the longest password bcrypt accepts
+const passwordMaxBytes = 72
+
+// ValidatePassword checks a new password against the configured strength rules.
+// Lengths are counted in runes, so multi-byte characters count once towards the minimum;
+// the bcrypt limit of 72 bytes still applies on top of that.
+// Returns ErrInvalidPassword with the failed rule as its message.
+func ValidatePassword(password string, policy config.PasswordPolicy) error {
+	if !utf8.ValidString(password) {
+		return ErrInvalidPassword.Msg("password must be valid UTF-8")
+	}
+	// An empty password is never accepted, even with no minimum configured
+	minLength := max(policy.MinLength, 1)
+	if utf8.RuneCountInString(password) < minLength {
+		return ErrInvalidPassword.Msgf("password must be at least %d characters", minLength)
+	}
+	if len(password) > passwordMaxBytes {
+		return ErrInvalidPassword.Msgf("password must be at most %d bytes", passwordMaxBytes)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if policy.RequireMixedCase && (!upper || !lower) {
+		return ErrInvalidPassword.Msg("password must contain both upper and lower case letters")
+	}
+	if policy.RequireDigit && !digit {
+		return ErrInvalidPassword.Msg("password must contain a digit")
+	}
+	if policy.RequireSymbol && !symbol {
+		return ErrInvalidPassword.Msg("password must contain a symbol")
+	}
+	return nil
+}
+
 // HashPassword hashes a plaintext password using bcrypt.
 func HashPassword(password string) (string, error) {
 	if password == "" {
@@ -34,6 +85,29 @@ func CheckPassword(password, hashed string) bool {
 	return err == nil
 }
 
+// API keys
+
+// apiKeyPrefix makes API keys easy to recognise (e.g. by secret scanners).
+const apiKeyPrefix = "qsk_"
+
+// GenerateAPIKey creates a new random API key.
+// Returns the plaintext key (to hand to the client once) and its hash (to store).
+func GenerateAPIKey() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of an API key.
+// API keys are high-entropy random values, so a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
 func generateToken(userID uuid.UUID, tokenType models.TokenType, expiry time.Duration, jwtConfig config.JWTConfig) (string, uuid.UUID, time.Time, error) {
 	now := time.Now()
 	expiresAt := now.Add(expiry)
@@ -51,8 +125,7 @@ func generateToken(userID uuid.UUID, tokenType models.TokenType, expiry time.Dur
 		TokenType: tokenType,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(jwtConfig.Secret))
+	signed, err := signToken(claims, jwtConfig)
 	if err != nil {
 		return "", uuid.UUID{}, time.Time{}, err
 	}
@@ -80,17 +153,57 @@ func GenerateAccessToken(userID uuid.UUID, sessionID uuid.UUID, jwtConfig config
 		SessionID: sessionID.String(),
 	}
 
+	return signToken(claims, jwtConfig)
+}
+
+// JWT signing keys
+
+// jwtKeyID derives the kid header of a signing secret: a short SHA-256 fingerprint,
+// so operators only configure secrets and the ID cannot leak the secret itself.
+func jwtKeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// signToken signs claims with the active secret, naming it in the kid header.
+func signToken(claims models.TokenClaims, jwtConfig config.JWTConfig) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = jwtKeyID(jwtConfig.Secret)
 	return token.SignedString([]byte(jwtConfig.Secret))
 }
 
+// verificationKey returns the key to verify a token with.
+// A token naming a kid is verified with that secret, the active one or a retired one; an unknown kid is rejected.
+// Tokens issued before key IDs were introduced carry no kid and are tried against every configured secret, active first.
+func verificationKey(token *jwt.Token, jwtConfig config.JWTConfig) (any, error) {
+	secrets := append([]string{jwtConfig.Secret}, jwtConfig.RetiredSecrets...)
+
+	kid, hasKid := token.Header["kid"]
+	if !hasKid {
+		keys := make([]jwt.VerificationKey, len(secrets))
+		for i, secret := range secrets {
+			keys[i] = []byte(secret)
+		}
+		return jwt.VerificationKeySet{Keys: keys}, nil
+	}
+
+	if kid, ok := kid.(string); ok {
+		for _, secret := range secrets {
+			if jwtKeyID(secret) == kid {
+				return []byte(secret), nil
+			}
+		}
+	}
+	return nil, ErrInvalidToken.Msg("token signed with an unknown key")
+}
+
 func extractClaims(tokenString string, jwtConfig config.JWTConfig) (*models.TokenClaims, error) {
 	claims := &models.TokenClaims{}
 	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method")
 		}
-		return []byte(jwtConfig.Secret), nil
+		return verificationKey(token, jwtConfig)
 	},
 		jwt.WithIssuer(jwtConfig.Issuer),
 		jwt.WithAudience(jwtConfig.Audience),
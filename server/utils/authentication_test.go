@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pranaovs/qashare/config"
+)
+
+func TestValidatePassword(t *testing.T) {
+	defaultPolicy := config.PasswordPolicy{MinLength: 8}
+
+	tests := []struct {
+		name     string
+		password string
+		policy   config.PasswordPolicy
+		wantErr  bool
+	}{
+		{"one below minimum", "abcdefg", defaultPolicy, true},
+		{"exactly minimum", "abcdefgh", defaultPolicy, false},
+		{"one above minimum", "abcdefghi", defaultPolicy, false},
+		{"empty", "", defaultPolicy, true},
+		{"empty with no minimum", "", config.PasswordPolicy{}, true},
+
+		// Multi-byte runes count once each towards the minimum
+		{"multi-byte below minimum in runes", "ñéüöäßç", defaultPolicy, true},
+		{"multi-byte at minimum in runes", "ñéüöäßçø", defaultPolicy, false},
+		{"cjk at minimum in runes", "密码密码密码密码", defaultPolicy, false},
+		{"emoji below minimum in runes", "🔑🔑🔑🔑🔑🔑🔑", defaultPolicy, true},
+		{"invalid utf-8", "abcdefg\xff", defaultPolicy, true},
+
+		// bcrypt's limit is in bytes, regardless of the rune count
+		{"exactly 72 bytes", strings.Repeat("a", 72), defaultPolicy, false},
+		{"73 bytes", strings.Repeat("a", 73), defaultPolicy, true},
+		{"over 72 bytes in few runes", strings.Repeat("密", 25), defaultPolicy, true},
+
+		// Character classes
+		{"mixed case satisfied", "Abcdefgh", config.PasswordPolicy{MinLength: 8, RequireMixedCase: true}, false},
+		{"mixed case missing upper", "abcdefgh", config.PasswordPolicy{MinLength: 8, RequireMixedCase: true}, true},
+		{"mixed case missing lower", "ABCDEFGH", config.PasswordPolicy{MinLength: 8, RequireMixedCase: true}, true},
+		{"mixed case non-latin", "Ñandúñandú", config.PasswordPolicy{MinLength: 8, RequireMixedCase: true}, false},
+		{"digit satisfied", "abcdefg1", config.PasswordPolicy{MinLength: 8, RequireDigit: true}, false},
+		{"digit missing", "abcdefgh", config.PasswordPolicy{MinLength: 8, RequireDigit: true}, true},
+		{"symbol satisfied", "abcdefg!", config.PasswordPolicy{MinLength: 8, RequireSymbol: true}, false},
+		{"space counts as symbol", "abcd efg", config.PasswordPolicy{MinLength: 8, RequireSymbol: true}, false},
+		{"symbol missing", "abcdefgh", config.PasswordPolicy{MinLength: 8, RequireSymbol: true}, true},
+		{"all classes satisfied", "Abcdef1!", config.PasswordPolicy{MinLength: 8, RequireMixedCase: true, RequireDigit: true, RequireSymbol: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password, tt.policy)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPassword) {
+					t.Fatalf("ValidatePassword(%q) = %v, want ErrInvalidPassword", tt.password, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidatePassword(%q) = %v, want nil", tt.password, err)
+			}
+		})
+	}
+}
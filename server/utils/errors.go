@@ -68,10 +68,28 @@ func (e *UtilsError) GetMessage() string {
 
 // Sentinel errors for common validation operations
 var (
-	// ErrInvalidName indicates an invalid name format
-	ErrInvalidName = &UtilsError{
-		Code:    "INVALID_NAME",
-		Message: "invalid name format",
+	// ErrNameTooShort indicates a name that is empty or below the minimum length
+	ErrNameTooShort = &UtilsError{
+		Code:    "NAME_TOO_SHORT",
+		Message: "name is too short",
+	}
+
+	// ErrNameTooLong indicates a name that exceeds the maximum length
+	ErrNameTooLong = &UtilsError{
+		Code:    "NAME_TOO_LONG",
+		Message: "name is too long",
+	}
+
+	// ErrNameInvalidChars indicates a name containing disallowed characters
+	ErrNameInvalidChars = &UtilsError{
+		Code:    "NAME_INVALID_CHARS",
+		Message: "name contains invalid characters",
+	}
+
+	// ErrInvalidTitle indicates a missing or invalid expense title
+	ErrInvalidTitle = &UtilsError{
+		Code:    "INVALID_TITLE",
+		Message: "invalid title",
 	}
 
 	// ErrInvalidEmail indicates an invalid email format
@@ -92,6 +110,72 @@ var (
 		Message: "failed to hash password",
 	}
 
+	// ErrDescriptionTooLong indicates a description that exceeds the maximum length
+	ErrDescriptionTooLong = &UtilsError{
+		Code:    "DESCRIPTION_TOO_LONG",
+		Message: "description is too long",
+	}
+
+	// ErrInvalidCurrency indicates a currency code that is not a 3-letter ISO 4217 code
+	ErrInvalidCurrency = &UtilsError{
+		Code:    "INVALID_CURRENCY",
+		Message: "invalid currency code",
+	}
+
+	// ErrInvalidLocale indicates a locale that is not a well-formed BCP 47 language tag
+	ErrInvalidLocale = &UtilsError{
+		Code:    "INVALID_LOCALE",
+		Message: "invalid locale",
+	}
+
+	// ErrInvalidPaymentMethod indicates a payment method that is not in the allowed list
+	ErrInvalidPaymentMethod = &UtilsError{
+		Code:    "INVALID_PAYMENT_METHOD",
+		Message: "invalid payment method",
+	}
+
+	// ErrInvalidCategory indicates an expense category that is too long or spans multiple lines
+	ErrInvalidCategory = &UtilsError{
+		Code:    "INVALID_CATEGORY",
+		Message: "invalid category",
+	}
+
+	// ErrInvalidAttachment indicates attachment metadata with a bad reference, content type or size
+	ErrInvalidAttachment = &UtilsError{
+		Code:    "INVALID_ATTACHMENT",
+		Message: "invalid attachment",
+	}
+
+	// ErrInvalidSplit indicates splits that cannot be computed or do not add up
+	ErrInvalidSplit = &UtilsError{
+		Code:    "INVALID_SPLIT",
+		Message: "invalid expense splits",
+	}
+
+	// ErrInvalidTransactedAt indicates a transaction time too far in the future
+	ErrInvalidTransactedAt = &UtilsError{
+		Code:    "INVALID_TRANSACTED_AT",
+		Message: "invalid transaction time",
+	}
+
+	// ErrInvalidWebhook indicates an invalid webhook URL or event list
+	ErrInvalidWebhook = &UtilsError{
+		Code:    "INVALID_WEBHOOK",
+		Message: "invalid webhook",
+	}
+
+	// ErrWebhookDeliveryFailed indicates a webhook endpoint did not accept a delivery
+	ErrWebhookDeliveryFailed = &UtilsError{
+		Code:    "WEBHOOK_DELIVERY_FAILED",
+		Message: "webhook delivery failed",
+	}
+
+	// ErrInvalidCursor indicates a malformed pagination cursor
+	ErrInvalidCursor = &UtilsError{
+		Code:    "INVALID_CURSOR",
+		Message: "invalid pagination cursor",
+	}
+
 	// ErrInvalidToken indicates an invalid token
 	ErrInvalidToken = &UtilsError{
 		Code:    "INVALID_TOKEN",
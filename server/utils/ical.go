@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalEvent is an all-day calendar event with a reminder, written by WriteICal.
+type ICalEvent struct {
+	UID         string    // Stable identifier, so re-imports update the event instead of duplicating it
+	Date        time.Time // Day of the event; only the date part is used
+	Summary     string
+	Description string
+}
+
+// icalLineLimit is the maximum length of a content line in octets (RFC 5545, section 3.1).
+const icalLineLimit = 75
+
+// WriteICal writes events as an RFC 5545 iCalendar document.
+// Each event is an all-day VEVENT with a display alarm at the start of the day.
+func WriteICal(w io.Writer, prodID string, events []ICalEvent) error {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	writeICalLine(bw, "BEGIN:VCALENDAR")
+	writeICalLine(bw, "VERSION:2.0")
+	writeICalLine(bw, "PRODID:"+icalEscape(prodID))
+	writeICalLine(bw, "CALSCALE:GREGORIAN")
+	writeICalLine(bw, "METHOD:PUBLISH")
+	for _, event := range events {
+		writeICalLine(bw, "BEGIN:VEVENT")
+		writeICalLine(bw, "UID:"+icalEscape(event.UID))
+		writeICalLine(bw, "DTSTAMP:"+stamp)
+		writeICalLine(bw, "DTSTART;VALUE=DATE:"+event.Date.Format("20060102"))
+		writeICalLine(bw, "DTEND;VALUE=DATE:"+event.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeICalLine(bw, "SUMMARY:"+icalEscape(event.Summary))
+		if event.Description != "" {
+			writeICalLine(bw, "DESCRIPTION:"+icalEscape(event.Description))
+		}
+		writeICalLine(bw, "TRANSP:TRANSPARENT")
+		writeICalLine(bw, "BEGIN:VALARM")
+		writeICalLine(bw, "ACTION:DISPLAY")
+		writeICalLine(bw, "DESCRIPTION:"+icalEscape(event.Summary))
+		writeICalLine(bw, "TRIGGER:PT0S")
+		writeICalLine(bw, "END:VALARM")
+		writeICalLine(bw, "END:VEVENT")
+	}
+	writeICalLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// icalEscape escapes text values (RFC 5545, section 3.3.11).
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+// writeICalLine writes a content line terminated by CRLF, folding it so no line exceeds
+// icalLineLimit octets. Folds never split a multi-byte UTF-8 character.
+// Write errors are left to the final Flush.
+func writeICalLine(w *bufio.Writer, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icalLineLimit - 1
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -55,7 +55,7 @@ func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
 	return level >= minLevel
 }
 
-func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+func (h *prettyHandler) Handle(ctx context.Context, r slog.Record) error {
 	// Time: YYYY/MM/DD - HH:MM:SS
 	timeStr := r.Time.Format("2006/01/02 - 15:04:05")
 
@@ -93,6 +93,9 @@ func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
 		attrStr.WriteString(" " + formatAttr(h.group, a))
 		return true
 	})
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		attrStr.WriteString(" request_id=" + requestID)
+	}
 
 	line := fmt.Sprintf("%s |%s| %s%s%s\n", timeStr, levelStr, sourceStr, r.Message, attrStr.String())
 
@@ -187,6 +190,24 @@ func (w *slogWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// requestIDKey is the context key under which WithRequestID stores the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request's correlation ID.
+// Records logged with the context are tagged with it as request_id.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
 // Logger returns the global structured logger
 func Logger() *slog.Logger {
 	return logger
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pranaovs/qashare/models"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// MinorUnits is the number of minor currency units in one major unit (e.g. cents per dollar).
+const MinorUnits = 100
+
+// RoundMoney rounds an amount to the nearest minor currency unit.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*MinorUnits) / MinorUnits
+}
+
+// ToMinorUnits converts an amount to an integer number of minor currency units.
+func ToMinorUnits(amount float64) int64 {
+	return int64(math.Round(amount * MinorUnits))
+}
+
+// FromMinorUnits converts an integer number of minor currency units back to an amount.
+func FromMinorUnits(units int64) float64 {
+	return float64(units) / MinorUnits
+}
+
+// FormatMoney formats an amount with two decimals followed by its currency code, e.g. "1234.50 EUR".
+// With a locale the number uses its digit grouping and decimal separator instead, e.g. "1.234,50 EUR" for de-DE.
+func FormatMoney(amount float64, currency string, locale *string) string {
+	amount = RoundMoney(amount)
+	if locale == nil {
+		return strconv.FormatFloat(amount, 'f', 2, 64) + " " + currency
+	}
+	return message.NewPrinter(language.Make(*locale)).Sprintf("%.2f", amount) + " " + currency
+}
+
+// DistributeRemainder rounds every amount to the minor unit and assigns the
+// difference between total and their sum to the largest amount, so the result
+// sums exactly to total. The largest amount is picked before rounding, so a share
+// that is only larger by a fraction of a cent still gets the difference. Ties are
+// broken by the lowest index, which keeps the outcome deterministic for a given input order.
+// The input slice is not modified.
+func DistributeRemainder(amounts []float64, total float64) []float64 {
+	result := make([]float64, len(amounts))
+	if len(amounts) == 0 {
+		return result
+	}
+
+	units := make([]int64, len(amounts))
+	var sum int64
+	largest := 0
+	for i, amount := range amounts {
+		units[i] = ToMinorUnits(amount)
+		sum += units[i]
+		if amount > amounts[largest] {
+			largest = i
+		}
+	}
+
+	units[largest] += ToMinorUnits(total) - sum
+
+	for i, u := range units {
+		result[i] = FromMinorUnits(u)
+	}
+	return result
+}
+
+// AutoBalanceSplits nudges the owed splits so they sum exactly to amount.
+// They are only adjusted when the discrepancy is non-zero and no larger than
+// maxDiscrepancy; the difference is absorbed by the largest owed split (see
+// DistributeRemainder). Paid splits record what each payer actually paid and are
+// never rewritten, so a paid side that is off is left for split validation to reject.
+// Returns true if any split was changed.
+func AutoBalanceSplits(splits []models.ExpenseSplit, amount float64, maxDiscrepancy float64) bool {
+	indexes := make([]int, 0, len(splits))
+	amounts := make([]float64, 0, len(splits))
+	var total float64
+	for i, s := range splits {
+		if !s.IsPaid {
+			indexes = append(indexes, i)
+			amounts = append(amounts, s.Amount)
+			total += s.Amount
+		}
+	}
+
+	if len(indexes) == 0 {
+		return false
+	}
+
+	discrepancy := math.Abs(amount - total)
+	if ToMinorUnits(discrepancy) == 0 || discrepancy > maxDiscrepancy {
+		return false
+	}
+
+	changed := false
+	for j, balanced := range DistributeRemainder(amounts, amount) {
+		if splits[indexes[j]].Amount != balanced {
+			splits[indexes[j]].Amount = balanced
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RemainingAmount summarizes the owed splits of an expense and how much of the
+// amount is still unassigned. Sums are computed in minor units so the result
+// matches what the split validation sees.
+func RemainingAmount(expense models.ExpenseDetails) models.ExpenseRemaining {
+	owed := make([]models.ParticipantAmount, 0, len(expense.Splits))
+	var assigned int64
+	for _, split := range expense.Splits {
+		if split.IsPaid {
+			continue
+		}
+		assigned += ToMinorUnits(split.Amount)
+		owed = append(owed, models.ParticipantAmount{UserID: split.UserID, Amount: RoundMoney(split.Amount)})
+	}
+
+	return models.ExpenseRemaining{
+		ExpenseID: expense.ExpenseID,
+		Amount:    expense.Amount,
+		Assigned:  FromMinorUnits(assigned),
+		Remaining: FromMinorUnits(ToMinorUnits(expense.Amount) - assigned),
+		Owed:      owed,
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// EncodeCursor packs the sort key of the last item on a page into an opaque
+// cursor string that clients pass back to fetch the next page.
+func EncodeCursor(values ...string) string {
+	raw, _ := json.Marshal(values) // marshalling a []string cannot fail
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor unpacks a cursor created by EncodeCursor.
+// Returns ErrInvalidCursor if the cursor is malformed or does not hold exactly n values.
+func DecodeCursor(cursor string, n int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor.WithError(err)
+	}
+
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, ErrInvalidCursor.WithError(err)
+	}
+	if len(values) != n {
+		return nil, ErrInvalidCursor
+	}
+
+	return values, nil
+}
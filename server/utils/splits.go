@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/models"
+)
+
+// Split methods supported by ComputeSplits
+const (
+	SplitMethodExact      = "exact"      // Client supplies every split (default)
+	SplitMethodEqual      = "equal"      // Owed splits are shared equally among participants
+	SplitMethodPercentage = "percentage" // Owed splits follow per-user percentages
+	SplitMethodShares     = "shares"     // Owed splits are proportional to per-user integer shares, e.g. 2:1:1
+)
+
+// percentageTolerance is how far percentage weights may sum from 100.
+const percentageTolerance = 0.01
+
+// maxSplitShares is the largest share a single user may be given, which keeps
+// the minor unit arithmetic in SplitByShares well within int64.
+const maxSplitShares = 1_000_000
+
+// ComputeSplits materializes the full split set for an expense.
+//
+// For SplitMethodExact (or an empty method) the splits are returned unchanged.
+// For computed methods the client supplies only the paid splits; any owed splits
+// in the input are rejected, and the owed side is generated by the server:
+// from participants for SplitMethodEqual (see SplitEqually), from weights
+// for SplitMethodPercentage (see SplitByPercentage), and from shares for
+// SplitMethodShares (see SplitByShares).
+//
+// includePayers controls whether payers also owe a share under SplitMethodEqual.
+// When false, users with a paid split are dropped from participants, so they pay
+// but owe nothing. Percentage weights and shares always name the owing users explicitly.
+//
+// Returns ErrInvalidSplit if the method is unknown or the input cannot be split.
+func ComputeSplits(method string, amount float64, splits []models.ExpenseSplit, participants []uuid.UUID, weights map[uuid.UUID]float64, shares map[uuid.UUID]int, includePayers bool) ([]models.ExpenseSplit, error) {
+	switch method {
+	case "", SplitMethodExact:
+		return splits, nil
+	case SplitMethodEqual, SplitMethodPercentage, SplitMethodShares:
+	default:
+		return nil, ErrInvalidSplit.Msgf("unknown split method: %s", method)
+	}
+
+	if amount <= 0 {
+		return nil, ErrInvalidSplit.Msg("amount must be positive to compute splits")
+	}
+
+	paid := make([]models.ExpenseSplit, 0, len(splits))
+	for _, s := range splits {
+		if !s.IsPaid {
+			return nil, ErrInvalidSplit.Msgf("owed splits are computed by the server for the %s split method", method)
+		}
+		paid = append(paid, s)
+	}
+	if len(paid) == 0 {
+		return nil, ErrInvalidSplit.Msg("at least one payer is required")
+	}
+
+	if method == SplitMethodPercentage {
+		owed, err := SplitByPercentage(amount, weights)
+		if err != nil {
+			return nil, err
+		}
+		return append(paid, owed...), nil
+	}
+
+	if method == SplitMethodShares {
+		owed, err := SplitByShares(amount, shares)
+		if err != nil {
+			return nil, err
+		}
+		return append(paid, owed...), nil
+	}
+
+	participants = GetUniqueUserIDs(participants)
+	if len(participants) == 0 {
+		return nil, ErrInvalidSplit.Msg("no participants provided")
+	}
+
+	if !includePayers {
+		participants = slices.DeleteFunc(participants, func(userID uuid.UUID) bool {
+			return slices.ContainsFunc(paid, func(s models.ExpenseSplit) bool { return s.UserID == userID })
+		})
+		if len(participants) == 0 {
+			return nil, ErrInvalidSplit.Msg("no participants left to owe a share after excluding payers")
+		}
+	}
+
+	return append(paid, SplitEqually(amount, participants)...), nil
+}
+
+// SplitEqually divides total into equal owed splits for the given users.
+// The total is split in minor units; leftover units are handed out one at a time
+// starting from the first user, so the splits always sum exactly to total.
+func SplitEqually(total float64, userIDs []uuid.UUID) []models.ExpenseSplit {
+	splits := make([]models.ExpenseSplit, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return splits
+	}
+
+	units := ToMinorUnits(total)
+	count := int64(len(userIDs))
+	share := units / count
+	leftover := units % count
+
+	for i, userID := range userIDs {
+		amount := share
+		if int64(i) < leftover {
+			amount++
+		}
+		splits = append(splits, models.ExpenseSplit{
+			UserID: userID,
+			Amount: FromMinorUnits(amount),
+			IsPaid: false,
+		})
+	}
+
+	return splits
+}
+
+// SplitByPercentage divides total into owed splits according to weights, which map each
+// user to their percentage of the total and must sum to 100.
+// Users are processed in ascending ID order and the rounding remainder is given to the
+// largest share (see DistributeRemainder), so the splits always sum exactly to total in
+// minor units and the result does not depend on map iteration order.
+// Returns ErrInvalidSplit if weights are empty, not positive, or do not sum to 100.
+func SplitByPercentage(total float64, weights map[uuid.UUID]float64) ([]models.ExpenseSplit, error) {
+	if len(weights) == 0 {
+		return nil, ErrInvalidSplit.Msg("no percentage weights provided")
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(weights))
+	var sum float64
+	for userID, weight := range weights {
+		if userID == uuid.Nil {
+			return nil, ErrInvalidSplit.Msg("percentage weight is missing a user_id")
+		}
+		if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, ErrInvalidSplit.Msgf("percentage for user %s must be positive", userID)
+		}
+		userIDs = append(userIDs, userID)
+		sum += weight
+	}
+	if math.Abs(sum-100) > percentageTolerance {
+		return nil, ErrInvalidSplit.Msgf("percentages must sum to 100, got %.2f", sum)
+	}
+
+	slices.SortFunc(userIDs, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+
+	amounts := make([]float64, len(userIDs))
+	for i, userID := range userIDs {
+		amounts[i] = total * weights[userID] / 100
+	}
+	amounts = DistributeRemainder(amounts, total)
+
+	splits := make([]models.ExpenseSplit, 0, len(userIDs))
+	for i, userID := range userIDs {
+		splits = append(splits, models.ExpenseSplit{
+			UserID: userID,
+			Amount: amounts[i],
+			IsPaid: false,
+		})
+	}
+	return splits, nil
+}
+
+// SplitByShares divides total into owed splits proportional to shares, which map each
+// user to a positive integer share (e.g. 2:1:1 gives the first user half of the total).
+// The total is split in minor units with the largest remainder method: every user gets the
+// rounded-down proportional amount, and the leftover units go one at a time to the users with
+// the largest fractional remainder, ties broken by ascending user ID. The splits always sum
+// exactly to total and the result does not depend on map iteration order.
+// Returns ErrInvalidSplit if total is not positive, or shares are empty, not positive, or larger than maxSplitShares.
+func SplitByShares(total float64, shares map[uuid.UUID]int) ([]models.ExpenseSplit, error) {
+	if total <= 0 {
+		return nil, ErrInvalidSplit.Msg("amount must be positive to compute splits")
+	}
+	if len(shares) == 0 {
+		return nil, ErrInvalidSplit.Msg("no shares provided")
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(shares))
+	var sum int64
+	for userID, share := range shares {
+		if userID == uuid.Nil {
+			return nil, ErrInvalidSplit.Msg("share is missing a user_id")
+		}
+		if share <= 0 {
+			return nil, ErrInvalidSplit.Msgf("share for user %s must be positive", userID)
+		}
+		if share > maxSplitShares {
+			return nil, ErrInvalidSplit.Msgf("share for user %s must be at most %d", userID, maxSplitShares)
+		}
+		userIDs = append(userIDs, userID)
+		sum += int64(share)
+	}
+
+	slices.SortFunc(userIDs, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+
+	units := ToMinorUnits(total)
+	amounts := make([]int64, len(userIDs))
+	remainders := make([]int64, len(userIDs))
+	leftover := units
+	for i, userID := range userIDs {
+		weighted := units * int64(shares[userID])
+		amounts[i] = weighted / sum
+		remainders[i] = weighted % sum
+		leftover -= amounts[i]
+	}
+
+	// Hand out the leftover units by largest remainder; the stable sort keeps ID order on ties
+	order := make([]int, len(userIDs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(remainders[b], remainders[a]) })
+	for _, i := range order[:leftover] {
+		amounts[i]++
+	}
+
+	splits := make([]models.ExpenseSplit, 0, len(userIDs))
+	for i, userID := range userIDs {
+		splits = append(splits, models.ExpenseSplit{
+			UserID: userID,
+			Amount: FromMinorUnits(amounts[i]),
+			IsPaid: false,
+		})
+	}
+	return splits, nil
+}
+
+// ValidateSplits checks a split set against the expense amount.
+//
+// The rules apply to every write path (create, update and patch):
+//   - An expense always has at least one split; an empty list is rejected.
+//     (In a PATCH, omitting splits leaves them unchanged, but sending [] is an error.)
+//   - Every split names a user and has a positive amount.
+//   - A user appears at most once as a payer and at most once as owing. Multiple
+//     payers are supported, each with the amount they paid.
+//   - Unless the expense is flagged incomplete (amount or split), the paid splits
+//     and the owed splits must each sum to the amount within tolerance.
+//
+// Returns ErrInvalidSplit with the reason on failure. Errors about a single split
+// name the user it belongs to.
+func ValidateSplits(splits []models.ExpenseSplit, amount float64, tolerance float64, incompleteAmount, incompleteSplit bool) error {
+	if len(splits) == 0 {
+		return ErrInvalidSplit.Msg("no splits provided")
+	}
+
+	paidBy := make(map[uuid.UUID]bool)
+	owedBy := make(map[uuid.UUID]bool)
+	var paidTotal, owedTotal float64
+	for i, s := range splits {
+		if s.UserID == uuid.Nil {
+			return ErrInvalidSplit.Msgf("split %d has no user_id", i+1)
+		}
+
+		role, seen := "owed", owedBy
+		if s.IsPaid {
+			role, seen = "paid", paidBy
+		}
+		if s.Amount <= 0 {
+			return ErrInvalidSplit.Msgf("%s amount for user %s must be positive", role, s.UserID)
+		}
+		if seen[s.UserID] {
+			return ErrInvalidSplit.Msgf("user %s has more than one %s split; combine them into one", s.UserID, role)
+		}
+		seen[s.UserID] = true
+
+		if s.IsPaid {
+			paidTotal += s.Amount
+		} else {
+			owedTotal += s.Amount
+		}
+	}
+
+	if incompleteAmount || incompleteSplit {
+		return nil
+	}
+
+	if math.Abs(paidTotal-amount) > tolerance {
+		return ErrInvalidSplit.Msgf("paid splits from %d payer(s) total %.2f but the expense amount is %.2f", len(paidBy), paidTotal, amount)
+	}
+	if math.Abs(owedTotal-amount) > tolerance {
+		return ErrInvalidSplit.Msgf("owed splits from %d user(s) total %.2f but the expense amount is %.2f", len(owedBy), owedTotal, amount)
+	}
+
+	return nil
+}
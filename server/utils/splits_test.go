@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pranaovs/qashare/models"
+)
+
+// sortedUserIDs returns n fixed user IDs in the order the split helpers sort them.
+func sortedUserIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.MustParse("00000000-0000-0000-0000-00000000000" + string(rune('1'+i)))
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return strings.Compare(a.String(), b.String()) })
+	return ids
+}
+
+// assertSplits checks each split's amount and that the splits sum exactly to total in minor units.
+func assertSplits(t *testing.T, splits []models.ExpenseSplit, total float64, want map[uuid.UUID]float64) {
+	t.Helper()
+	if len(splits) != len(want) {
+		t.Fatalf("got %d splits, want %d", len(splits), len(want))
+	}
+	var sum int64
+	for _, split := range splits {
+		if split.IsPaid {
+			t.Errorf("split for %s is a paid split, want owed", split.UserID)
+		}
+		if split.Amount != want[split.UserID] {
+			t.Errorf("split for %s = %.2f, want %.2f", split.UserID, split.Amount, want[split.UserID])
+		}
+		sum += ToMinorUnits(split.Amount)
+	}
+	if sum != ToMinorUnits(total) {
+		t.Errorf("splits sum to %d minor units, want %d", sum, ToMinorUnits(total))
+	}
+}
+
+func TestSplitEquallyTenDollarsThreeWays(t *testing.T) {
+	ids := sortedUserIDs(3)
+
+	splits := SplitEqually(10, ids)
+
+	// The leftover cent goes to the first user
+	assertSplits(t, splits, 10, map[uuid.UUID]float64{ids[0]: 3.34, ids[1]: 3.33, ids[2]: 3.33})
+}
+
+func TestSplitEquallyNoUsers(t *testing.T) {
+	if splits := SplitEqually(10, nil); len(splits) != 0 {
+		t.Fatalf("got %d splits, want none", len(splits))
+	}
+}
+
+func TestSplitByPercentageTenDollarsThreeWays(t *testing.T) {
+	ids := sortedUserIDs(3)
+	weights := map[uuid.UUID]float64{ids[0]: 33.33, ids[1]: 33.33, ids[2]: 33.34}
+
+	splits, err := SplitByPercentage(10, weights)
+	if err != nil {
+		t.Fatalf("SplitByPercentage: %v", err)
+	}
+
+	// The rounding remainder goes to the largest share
+	assertSplits(t, splits, 10, map[uuid.UUID]float64{ids[0]: 3.33, ids[1]: 3.33, ids[2]: 3.34})
+}
+
+func TestSplitByPercentageRejectsInvalidWeights(t *testing.T) {
+	ids := sortedUserIDs(2)
+	tests := map[string]map[uuid.UUID]float64{
+		"empty":           {},
+		"not 100 percent": {ids[0]: 50, ids[1]: 40},
+		"zero weight":     {ids[0]: 100, ids[1]: 0},
+		"negative weight": {ids[0]: 110, ids[1]: -10},
+		"nil user":        {uuid.Nil: 100},
+	}
+	for name, weights := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := SplitByPercentage(10, weights); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestSplitBySharesOneDollarOneOneOne(t *testing.T) {
+	ids := sortedUserIDs(3)
+	shares := map[uuid.UUID]int{ids[0]: 1, ids[1]: 1, ids[2]: 1}
+
+	splits, err := SplitByShares(1, shares)
+	if err != nil {
+		t.Fatalf("SplitByShares: %v", err)
+	}
+
+	// 100 cents over 1:1:1 gives 34/33/33, with the leftover cent on the first user
+	assertSplits(t, splits, 1, map[uuid.UUID]float64{ids[0]: 0.34, ids[1]: 0.33, ids[2]: 0.33})
+}
+
+func TestSplitBySharesRatio(t *testing.T) {
+	ids := sortedUserIDs(3)
+	shares := map[uuid.UUID]int{ids[0]: 2, ids[1]: 1, ids[2]: 1}
+
+	splits, err := SplitByShares(10, shares)
+	if err != nil {
+		t.Fatalf("SplitByShares: %v", err)
+	}
+
+	assertSplits(t, splits, 10, map[uuid.UUID]float64{ids[0]: 5, ids[1]: 2.5, ids[2]: 2.5})
+}
+
+func TestSplitBySharesDeterministic(t *testing.T) {
+	ids := sortedUserIDs(5)
+
+	// Maps built in different insertion orders; Go also randomizes iteration order per range
+	forward := make(map[uuid.UUID]int)
+	for i, id := range ids {
+		forward[id] = i%2 + 1
+	}
+	backward := make(map[uuid.UUID]int)
+	for i := len(ids) - 1; i >= 0; i-- {
+		backward[ids[i]] = i%2 + 1
+	}
+
+	want, err := SplitByShares(10, forward)
+	if err != nil {
+		t.Fatalf("SplitByShares: %v", err)
+	}
+	for range 50 {
+		for _, shares := range []map[uuid.UUID]int{forward, backward} {
+			got, err := SplitByShares(10, shares)
+			if err != nil {
+				t.Fatalf("SplitByShares: %v", err)
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("SplitByShares = %v, want %v", got, want)
+			}
+		}
+	}
+}
+
+func TestSplitBySharesRejectsInvalidShares(t *testing.T) {
+	ids := sortedUserIDs(2)
+	tests := []struct {
+		name   string
+		total  float64
+		shares map[uuid.UUID]int
+	}{
+		{"zero share", 10, map[uuid.UUID]int{ids[0]: 1, ids[1]: 0}},
+		{"negative share", 10, map[uuid.UUID]int{ids[0]: 2, ids[1]: -1}},
+		{"too large share", 10, map[uuid.UUID]int{ids[0]: maxSplitShares + 1}},
+		{"no shares", 10, map[uuid.UUID]int{}},
+		{"nil user", 10, map[uuid.UUID]int{uuid.Nil: 1}},
+		{"zero total", 0, map[uuid.UUID]int{ids[0]: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SplitByShares(tt.total, tt.shares); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestValidateSplitsRejectsNegativeAmounts(t *testing.T) {
+	ids := sortedUserIDs(2)
+	tests := []struct {
+		name   string
+		splits []models.ExpenseSplit
+	}{
+		{"negative owed split", []models.ExpenseSplit{
+			{UserID: ids[0], Amount: 10, IsPaid: true},
+			{UserID: ids[0], Amount: 15},
+			{UserID: ids[1], Amount: -5},
+		}},
+		{"negative paid split", []models.ExpenseSplit{
+			{UserID: ids[0], Amount: 15, IsPaid: true},
+			{UserID: ids[1], Amount: -5, IsPaid: true},
+			{UserID: ids[0], Amount: 10},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Incomplete flags skip the totals, never the per-split checks
+			for _, incomplete := range []bool{false, true} {
+				err := ValidateSplits(tt.splits, 10, 0.01, incomplete, incomplete)
+				if !errors.Is(err, ErrInvalidSplit) {
+					t.Errorf("incomplete=%v: err = %v, want ErrInvalidSplit", incomplete, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSplitsAcceptsSettlement(t *testing.T) {
+	ids := sortedUserIDs(2)
+	// Settlements are stored as one paid and one owed split of the full amount
+	splits := []models.ExpenseSplit{
+		{UserID: ids[0], Amount: 12.5, IsPaid: true},
+		{UserID: ids[1], Amount: 12.5},
+	}
+
+	if err := ValidateSplits(splits, 12.5, 0.01, false, false); err != nil {
+		t.Fatalf("ValidateSplits = %v, want nil", err)
+	}
+}
+
+func TestValidateSplitsSkipsTotalsWhenIncomplete(t *testing.T) {
+	ids := sortedUserIDs(2)
+	// Paid 10 and owed 4 against an amount of 20: neither side balances
+	splits := []models.ExpenseSplit{
+		{UserID: ids[0], Amount: 10, IsPaid: true},
+		{UserID: ids[1], Amount: 4},
+	}
+	tests := []struct {
+		name                              string
+		incompleteAmount, incompleteSplit bool
+		wantErr                           bool
+	}{
+		{"complete", false, false, true},
+		{"incomplete amount", true, false, false},
+		{"incomplete split", false, true, false},
+		{"both incomplete", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSplits(splits, 20, 0.01, tt.incompleteAmount, tt.incompleteSplit)
+			if tt.wantErr && !errors.Is(err, ErrInvalidSplit) {
+				t.Errorf("err = %v, want ErrInvalidSplit", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestValidateSplitsRejectsUserPaidTwice(t *testing.T) {
+	ids := sortedUserIDs(2)
+	// Totals balance, but (expense, user, is_paid) is the splits table's key
+	splits := []models.ExpenseSplit{
+		{UserID: ids[0], Amount: 6, IsPaid: true},
+		{UserID: ids[0], Amount: 4, IsPaid: true},
+		{UserID: ids[1], Amount: 10},
+	}
+
+	for _, incomplete := range []bool{false, true} {
+		err := ValidateSplits(splits, 10, 0.01, incomplete, incomplete)
+		if !errors.Is(err, ErrInvalidSplit) {
+			t.Errorf("incomplete=%v: err = %v, want ErrInvalidSplit", incomplete, err)
+		}
+	}
+}
+
+func TestValidateSplitsThreePayersFourDebtors(t *testing.T) {
+	ids := sortedUserIDs(7)
+	splits := []models.ExpenseSplit{
+		{UserID: ids[0], Amount: 60, IsPaid: true},
+		{UserID: ids[1], Amount: 40, IsPaid: true},
+		{UserID: ids[2], Amount: 20, IsPaid: true},
+		{UserID: ids[3], Amount: 40},
+		{UserID: ids[4], Amount: 30},
+		{UserID: ids[5], Amount: 30},
+		{UserID: ids[6], Amount: 20},
+	}
+
+	if err := ValidateSplits(splits, 120, 0.01, false, false); err != nil {
+		t.Fatalf("ValidateSplits = %v, want nil", err)
+	}
+
+	// Problems with a single payer name that payer
+	bad := slices.Clone(splits)
+	bad[1].Amount = 0
+	err := ValidateSplits(bad, 120, 0.01, false, false)
+	if !errors.Is(err, ErrInvalidSplit) || !strings.Contains(err.Error(), ids[1].String()) {
+		t.Errorf("err = %v, want ErrInvalidSplit naming %s", err, ids[1])
+	}
+
+	// A paid total that misses the amount reports how many payers it came from
+	short := slices.Clone(splits)
+	short[2].Amount = 10
+	err = ValidateSplits(short, 120, 0.01, false, false)
+	if !errors.Is(err, ErrInvalidSplit) || !strings.Contains(err.Error(), "3 payer(s)") {
+		t.Errorf("err = %v, want ErrInvalidSplit about the 3 payers", err)
+	}
+}
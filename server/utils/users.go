@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/google/uuid"
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 // GetUniqueUserIDs extracts unique user IDs from a slice of user IDs.
 // This handles cases where the same user appears multiple times in splits
@@ -18,3 +22,12 @@ func GetUniqueUserIDs(userIDs []uuid.UUID) []uuid.UUID {
 
 	return unique
 }
+
+// JoinUserIDs formats user IDs as a comma-separated list for error messages.
+func JoinUserIDs(userIDs []uuid.UUID) string {
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+	return strings.Join(ids, ", ")
+}
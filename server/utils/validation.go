@@ -1,28 +1,270 @@
 package utils
 
 import (
+	"mime"
 	"net/mail"
+	neturl "net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
 )
 
-var nameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z .'\-]{1,62}[a-zA-Z]$`)
+// Name length limits, in runes
+const (
+	nameMinLength = 1
+	nameMaxLength = 100
+)
 
-// ValidateName validates a user's name.
+// ValidateName validates and normalizes a user or group name.
+// Surrounding whitespace is trimmed and internal runs of whitespace are collapsed to a single space.
+// Any script, accents and emoji are accepted; control characters and invisible formatting
+// characters are not, except the zero-width joiner and non-joiner that some scripts and emoji need.
+// Lengths are counted in runes, not bytes.
+// Each failure reason is reported with its own sentinel (ErrNameTooShort,
+// ErrNameTooLong, ErrNameInvalidChars) so callers can tell them apart.
 func ValidateName(name string) (string, error) {
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return "", ErrInvalidName.Msg("name cannot be empty")
+	if !utf8.ValidString(name) {
+		return "", ErrNameInvalidChars.Msg("name must be valid UTF-8")
 	}
-	if !nameRegex.MatchString(name) {
-		return "", ErrInvalidName.Msg("name must be 3-64 characters, start and end with a letter, and contain only letters, spaces, periods, apostrophes, and hyphens")
+
+	name = strings.Join(strings.Fields(name), " ")
+	length := utf8.RuneCountInString(name)
+	if length < nameMinLength {
+		return "", ErrNameTooShort.Msgf("name must be at least %d characters", nameMinLength)
+	}
+
+	visible := false
+	for _, r := range name {
+		switch {
+		case unicode.IsControl(r):
+			return "", ErrNameInvalidChars.Msg("name must not contain control characters")
+		case r == zeroWidthJoiner || r == zeroWidthNonJoiner:
+		case unicode.Is(unicode.Cf, r):
+			return "", ErrNameInvalidChars.Msgf("name must not contain invisible formatting characters (U+%04X)", r)
+		case !unicode.Is(unicode.Mn, r) && r != ' ':
+			visible = true
+		}
+	}
+	if !visible {
+		return "", ErrNameInvalidChars.Msg("name must contain at least one visible character")
+	}
+
+	if length > nameMaxLength {
+		return "", ErrNameTooLong.Msgf("name must be at most %d characters", nameMaxLength)
 	}
 	return name, nil
 }
 
+// Zero-width characters that are allowed in names: they shape text in scripts such as
+// Persian and Devanagari, and join emoji sequences.
+const (
+	zeroWidthNonJoiner = '\u200C'
+	zeroWidthJoiner    = '\u200D'
+)
+
+// ValidateExpenseTitle validates and trims an expense title.
+// Titles are required unless allowEmpty is set, in which case an empty title
+// is replaced with defaultTitle.
+func ValidateExpenseTitle(title string, allowEmpty bool, defaultTitle string) (string, error) {
+	title = strings.TrimSpace(title)
+	if title != "" {
+		return title, nil
+	}
+	if allowEmpty && defaultTitle != "" {
+		return defaultTitle, nil
+	}
+	return "", ErrInvalidTitle.Msg("title is required")
+}
+
+// SanitizeText normalizes free-form user text: line endings become "\n",
+// control characters other than newlines and tabs are dropped, invalid UTF-8
+// is removed and surrounding whitespace is trimmed.
+func SanitizeText(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Map(func(r rune) rune {
+		if r == '\r' {
+			return '\n'
+		}
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.TrimSpace(text)
+}
+
+// ValidateDescription sanitizes a description with SanitizeText and checks its length.
+// Empty descriptions are allowed. A maxLength of 0 disables the length check.
+func ValidateDescription(description string, maxLength int) (string, error) {
+	description = SanitizeText(description)
+	if maxLength > 0 && utf8.RuneCountInString(description) > maxLength {
+		return "", ErrDescriptionTooLong.Msgf("description must be at most %d characters", maxLength)
+	}
+	return description, nil
+}
+
+var currencyRegex = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidateCurrency validates and normalizes an ISO 4217 currency code.
+// Returns the trimmed, uppercase code or ErrInvalidCurrency.
+func ValidateCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyRegex.MatchString(code) {
+		return "", ErrInvalidCurrency.Msg("currency must be a 3-letter ISO 4217 code")
+	}
+	return code, nil
+}
+
+// ValidateOptionalCurrency validates an optional currency code with ValidateCurrency.
+// nil or empty means no currency is set and returns nil.
+func ValidateOptionalCurrency(code *string) (*string, error) {
+	code = trimOptional(code)
+	if code == nil {
+		return nil, nil
+	}
+	normalized, err := ValidateCurrency(*code)
+	if err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
+
+// ValidateLocale validates and canonicalizes an optional BCP 47 language tag, e.g. "en-us" becomes "en-US".
+// nil or empty means no locale is set and returns nil.
+func ValidateLocale(locale *string) (*string, error) {
+	locale = trimOptional(locale)
+	if locale == nil {
+		return nil, nil
+	}
+	tag, err := language.Parse(*locale)
+	if err != nil || tag == language.Und {
+		return nil, ErrInvalidLocale.Msg("locale must be a BCP 47 language tag such as en-US")
+	}
+	canonical := tag.String()
+	return &canonical, nil
+}
+
+// ValidatePaymentMethod validates and normalizes an optional payment method.
+// The method is trimmed and lowercased; nil or empty means no payment method.
+// When allowed is non-empty, the method must be one of its entries (case-insensitive).
+func ValidatePaymentMethod(method *string, allowed []string) (*string, error) {
+	if method == nil {
+		return nil, nil
+	}
+	normalized := strings.ToLower(strings.TrimSpace(*method))
+	if normalized == "" {
+		return nil, nil
+	}
+	if len(allowed) > 0 && !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, normalized) }) {
+		return nil, ErrInvalidPaymentMethod.Msgf("payment method must be one of: %s", strings.Join(allowed, ", "))
+	}
+	return &normalized, nil
+}
+
+// maxCategoryLength is the longest category name accepted, in characters.
+const maxCategoryLength = 50
+
+// ValidateCategory validates and normalizes an optional expense category.
+// The category is sanitized with SanitizeText and lowercased so the same category is always
+// grouped together; nil or empty means uncategorized.
+// When allowed is non-empty, the category must be one of its entries (case-insensitive).
+func ValidateCategory(category *string, allowed []string) (*string, error) {
+	if category == nil {
+		return nil, nil
+	}
+	normalized := strings.ToLower(SanitizeText(*category))
+	if normalized == "" {
+		return nil, nil
+	}
+	if strings.ContainsAny(normalized, "\n\t") {
+		return nil, ErrInvalidCategory.Msg("category must be a single line")
+	}
+	if utf8.RuneCountInString(normalized) > maxCategoryLength {
+		return nil, ErrInvalidCategory.Msgf("category must be at most %d characters", maxCategoryLength)
+	}
+	if len(allowed) > 0 && !slices.ContainsFunc(allowed, func(a string) bool { return strings.EqualFold(a, normalized) }) {
+		return nil, ErrInvalidCategory.Msgf("category must be one of: %s", strings.Join(allowed, ", "))
+	}
+	return &normalized, nil
+}
+
+// maxTransactedAtAhead is how far in the future a transaction time may be, to allow for
+// clients in time zones ahead of the server and small clock skew.
+const maxTransactedAtAhead = 24 * time.Hour
+
+// ValidateTransactedAt checks an optional transaction time in unix seconds.
+// nil means the transaction happened now. Times in the past are accepted as they are, but not times
+// more than a day ahead, which are almost always a client passing milliseconds or a wrong date.
+func ValidateTransactedAt(transactedAt *int64) error {
+	if transactedAt == nil {
+		return nil
+	}
+	if limit := time.Now().Add(maxTransactedAtAhead).Unix(); *transactedAt > limit {
+		return ErrInvalidTransactedAt.Msg("transacted_at must not be more than a day in the future")
+	}
+	return nil
+}
+
+// ValidateAttachment validates and normalizes the metadata of an expense attachment.
+// Exactly one of url (an absolute http or https URL) and objectKey must be set; empty strings count as unset.
+// The content type must be an image type or application/pdf, and is returned lowercased without parameters.
+// A maxSize of 0 disables the size limit.
+func ValidateAttachment(url, objectKey *string, contentType string, size, maxSize int64) (*string, *string, string, error) {
+	url = trimOptional(url)
+	objectKey = trimOptional(objectKey)
+	if (url == nil) == (objectKey == nil) {
+		return nil, nil, "", ErrInvalidAttachment.Msg("exactly one of url and object_key is required")
+	}
+	if url != nil {
+		parsed, err := neturl.Parse(*url)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, nil, "", ErrInvalidAttachment.Msg("url must be an absolute http or https URL")
+		}
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (!strings.HasPrefix(mediaType, "image/") && mediaType != "application/pdf") {
+		return nil, nil, "", ErrInvalidAttachment.Msg("content type must be an image or application/pdf")
+	}
+
+	if size < 0 {
+		return nil, nil, "", ErrInvalidAttachment.Msg("size must not be negative")
+	}
+	if maxSize > 0 && size > maxSize {
+		return nil, nil, "", ErrInvalidAttachment.Msgf("size must be at most %d bytes", maxSize)
+	}
+
+	return url, objectKey, mediaType, nil
+}
+
+// trimOptional trims an optional string, returning nil if it is nil or empty.
+func trimOptional(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*s)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 
+// maxEmailLength is the longest address that fits in an SMTP forward-path (RFC 5321).
+const maxEmailLength = 254
+
 // ValidateEmail validates and normalizes an email. Returns a cleaned, lowercase email string or an error.
+// Surrounding whitespace is trimmed and the whole address is lowercased, so "Foo@EXAMPLE.com " and
+// "foo@example.com" resolve to the same account on every endpoint that looks users up by email.
+// RFC 5321 allows the local part to be case-sensitive, but virtually no mail provider treats it that way,
+// and keeping it as typed would let the same inbox register twice under different spellings.
 func ValidateEmail(email string) (string, error) {
 	email = strings.TrimSpace(email)
 	email = strings.ToLower(email)
@@ -31,6 +273,15 @@ func ValidateEmail(email string) (string, error) {
 		return "", ErrInvalidEmail.Msg("email cannot be empty")
 	}
 
+	if len(email) > maxEmailLength {
+		return "", ErrInvalidEmail.Msgf("email cannot exceed %d characters", maxEmailLength)
+	}
+
+	local, domain, found := strings.Cut(email, "@")
+	if !found || local == "" || domain == "" {
+		return "", ErrInvalidEmail.Msg("email must have a local part and a domain")
+	}
+
 	if !emailRegex.MatchString(email) {
 		return "", ErrInvalidEmail.Msg("email does not match required format")
 	}
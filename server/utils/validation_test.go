@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		// Normalization
+		{"plain", "Alice", "Alice", nil},
+		{"surrounding whitespace trimmed", "  Alice \t", "Alice", nil},
+		{"internal whitespace collapsed", "Alice \t\n  Smith", "Alice Smith", nil},
+
+		// Unicode
+		{"accents", "José Müller", "José Müller", nil},
+		{"combining accent", "Jose\u0301", "Jose\u0301", nil},
+		{"cyrillic", "Анна", "Анна", nil},
+		{"cjk", "山田太郎", "山田太郎", nil},
+		{"arabic", "محمد", "محمد", nil},
+		{"emoji", "Trip 🏖️", "Trip 🏖️", nil},
+		{"emoji joined with zwj", "👩\u200D👩\u200D👧", "👩\u200D👩\u200D👧", nil},
+		{"persian with zwnj", "می\u200Cخواهم", "می\u200Cخواهم", nil},
+
+		// Zero-width and invisible characters
+		{"zero-width space", "Ali\u200Bce", "", ErrNameInvalidChars},
+		{"byte order mark", "\uFEFFAlice", "", ErrNameInvalidChars},
+		{"only a zero-width joiner", "\u200D", "", ErrNameInvalidChars},
+		{"only combining marks", "\u0301\u0301", "", ErrNameInvalidChars},
+		{"control character", "Ali\x07ce", "", ErrNameInvalidChars},
+		{"invalid utf-8", "Ali\xffce", "", ErrNameInvalidChars},
+
+		// Length, counted in runes
+		{"empty", "", "", ErrNameTooShort},
+		{"only whitespace", " \t\n ", "", ErrNameTooShort},
+		{"single rune", "A", "A", nil},
+		{"at maximum", strings.Repeat("a", nameMaxLength), strings.Repeat("a", nameMaxLength), nil},
+		{"over maximum", strings.Repeat("a", nameMaxLength+1), "", ErrNameTooLong},
+		{"multi-byte at maximum", strings.Repeat("é", nameMaxLength), strings.Repeat("é", nameMaxLength), nil},
+		{"emoji over maximum", strings.Repeat("🙂", nameMaxLength+1), "", ErrNameTooLong},
+		{"long before collapsing whitespace", "a" + strings.Repeat(" ", 200) + "b", "a b", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateName(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ValidateName(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateName(%q) error = %v, want nil", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Headers set on every webhook delivery
+const (
+	WebhookEventHeader     = "X-Qashare-Event"
+	WebhookDeliveryHeader  = "X-Qashare-Delivery"
+	WebhookSignatureHeader = "X-Qashare-Signature"
+)
+
+// WebhookTimeout bounds a single delivery attempt, including reading the response status
+const WebhookTimeout = 10 * time.Second
+
+// webhookDialer refuses connections to internal addresses. The check runs in Control, on the
+// address actually being dialled after DNS resolution, so a hostname that resolves (or is later
+// rebound) to an internal address is caught as well as a literal IP.
+var webhookDialer = &net.Dialer{
+	Timeout: WebhookTimeout,
+	Control: func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip, err := netip.ParseAddr(host)
+		if err != nil {
+			return fmt.Errorf("webhook target %s is not an IP address: %w", host, err)
+		}
+		if isInternalAddr(ip) {
+			return fmt.Errorf("webhook target %s is an internal address", ip)
+		}
+		return nil
+	},
+}
+
+var webhookClient = &http.Client{
+	Timeout: WebhookTimeout,
+	// No proxy: the dialer must see the real target address for its check to mean anything
+	Transport: &http.Transport{
+		DialContext:         webhookDialer.DialContext,
+		TLSHandshakeTimeout: WebhookTimeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	// Redirects are not followed, so a delivery only ever reaches the registered URL
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// isInternalAddr reports whether ip is an address webhooks must not reach: loopback, private,
+// link-local, unspecified or multicast. IPv4-mapped IPv6 addresses are checked as IPv4.
+func isInternalAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// ValidateWebhook validates and normalizes a webhook's target URL and event list.
+// The URL must be an absolute http or https URL. A literal internal IP or localhost is rejected
+// here; hostnames are checked again against their resolved address on every delivery.
+// Events must be non-empty and each one of allowed; duplicates are removed.
+func ValidateWebhook(url string, events, allowed []string) (string, []string, error) {
+	url = strings.TrimSpace(url)
+	parsed, err := neturl.Parse(url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", nil, ErrInvalidWebhook.Msg("url must be an absolute http or https URL")
+	}
+	if parsed.User != nil {
+		return "", nil, ErrInvalidWebhook.Msg("url must not contain credentials")
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", nil, ErrInvalidWebhook.Msg("url must not point to an internal address")
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && isInternalAddr(ip) {
+		return "", nil, ErrInvalidWebhook.Msg("url must not point to an internal address")
+	}
+
+	if len(events) == 0 {
+		return "", nil, ErrInvalidWebhook.Msg("at least one event is required")
+	}
+	unique := make([]string, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(allowed, event) {
+			return "", nil, ErrInvalidWebhook.Msgf("unknown event %q, must be one of: %s", event, strings.Join(allowed, ", "))
+		}
+		if !slices.Contains(unique, event) {
+			unique = append(unique, event)
+		}
+	}
+	return url, unique, nil
+}
+
+// GenerateWebhookSecret creates a random secret for signing webhook deliveries.
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// SignWebhookPayload returns the value of the signature header for a delivery body:
+// "sha256=" followed by the hex HMAC-SHA256 of the body, keyed with the webhook secret.
+// Receivers should recompute it over the raw body and compare in constant time.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SendWebhook POSTs a signed JSON body to a webhook URL.
+// Any response other than 2xx, including a redirect, counts as a failed delivery.
+func SendWebhook(ctx context.Context, url, secret, event string, deliveryID uuid.UUID, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return ErrWebhookDeliveryFailed.WithError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookDeliveryHeader, deliveryID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return ErrWebhookDeliveryFailed.WithError(err)
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return ErrWebhookDeliveryFailed.Msgf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateWebhookRejectsInternalTargets(t *testing.T) {
+	events := []string{"expense.created"}
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://api.localhost/hook",
+		"http://10.1.2.3/hook",
+		"http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://[fe80::1]/hook",
+	} {
+		t.Run(url, func(t *testing.T) {
+			if _, _, err := ValidateWebhook(url, events, events); !errors.Is(err, ErrInvalidWebhook) {
+				t.Fatalf("ValidateWebhook(%q) = %v, want ErrInvalidWebhook", url, err)
+			}
+		})
+	}
+
+	if _, _, err := ValidateWebhook("https://hooks.example.com/qashare", events, events); err != nil {
+		t.Fatalf("ValidateWebhook on a public host = %v, want nil", err)
+	}
+}
+
+func TestSendWebhookRefusesInternalAddress(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	// httptest listens on loopback, which the delivery dialer must refuse
+	err := SendWebhook(context.Background(), server.URL, "secret", "expense.created", uuid.New(), []byte(`{}`))
+	if !errors.Is(err, ErrWebhookDeliveryFailed) {
+		t.Fatalf("SendWebhook = %v, want ErrWebhookDeliveryFailed", err)
+	}
+	if reached {
+		t.Fatal("delivery reached a loopback server")
+	}
+}